@@ -52,7 +52,10 @@ func oldCli() {
 			log.Println(recoveredSecret)
 		}
 	} else if *examplePtr == "dlog_equality" {
-		dlog := config.LoadDLog("pseudonymsys")
+		dlog, err := config.LoadDLog("pseudonymsys")
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		secret := big.NewInt(213412)
 		groupOrder := new(big.Int).Sub(dlog.P, big.NewInt(1))
@@ -65,7 +68,10 @@ func oldCli() {
 		log.Println(proved)
 
 	} else if *examplePtr == "dlog_equality_blinded_transcript" {
-		dlog := config.LoadDLog("pseudonymsys")
+		dlog, err := config.LoadDLog("pseudonymsys")
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		// no wrappers at the moment, because messages handling will be refactored
 		eProver := dlogproofs.NewDLogEqualityBTranscriptProver(dlog)
@@ -95,9 +101,15 @@ func oldCli() {
 		orgName2 := "org2"
 		userName := "user1"
 		caName := "ca"
-		dlog := config.LoadDLog("pseudonymsys")
+		dlog, err := config.LoadDLog("pseudonymsys")
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		userSecret := config.LoadPseudonymsysUserSecret(userName)
+		userSecret, err := config.LoadPseudonymsysUserSecret(userName)
+		if err != nil {
+			log.Fatal(err)
+		}
 		p, _ := dlog.Exponentiate(dlog.G, userSecret)
 		masterNym := pseudonymsys.Pseudonym{A: dlog.G, B: p}
 		blindedA, blindedB, r, s, err := pseudonymsys.RegisterWithCA(caName, userSecret, masterNym, dlog)
@@ -107,10 +119,16 @@ func oldCli() {
 		log.Println(s)
 
 		orgPubKeys := make(map[string]*pseudonymsys.OrgPubKeys)
-		h11, h12 := config.LoadPseudonymsysOrgPubKeys(orgName1)
+		h11, h12, err := config.LoadPseudonymsysOrgPubKeys(orgName1)
+		if err != nil {
+			log.Fatal(err)
+		}
 		orgPubKeys[orgName1] = &pseudonymsys.OrgPubKeys{H1: h11, H2: h12}
 
-		h21, h22 := config.LoadPseudonymsysOrgPubKeys(orgName1)
+		h21, h22, err := config.LoadPseudonymsysOrgPubKeys(orgName1)
+		if err != nil {
+			log.Fatal(err)
+		}
 		orgPubKeys[orgName2] = &pseudonymsys.OrgPubKeys{H1: h21, H2: h22}
 
 		// register with orgName1
@@ -135,7 +153,10 @@ func oldCli() {
 		//credentials[orgName1] = credential
 
 		// register with orgName2
-		nym2 := pseudonymsys.GenerateNym(userSecret, orgName2, dlog)
+		nym2, err := pseudonymsys.GenerateNym(userSecret, orgName2, dlog)
+		if err != nil {
+			log.Fatal(err)
+		}
 		nyms[orgName2] = nym2
 
 		authenticated, _ := pseudonymsys.TransferCredential(userSecret, credential, nym2,
@@ -2,11 +2,10 @@ package client
 
 import (
 	"fmt"
-	"github.com/xlab-si/emmy/config"
 	"github.com/xlab-si/emmy/log"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc"
 	"io"
 	"math/rand"
 	"time"
@@ -15,38 +14,32 @@ import (
 var logger = log.ClientLogger
 
 type genericClient struct {
-	id     int32
-	conn   *grpc.ClientConn
-	stream pb.Protocol_RunClient
+	id               int32
+	transport        transport.Transport
+	recordTranscript bool
+	result           *Result // result of the current protocol execution
 }
 
-func newGenericClient(endpoint string) (*genericClient, error) {
-	conn, err := getConnection(endpoint)
-	if err != nil {
-		return nil, err
-	}
-
-	logger.Debug("Creating the client")
-	client := pb.NewProtocolClient(conn)
-	stream, err := getStream(client)
-	if err != nil {
-		return nil, err
-	}
-
+func newGenericClient(t transport.Transport) *genericClient {
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	genClient := genericClient{
-		id:     rand.Int31(),
-		conn:   conn,
-		stream: stream,
+		id:        rand.Int31(),
+		transport: t,
 	}
 
 	logger.Infof("New GenericClient spawned (%v)", genClient.id)
-	return &genClient, nil
+	return &genClient
+}
+
+// RecordTranscript sets whether messages exchanged with emmy server are recorded in the
+// transcript of the Result returned by RunWithContext.
+func (c *genericClient) RecordTranscript(record bool) {
+	c.recordTranscript = record
 }
 
-func (c *genericClient) send(msg *pb.Message) error {
-	if err := c.stream.Send(msg); err != nil {
+func (c *genericClient) send(ctx context.Context, msg *pb.Message) error {
+	if err := c.transport.Send(ctx, msg); err != nil {
 		return fmt.Errorf("[Client %v] Error sending message: %v", c.id, err)
 	}
 	logger.Infof("[Client %v] Successfully sent request:", c.id, msg)
@@ -54,56 +47,68 @@ func (c *genericClient) send(msg *pb.Message) error {
 	return nil
 }
 
-func (c *genericClient) receive() (*pb.Message, error) {
-	resp, err := c.stream.Recv()
+func (c *genericClient) receive(ctx context.Context) (*pb.Message, error) {
+	resp, err := c.transport.Receive(ctx)
 	if err == io.EOF {
 		return nil, fmt.Errorf("[Client %v] EOF error", c.id)
 	} else if err != nil {
 		return nil, fmt.Errorf("[Client %v] An error ocurred: %v", c.id, err)
 	}
-	logger.Infof("[Client %v] Received response from the stream: %v", c.id, resp)
+	logger.Infof("[Client %v] Received response from the transport: %v", c.id, resp)
 	return resp, nil
 }
 
 // getResponseTo sends a message msg to emmy server and retrieves the server's response.
-func (c *genericClient) getResponseTo(msg *pb.Message) (*pb.Message, error) {
-	if err := c.send(msg); err != nil {
+// The exchange is recorded as a step of the current protocol execution.
+func (c *genericClient) getResponseTo(ctx context.Context, msg *pb.Message) (*pb.Message, error) {
+	start := time.Now()
+	if err := c.send(ctx, msg); err != nil {
+		return nil, err
+	}
+	resp, err := c.receive(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return c.receive()
-}
 
-// close closes the communication stream and connection to the server.
-func (c *genericClient) close() error {
-	if err := c.stream.CloseSend(); err != nil {
-		return fmt.Errorf("[Client %v] Error closing stream: %v", c.id, err)
+	c.result.Timings = append(c.result.Timings, StepTiming{
+		Step:     stepName(msg),
+		Duration: time.Since(start),
+	})
+	if c.recordTranscript {
+		c.result.Transcript = append(c.result.Transcript, msg, resp)
 	}
-	if err := c.conn.Close(); err != nil {
-		return fmt.Errorf("[Client %v] Error closing connection: %v", c.id, err)
+	if status := resp.GetStatus(); status != nil {
+		c.result.Verified = status.Success
+		c.result.FailureReason = status.Reason
 	}
-	return nil
+	return resp, nil
 }
 
-func getConnection(serverEndpoint string) (*grpc.ClientConn, error) {
-	logger.Debug("Getting the connection")
-	timeoutSec := config.LoadTimeout()
-	dialOptions := []grpc.DialOption{
-		grpc.WithInsecure(),
-		grpc.WithBlock(),
-		grpc.WithTimeout(time.Duration(timeoutSec) * time.Second),
-	}
-	conn, err := grpc.Dial(serverEndpoint, dialOptions...)
+// startRun prepares the client for a new protocol execution.
+func (c *genericClient) startRun() {
+	c.result = &Result{SessionId: c.id}
+}
+
+// finishRun completes the result of the protocol execution that ended with err.
+// If ctx was done in the meantime, the transport is closed and ctx.Err() is
+// reported instead of the error caused by aborting the execution.
+func (c *genericClient) finishRun(ctx context.Context, err error) (*Result, error) {
 	if err != nil {
-		return nil, fmt.Errorf("Could not connect to server %v (%v)", serverEndpoint, err)
+		if ctx.Err() != nil {
+			c.transport.Close()
+			err = fmt.Errorf("[Client %v] Protocol execution aborted: %v", c.id, ctx.Err())
+		}
+		if c.result.FailureReason == "" {
+			c.result.FailureReason = err.Error()
+		}
 	}
-	return conn, nil
+	return c.result, err
 }
 
-func getStream(client pb.ProtocolClient) (pb.Protocol_RunClient, error) {
-	logger.Debug("Getting the stream")
-	stream, err := client.Run(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("Error creating the stream: %v", err)
+// close closes the transport used for communication with the server.
+func (c *genericClient) close() error {
+	if err := c.transport.Close(); err != nil {
+		return fmt.Errorf("[Client %v] Error closing transport: %v", c.id, err)
 	}
-	return stream, nil
+	return nil
 }
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/transport"
+	"google.golang.org/grpc"
+)
+
+// Connection is a connection to emmy server that can be shared by many clients.
+// Each protocol execution runs on its own stream, so that clients proving repeatedly
+// (or concurrently) do not need to establish a new connection for every proof.
+type Connection struct {
+	conn *grpc.ClientConn
+}
+
+// NewConnection connects to emmy server at the given endpoint.
+func NewConnection(endpoint string) (*Connection, error) {
+	conn, err := transport.ConnectGRPC(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Connected to emmy server at %v", endpoint)
+	return &Connection{conn: conn}, nil
+}
+
+// NewTransport opens a new stream on the connection and returns a transport using it.
+// The returned transport can be passed to any client constructor. Closing it closes
+// only the stream, while the connection remains open for other protocol executions.
+func (c *Connection) NewTransport() (transport.Transport, error) {
+	return transport.NewGRPCClientTransport(c.conn)
+}
+
+// Close closes the connection to emmy server. Protocol executions still running on
+// the connection are aborted.
+func (c *Connection) Close() error {
+	if err := c.conn.Close(); err != nil {
+		return fmt.Errorf("Error closing connection: %v", err)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"fmt"
 	"github.com/xlab-si/emmy/encryption"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
 	"math/big"
 )
 
@@ -10,42 +13,93 @@ type CSPaillierClient struct {
 	genericClient
 	encryptor *encryption.CSPaillier
 	label, m  *big.Int
+	keyId     string
 }
 
-// NewCSPaillierClient returns an initialized struct of type CSPaillierClient.
-func NewCSPaillierClient(endpoint string, pubKeyPath string, m, l *big.Int) (*CSPaillierClient, error) {
-	genericClient, err := newGenericClient(endpoint)
-	if err != nil {
-		return nil, err
-	}
-
-	encryptor, err := encryption.NewCSPaillierFromPubKeyFile(pubKeyPath)
-	if err != nil {
-		return nil, err
+// NewCSPaillierClient returns an initialized struct of type CSPaillierClient, using the
+// public key stored at pubKeyPath. If pubKeyPath is empty, the client instead fetches
+// the public key from emmy server at the beginning of the protocol. Note that the
+// fetched key is then only as trustworthy as the connection to emmy server.
+func NewCSPaillierClient(t transport.Transport, pubKeyPath string, m, l *big.Int) (*CSPaillierClient, error) {
+	var encryptor *encryption.CSPaillier
+	if pubKeyPath != "" {
+		var err error
+		encryptor, err = encryption.NewCSPaillierFromPubKeyFile(pubKeyPath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &CSPaillierClient{
-		genericClient: *genericClient,
+		genericClient: *newGenericClient(t),
 		encryptor:     encryptor,
 		m:             m,
 		label:         l,
 	}, nil
 }
 
+// SetKeyId sets the ID of the server's key that the public key of the client corresponds
+// to. If it is not set, the server uses its default key.
+func (c *CSPaillierClient) SetKeyId(keyId string) {
+	c.keyId = keyId
+}
+
 // Run runs the Camenisch-Shoup sigma protocol for verifiable encryption and decryption
 // of discrete logatirhms.
 func (c *CSPaillierClient) Run() error {
+	_, err := c.RunWithContext(context.Background())
+	return err
+}
+
+// RunWithContext runs the protocol just like Run, but aborts the protocol execution
+// when ctx is done. It returns the result of the protocol execution.
+func (c *CSPaillierClient) RunWithContext(ctx context.Context) (*Result, error) {
+	c.startRun()
+	err := c.run(ctx)
+	return c.finishRun(ctx, err)
+}
+
+// FetchCSPaillierPubKey obtains the public key with the given ID (empty for the default
+// key) from emmy server, using transport t, which is closed afterwards.
+func FetchCSPaillierPubKey(ctx context.Context, t transport.Transport, keyId string) (
+	*encryption.CSPaillierPubKey, error) {
+	c := &CSPaillierClient{
+		genericClient: *newGenericClient(t),
+		keyId:         keyId,
+	}
+	c.startRun()
+	pubKey, err := c.getPubKey(ctx)
+	if err != nil {
+		_, err = c.finishRun(ctx, err)
+		return nil, err
+	}
+	if err := c.close(); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
+
+// run executes the steps of the protocol.
+func (c *CSPaillierClient) run(ctx context.Context) error {
+	if c.encryptor == nil {
+		pubKey, err := c.getPubKey(ctx)
+		if err != nil {
+			return err
+		}
+		c.encryptor = encryption.NewCSPaillierFromPubKey(pubKey)
+	}
+
 	u, e, v, _ := c.encryptor.Encrypt(c.m, c.label)
-	if err := c.open(u, e, v); err != nil {
+	if err := c.open(ctx, u, e, v); err != nil {
 		return err
 	}
 
-	challenge, err := c.getProofRandomData(u, e)
+	challenge, err := c.getProofRandomData(ctx, u, e)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.getProofData(challenge)
+	_, err = c.getProofData(ctx, challenge)
 	if err != nil {
 		return err
 	}
@@ -57,7 +111,30 @@ func (c *CSPaillierClient) Run() error {
 	return nil
 }
 
-func (c *CSPaillierClient) open(u, e, v *big.Int) error {
+// getPubKey asks emmy server for its public key.
+func (c *CSPaillierClient) getPubKey(ctx context.Context) (*encryption.CSPaillierPubKey, error) {
+	req := &pb.Message{
+		ClientId: c.id,
+		Schema:   pb.SchemaType_CSPAILLIER,
+		KeyId:    c.keyId,
+		Content:  &pb.Message_Empty{&pb.EmptyMsg{}},
+	}
+	resp, err := c.getResponseTo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pKey := resp.GetCsPaillierPubKey()
+	if pKey == nil {
+		return nil, fmt.Errorf("[Client %v] Emmy server did not send a public key", c.id)
+	}
+	if resp.GetKeyId() != c.keyId {
+		return nil, fmt.Errorf("[Client %v] Emmy server sent key %q instead of %q", c.id, resp.GetKeyId(), c.keyId)
+	}
+	return encryption.ToCSPaillierPubKey(pKey), nil
+}
+
+func (c *CSPaillierClient) open(ctx context.Context, u, e, v *big.Int) error {
 	l, delta := c.encryptor.GetOpeningMsg(c.m)
 
 	opening := pb.CSPaillierOpening{
@@ -71,17 +148,18 @@ func (c *CSPaillierClient) open(u, e, v *big.Int) error {
 	openMsg := &pb.Message{
 		ClientId: c.id,
 		Schema:   pb.SchemaType_CSPAILLIER,
+		KeyId:    c.keyId,
 		Content:  &pb.Message_CsPaillierOpening{&opening},
 	}
 
-	if _, err := c.getResponseTo(openMsg); err != nil {
+	if _, err := c.getResponseTo(ctx, openMsg); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *CSPaillierClient) getProofRandomData(u, e *big.Int) (*big.Int, error) {
+func (c *CSPaillierClient) getProofRandomData(ctx context.Context, u, e *big.Int) (*big.Int, error) {
 	u1, e1, v1, delta1, l1, err := c.encryptor.GetProofRandomData(u, e, c.label)
 	if err != nil {
 		return nil, err
@@ -98,7 +176,7 @@ func (c *CSPaillierClient) getProofRandomData(u, e *big.Int) (*big.Int, error) {
 		Content: &pb.Message_CsPaillierProofRandomData{&data},
 	}
 
-	resp, err := c.getResponseTo(msg)
+	resp, err := c.getResponseTo(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +185,7 @@ func (c *CSPaillierClient) getProofRandomData(u, e *big.Int) (*big.Int, error) {
 	return new(big.Int).SetBytes(bigint.X1), nil
 }
 
-func (c *CSPaillierClient) getProofData(challenge *big.Int) (bool, error) {
+func (c *CSPaillierClient) getProofData(ctx context.Context, challenge *big.Int) (bool, error) {
 	rTilde, sTilde, mTilde := c.encryptor.GetProofData(challenge)
 
 	data := pb.CSPaillierProofData{
@@ -122,7 +200,7 @@ func (c *CSPaillierClient) getProofData(challenge *big.Int) (bool, error) {
 		Content: &pb.Message_CsPaillierProofData{&data},
 	}
 
-	resp, err := c.getResponseTo(msg)
+	resp, err := c.getResponseTo(ctx, msg)
 	if err != nil {
 		return false, err
 	}
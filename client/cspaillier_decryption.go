@@ -0,0 +1,100 @@
+package client
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
+	"math/big"
+)
+
+// CSPaillierDecryptionClient asks emmy server, acting as a trustee, to decrypt an
+// escrowed CSPaillier ciphertext (see trustee package).
+type CSPaillierDecryptionClient struct {
+	genericClient
+	verifier      *encryption.CSPaillier
+	u, e, v       *big.Int
+	label         *big.Int
+	justification string
+	keyId         string
+}
+
+// NewCSPaillierDecryptionClient returns an initialized struct of type
+// CSPaillierDecryptionClient for the ciphertext (u, e, v) with the given label.
+// The justification of the request is recorded in the trustee's audit log. pubKey is
+// used to verify that the ciphertext was decrypted correctly.
+func NewCSPaillierDecryptionClient(t transport.Transport, pubKey *encryption.CSPaillierPubKey,
+	u, e, v, label *big.Int, justification string) *CSPaillierDecryptionClient {
+	return &CSPaillierDecryptionClient{
+		genericClient: *newGenericClient(t),
+		verifier:      encryption.NewCSPaillierFromPubKey(pubKey),
+		u:             u,
+		e:             e,
+		v:             v,
+		label:         label,
+		justification: justification,
+	}
+}
+
+// SetKeyId sets the ID of the server's key that the ciphertext was encrypted under.
+// If it is not set, the server uses its default key.
+func (c *CSPaillierDecryptionClient) SetKeyId(keyId string) {
+	c.keyId = keyId
+}
+
+// Decrypt sends the decryption request to emmy server and returns the plaintext,
+// once it verifies server's proof of correct decryption. It returns an error if the
+// request was not granted or the proof is not valid.
+func (c *CSPaillierDecryptionClient) Decrypt(ctx context.Context) (*big.Int, error) {
+	c.startRun()
+	m, err := c.decrypt(ctx)
+	if _, err := c.finishRun(ctx, err); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *CSPaillierDecryptionClient) decrypt(ctx context.Context) (*big.Int, error) {
+	req := &pb.Message{
+		ClientId: c.id,
+		Schema:   pb.SchemaType_CSPAILLIER_DECRYPTION,
+		KeyId:    c.keyId,
+		Content: &pb.Message_CsPaillierDecryptionRequest{
+			&pb.CSPaillierDecryptionRequest{
+				U:             c.u.Bytes(),
+				E:             c.e.Bytes(),
+				V:             c.v.Bytes(),
+				Label:         c.label.Bytes(),
+				Justification: c.justification,
+			},
+		},
+	}
+	resp, err := c.getResponseTo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.close(); err != nil {
+		return nil, err
+	}
+
+	if status := resp.GetStatus(); status != nil {
+		return nil, fmt.Errorf("[Client %v] Decryption request was not granted: %v", c.id, status.Reason)
+	}
+	decryption := resp.GetCsPaillierDecryption()
+	if decryption == nil {
+		return nil, fmt.Errorf("[Client %v] Emmy server did not send the plaintext", c.id)
+	}
+
+	m := new(big.Int).SetBytes(decryption.M)
+	proof := &encryption.CSPaillierDecryptionProof{
+		T1: new(big.Int).SetBytes(decryption.T1),
+		T2: new(big.Int).SetBytes(decryption.T2),
+		Z:  new(big.Int).SetBytes(decryption.Z),
+	}
+	if !c.verifier.VerifyDecryption(c.u, c.e, c.v, c.label, m, proof) {
+		return nil, fmt.Errorf("[Client %v] Proof of correct decryption is not valid", c.id)
+	}
+	c.result.Verified = true
+	return m, nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/dlogproofs"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
+	"math/big"
+)
+
+// ElGamalDecryptionShareClient asks emmy server, acting as one of the trustees of a
+// threshold ElGamal key, for its decryption share of a ciphertext (see trustee package).
+// Shares collected from threshold trustees are combined into the plaintext with
+// encryption.ThresholdElGamalPubKey.Combine (or CombineExponential).
+type ElGamalDecryptionShareClient struct {
+	genericClient
+	pubKey        *encryption.ThresholdElGamalPubKey
+	c             *encryption.ElGamalCiphertext
+	label         *big.Int
+	justification string
+}
+
+// NewElGamalDecryptionShareClient returns an initialized struct of type
+// ElGamalDecryptionShareClient for ciphertext c, requested on behalf of the case with
+// the given label (see trustee.Label). The justification of the request is recorded in
+// the trustee's audit log. pubKey is used to verify the decryption share.
+func NewElGamalDecryptionShareClient(t transport.Transport, pubKey *encryption.ThresholdElGamalPubKey,
+	c *encryption.ElGamalCiphertext, label *big.Int, justification string) *ElGamalDecryptionShareClient {
+	return &ElGamalDecryptionShareClient{
+		genericClient: *newGenericClient(t),
+		pubKey:        pubKey,
+		c:             c,
+		label:         label,
+		justification: justification,
+	}
+}
+
+// GetShare sends the request to emmy server and returns its decryption share, once it
+// verifies the proof of its correctness. It returns an error if the request was not
+// granted or the proof is not valid.
+func (c *ElGamalDecryptionShareClient) GetShare(ctx context.Context) (*encryption.ElGamalDecryptionShare, error) {
+	c.startRun()
+	share, err := c.getShare(ctx)
+	if _, err := c.finishRun(ctx, err); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (c *ElGamalDecryptionShareClient) getShare(ctx context.Context) (*encryption.ElGamalDecryptionShare, error) {
+	req := &pb.Message{
+		ClientId: c.id,
+		Schema:   pb.SchemaType_ELGAMAL_DECRYPTION_SHARE,
+		Content: &pb.Message_ElGamalDecryptionShareRequest{
+			&pb.ElGamalDecryptionShareRequest{
+				C1:            c.c.C1.Bytes(),
+				C2:            c.c.C2.Bytes(),
+				Label:         c.label.Bytes(),
+				Justification: c.justification,
+			},
+		},
+	}
+	resp, err := c.getResponseTo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.close(); err != nil {
+		return nil, err
+	}
+
+	if status := resp.GetStatus(); status != nil {
+		return nil, fmt.Errorf("[Client %v] Decryption share request was not granted: %v", c.id, status.Reason)
+	}
+	s := resp.GetElGamalDecryptionShare()
+	if s == nil {
+		return nil, fmt.Errorf("[Client %v] Emmy server did not send the decryption share", c.id)
+	}
+
+	share := &encryption.ElGamalDecryptionShare{
+		Index: int(s.Index),
+		D:     new(big.Int).SetBytes(s.D),
+		Proof: &dlogproofs.DLogEqualityProof{
+			X1: new(big.Int).SetBytes(s.X1),
+			X2: new(big.Int).SetBytes(s.X2),
+			Z:  new(big.Int).SetBytes(s.Z),
+		},
+	}
+	if !c.pubKey.VerifyDecryptionShare(c.c, share) {
+		return nil, fmt.Errorf("[Client %v] Decryption share is not valid", c.id)
+	}
+	c.result.Verified = true
+	return share, nil
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
+	"math/big"
+)
+
+// PaillierDecryptionShareClient asks emmy server, acting as one of the trustees of a
+// threshold Paillier key, for its decryption share of a ciphertext (see trustee package).
+// Shares collected from threshold trustees are combined into the plaintext with
+// encryption.ThresholdPaillierPubKey.Combine.
+type PaillierDecryptionShareClient struct {
+	genericClient
+	pubKey        *encryption.ThresholdPaillierPubKey
+	c             *big.Int
+	label         *big.Int
+	justification string
+}
+
+// NewPaillierDecryptionShareClient returns an initialized struct of type
+// PaillierDecryptionShareClient for ciphertext c, requested on behalf of the case with
+// the given label (see trustee.Label). The justification of the request is recorded in
+// the trustee's audit log. pubKey is used to verify the decryption share.
+func NewPaillierDecryptionShareClient(t transport.Transport, pubKey *encryption.ThresholdPaillierPubKey,
+	c, label *big.Int, justification string) *PaillierDecryptionShareClient {
+	return &PaillierDecryptionShareClient{
+		genericClient: *newGenericClient(t),
+		pubKey:        pubKey,
+		c:             c,
+		label:         label,
+		justification: justification,
+	}
+}
+
+// GetShare sends the request to emmy server and returns its decryption share, once it
+// verifies the proof of its correctness. It returns an error if the request was not
+// granted or the proof is not valid.
+func (c *PaillierDecryptionShareClient) GetShare(ctx context.Context) (*encryption.PaillierDecryptionShare, error) {
+	c.startRun()
+	share, err := c.getShare(ctx)
+	if _, err := c.finishRun(ctx, err); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (c *PaillierDecryptionShareClient) getShare(ctx context.Context) (*encryption.PaillierDecryptionShare, error) {
+	req := &pb.Message{
+		ClientId: c.id,
+		Schema:   pb.SchemaType_PAILLIER_DECRYPTION_SHARE,
+		Content: &pb.Message_PaillierDecryptionShareRequest{
+			&pb.PaillierDecryptionShareRequest{
+				C:             c.c.Bytes(),
+				Label:         c.label.Bytes(),
+				Justification: c.justification,
+			},
+		},
+	}
+	resp, err := c.getResponseTo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.close(); err != nil {
+		return nil, err
+	}
+
+	if status := resp.GetStatus(); status != nil {
+		return nil, fmt.Errorf("[Client %v] Decryption share request was not granted: %v", c.id, status.Reason)
+	}
+	s := resp.GetPaillierDecryptionShare()
+	if s == nil {
+		return nil, fmt.Errorf("[Client %v] Emmy server did not send the decryption share", c.id)
+	}
+
+	share := &encryption.PaillierDecryptionShare{
+		Index: int(s.Index),
+		C:     new(big.Int).SetBytes(s.C),
+		A:     new(big.Int).SetBytes(s.A),
+		B:     new(big.Int).SetBytes(s.B),
+		Z:     new(big.Int).SetBytes(s.Z),
+	}
+	if !c.pubKey.VerifyDecryptionShare(c.c, share) {
+		return nil, fmt.Errorf("[Client %v] Decryption share is not valid", c.id)
+	}
+	c.result.Verified = true
+	return share, nil
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/xlab-si/emmy/commitments"
 	"github.com/xlab-si/emmy/dlog"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
 	"math/big"
 )
 
@@ -14,17 +16,12 @@ type PedersenClient struct {
 }
 
 // NewPedersenClient returns an initialized struct of type PedersenClient.
-func NewPedersenClient(endpoint string, variant pb.SchemaVariant, dlog *dlog.ZpDLog,
+func NewPedersenClient(t transport.Transport, variant pb.SchemaVariant, dlog *dlog.ZpDLog,
 	val *big.Int) (*PedersenClient, error) {
-	genericClient, err := newGenericClient(endpoint)
-	if err != nil {
-		return nil, err
-	}
-
 	validateVariant(variant)
 
 	return &PedersenClient{
-		pedersenCommonClient: pedersenCommonClient{genericClient: *genericClient},
+		pedersenCommonClient: pedersenCommonClient{genericClient: *newGenericClient(t)},
 		committer:            commitments.NewPedersenCommitter(dlog),
 		val:                  val,
 	}, nil
@@ -32,7 +29,21 @@ func NewPedersenClient(endpoint string, variant pb.SchemaVariant, dlog *dlog.ZpD
 
 // Run runs Pedersen commitment protocol in multiplicative group of integers modulo p.
 func (c *PedersenClient) Run() error {
-	pf, err := c.getH()
+	_, err := c.RunWithContext(context.Background())
+	return err
+}
+
+// RunWithContext runs the protocol just like Run, but aborts the protocol execution
+// when ctx is done. It returns the result of the protocol execution.
+func (c *PedersenClient) RunWithContext(ctx context.Context) (*Result, error) {
+	c.startRun()
+	err := c.run(ctx)
+	return c.finishRun(ctx, err)
+}
+
+// run executes the steps of the protocol.
+func (c *PedersenClient) run(ctx context.Context) error {
+	pf, err := c.getH(ctx)
 	if err != nil {
 		return err
 	}
@@ -46,12 +57,12 @@ func (c *PedersenClient) Run() error {
 		return err
 	}
 
-	if err = c.commit(commitment); err != nil {
+	if err = c.commit(ctx, commitment); err != nil {
 		return err
 	}
 
 	decommitVal, r := c.committer.GetDecommitMsg()
-	if err = c.decommit(decommitVal, r); err != nil {
+	if err = c.decommit(ctx, decommitVal, r); err != nil {
 		return err
 	}
 
@@ -61,28 +72,28 @@ func (c *PedersenClient) Run() error {
 	return nil
 }
 
-func (c *PedersenClient) getH() (*pb.PedersenFirst, error) {
+func (c *PedersenClient) getH(ctx context.Context) (*pb.PedersenFirst, error) {
 	initMsg := &pb.Message{
 		ClientId: c.id,
 		Schema:   pb.SchemaType_PEDERSEN,
 		Content:  &pb.Message_Empty{&pb.EmptyMsg{}},
 	}
 
-	resp, err := c.getResponseTo(initMsg)
+	resp, err := c.getResponseTo(ctx, initMsg)
 	if err != nil {
 		return nil, err
 	}
 	return resp.GetPedersenFirst(), nil
 }
 
-func (c *PedersenClient) commit(commitment *big.Int) error {
+func (c *PedersenClient) commit(ctx context.Context, commitment *big.Int) error {
 	commitmentMsg := &pb.Message{
 		Content: &pb.Message_Bigint{
 			&pb.BigInt{X1: commitment.Bytes()},
 		},
 	}
 
-	if _, err := c.getResponseTo(commitmentMsg); err != nil {
+	if _, err := c.getResponseTo(ctx, commitmentMsg); err != nil {
 		return err
 	}
 	return nil
@@ -2,6 +2,7 @@ package client
 
 import (
 	pb "github.com/xlab-si/emmy/protobuf"
+	"golang.org/x/net/context"
 	"math/big"
 )
 
@@ -9,7 +10,7 @@ type pedersenCommonClient struct {
 	genericClient
 }
 
-func (c *pedersenCommonClient) decommit(decommitVal, r *big.Int) error {
+func (c *pedersenCommonClient) decommit(ctx context.Context, decommitVal, r *big.Int) error {
 	decommitMsg := &pb.Message{
 		Content: &pb.Message_PedersenDecommitment{
 			&pb.PedersenDecommitment{
@@ -19,7 +20,7 @@ func (c *pedersenCommonClient) decommit(decommitVal, r *big.Int) error {
 		},
 	}
 
-	if _, err := c.getResponseTo(decommitMsg); err != nil {
+	if _, err := c.getResponseTo(ctx, decommitMsg); err != nil {
 		return err
 	}
 	return nil
@@ -4,6 +4,8 @@ import (
 	"github.com/xlab-si/emmy/commitments"
 	"github.com/xlab-si/emmy/common"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
 	"math/big"
 )
 
@@ -14,14 +16,9 @@ type PedersenECClient struct {
 }
 
 // NewPedersenECClient returns an initialized struct of type PedersenECClient.
-func NewPedersenECClient(endpoint string, v *big.Int) (*PedersenECClient, error) {
-	genericClient, err := newGenericClient(endpoint)
-	if err != nil {
-		return nil, err
-	}
-
+func NewPedersenECClient(t transport.Transport, v *big.Int) (*PedersenECClient, error) {
 	return &PedersenECClient{
-		pedersenCommonClient: pedersenCommonClient{genericClient: *genericClient},
+		pedersenCommonClient: pedersenCommonClient{genericClient: *newGenericClient(t)},
 		committer:            commitments.NewPedersenECCommitter(),
 		val:                  v,
 	}, nil
@@ -29,7 +26,21 @@ func NewPedersenECClient(endpoint string, v *big.Int) (*PedersenECClient, error)
 
 // Run runs Pedersen commitment protocol in the eliptic curve group.
 func (c *PedersenECClient) Run() error {
-	ecge, err := c.getH()
+	_, err := c.RunWithContext(context.Background())
+	return err
+}
+
+// RunWithContext runs the protocol just like Run, but aborts the protocol execution
+// when ctx is done. It returns the result of the protocol execution.
+func (c *PedersenECClient) RunWithContext(ctx context.Context) (*Result, error) {
+	c.startRun()
+	err := c.run(ctx)
+	return c.finishRun(ctx, err)
+}
+
+// run executes the steps of the protocol.
+func (c *PedersenECClient) run(ctx context.Context) error {
+	ecge, err := c.getH(ctx)
 	if err != nil {
 		return err
 	}
@@ -42,12 +53,12 @@ func (c *PedersenECClient) Run() error {
 		return nil
 	}
 
-	if err = c.commit(commitment); err != nil {
+	if err = c.commit(ctx, commitment); err != nil {
 		return err
 	}
 
 	decommitVal, r := c.committer.GetDecommitMsg()
-	if err = c.decommit(decommitVal, r); err != nil {
+	if err = c.decommit(ctx, decommitVal, r); err != nil {
 		return err
 	}
 
@@ -57,28 +68,28 @@ func (c *PedersenECClient) Run() error {
 	return nil
 }
 
-func (c *PedersenECClient) getH() (*pb.ECGroupElement, error) {
+func (c *PedersenECClient) getH(ctx context.Context) (*pb.ECGroupElement, error) {
 	initMsg := &pb.Message{
 		ClientId: c.id,
 		Schema:   pb.SchemaType_PEDERSEN_EC,
 		Content:  &pb.Message_Empty{&pb.EmptyMsg{}},
 	}
 
-	resp, err := c.getResponseTo(initMsg)
+	resp, err := c.getResponseTo(ctx, initMsg)
 	if err != nil {
 		return nil, err
 	}
 	return resp.GetEcGroupElement(), nil
 }
 
-func (c *PedersenECClient) commit(commitVal *common.ECGroupElement) error {
+func (c *PedersenECClient) commit(ctx context.Context, commitVal *common.ECGroupElement) error {
 	commitmentMsg := &pb.Message{
 		Content: &pb.Message_EcGroupElement{
 			common.ToPbECGroupElement(commitVal),
 		},
 	}
 
-	if _, err := c.getResponseTo(commitmentMsg); err != nil {
+	if _, err := c.getResponseTo(ctx, commitmentMsg); err != nil {
 		return err
 	}
 	return nil
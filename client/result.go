@@ -0,0 +1,52 @@
+package client
+
+import (
+	pb "github.com/xlab-si/emmy/protobuf"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Result describes the outcome of a protocol execution.
+type Result struct {
+	// SessionId identifies the protocol execution. It is sent to emmy server as
+	// the client id and appears in the server's logs.
+	SessionId int32
+	// Verified is true if emmy server verified the client's proof.
+	Verified bool
+	// FailureReason explains why the proof was not verified. It is either the reason
+	// reported by emmy server or the error that interrupted the protocol.
+	FailureReason string
+	// Timings holds the duration of each request-response exchange with emmy server.
+	Timings []StepTiming
+	// Transcript holds messages exchanged with emmy server (requests and responses
+	// in turn), if the client was instructed to record them.
+	Transcript []*pb.Message
+}
+
+// StepTiming holds the duration of a single step of the protocol.
+type StepTiming struct {
+	// Step is the type of the message sent to emmy server in this step,
+	// for example "SchnorrProofData".
+	Step     string
+	Duration time.Duration
+}
+
+// Duration returns the total duration of the protocol steps.
+func (r *Result) Duration() time.Duration {
+	var d time.Duration
+	for _, t := range r.Timings {
+		d += t.Duration
+	}
+	return d
+}
+
+// stepName returns the name of the content type of the message, used for naming
+// protocol steps.
+func stepName(msg *pb.Message) string {
+	if msg.Content == nil {
+		return "Empty"
+	}
+	name := reflect.TypeOf(msg.Content).Elem().Name()
+	return strings.TrimPrefix(name, "Message_")
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/xlab-si/emmy/dlog"
 	"github.com/xlab-si/emmy/dlogproofs"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
 	"math/big"
 )
 
@@ -18,15 +20,10 @@ type SchnorrClient struct {
 }
 
 // NewSchnorrClient returns an initialized struct of type SchnorrClient.
-func NewSchnorrClient(endpoint string, variant pb.SchemaVariant, dlog *dlog.ZpDLog,
+func NewSchnorrClient(t transport.Transport, variant pb.SchemaVariant, dlog *dlog.ZpDLog,
 	s *big.Int) (*SchnorrClient, error) {
-	genericClient, err := newGenericClient(endpoint)
-	if err != nil {
-		return nil, err
-	}
-
 	return &SchnorrClient{
-		genericClient: *genericClient,
+		genericClient: *newGenericClient(t),
 		variant:       variant,
 		prover:        dlogproofs.NewSchnorrProver(dlog, common.ToProtocolType(variant)),
 		secret:        s,
@@ -38,26 +35,40 @@ func NewSchnorrClient(endpoint string, variant pb.SchemaVariant, dlog *dlog.ZpDL
 // group of integers modulo p. It executes either sigma protocol or Zero Knowledge Proof(of
 // knowledge)
 func (c *SchnorrClient) Run() error {
+	_, err := c.RunWithContext(context.Background())
+	return err
+}
+
+// RunWithContext runs the protocol just like Run, but aborts the protocol execution
+// when ctx is done. It returns the result of the protocol execution.
+func (c *SchnorrClient) RunWithContext(ctx context.Context) (*Result, error) {
+	c.startRun()
+	err := c.run(ctx)
+	return c.finishRun(ctx, err)
+}
+
+// run executes the steps of the protocol.
+func (c *SchnorrClient) run(ctx context.Context) error {
 	if c.variant == pb.SchemaVariant_SIGMA {
-		return c.runSigma()
+		return c.runSigma(ctx)
 	}
-	return c.runZeroKnowledge()
+	return c.runZeroKnowledge(ctx)
 }
 
 // runSigma runs the sigma version of the Schnorr protocol
-func (c *SchnorrClient) runSigma() error {
+func (c *SchnorrClient) runSigma(ctx context.Context) error {
 	initMsg := &pb.Message{
 		ClientId:      c.id,
 		Schema:        pb.SchemaType_SCHNORR,
 		SchemaVariant: pb.SchemaVariant_SIGMA,
 	}
-	pedersenDecommitment, err := c.getProofRandomData(true, initMsg)
+	pedersenDecommitment, err := c.getProofRandomData(ctx, true, initMsg)
 	if err != nil {
 		return err
 	}
 
 	challenge := new(big.Int).SetBytes(pedersenDecommitment.X)
-	proved, err := c.getProofData(challenge)
+	proved, err := c.getProofData(ctx, challenge)
 	if err != nil {
 		return err
 	}
@@ -72,14 +83,14 @@ func (c *SchnorrClient) runSigma() error {
 
 // runZeroKnowledge runs the ZKP or ZKPOK version of Schnorr protocol, depending on the value
 // of SchnorrClient's variant field.
-func (c *SchnorrClient) runZeroKnowledge() error {
-	commitment, err := c.open() // sends pedersen's h=g^trapdoor
+func (c *SchnorrClient) runZeroKnowledge(ctx context.Context) error {
+	commitment, err := c.open(ctx) // sends pedersen's h=g^trapdoor
 	if err != nil {
 		return err
 	}
 	c.prover.PedersenReceiver.SetCommitment(commitment)
 
-	pedersenDecommitment, err := c.getProofRandomData(false, &pb.Message{})
+	pedersenDecommitment, err := c.getProofRandomData(ctx, false, &pb.Message{})
 	if err != nil {
 		return err
 	}
@@ -88,7 +99,7 @@ func (c *SchnorrClient) runZeroKnowledge() error {
 	r := new(big.Int).SetBytes(pedersenDecommitment.R)
 
 	if success := c.prover.PedersenReceiver.CheckDecommitment(r, challenge); success {
-		proved, err := c.getProofData(challenge)
+		proved, err := c.getProofData(ctx, challenge)
 		logger.Noticef("Decommitment successful, proved: %v", proved)
 		if err != nil {
 			return err
@@ -103,7 +114,7 @@ func (c *SchnorrClient) runZeroKnowledge() error {
 	return nil
 }
 
-func (c *SchnorrClient) open() (*big.Int, error) {
+func (c *SchnorrClient) open(ctx context.Context) (*big.Int, error) {
 	h := c.prover.GetOpeningMsg()
 	openMsg := &pb.Message{
 		ClientId:      c.id,
@@ -114,7 +125,7 @@ func (c *SchnorrClient) open() (*big.Int, error) {
 		},
 	}
 
-	resp, err := c.getResponseTo(openMsg)
+	resp, err := c.getResponseTo(ctx, openMsg)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +133,7 @@ func (c *SchnorrClient) open() (*big.Int, error) {
 	return new(big.Int).SetBytes(bigint.X1), nil
 }
 
-func (c *SchnorrClient) getProofRandomData(isFirstMsg bool, msg *pb.Message) (*pb.PedersenDecommitment, error) {
+func (c *SchnorrClient) getProofRandomData(ctx context.Context, isFirstMsg bool, msg *pb.Message) (*pb.PedersenDecommitment, error) {
 	x := c.prover.GetProofRandomData(c.secret, c.a)
 	b, _ := c.prover.DLog.Exponentiate(c.a, c.secret)
 	pRandomData := pb.SchnorrProofRandomData{
@@ -134,7 +145,7 @@ func (c *SchnorrClient) getProofRandomData(isFirstMsg bool, msg *pb.Message) (*p
 	msg.Content = &pb.Message_SchnorrProofRandomData{
 		&pRandomData,
 	}
-	resp, err := c.getResponseTo(msg)
+	resp, err := c.getResponseTo(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +153,7 @@ func (c *SchnorrClient) getProofRandomData(isFirstMsg bool, msg *pb.Message) (*p
 	return resp.GetPedersenDecommitment(), nil
 }
 
-func (c *SchnorrClient) getProofData(challenge *big.Int) (bool, error) {
+func (c *SchnorrClient) getProofData(ctx context.Context, challenge *big.Int) (bool, error) {
 	z, trapdoor := c.prover.GetProofData(challenge)
 	if trapdoor == nil { // sigma protocol and ZKP
 		trapdoor = new(big.Int)
@@ -156,7 +167,7 @@ func (c *SchnorrClient) getProofData(challenge *big.Int) (bool, error) {
 		},
 	}
 
-	resp, err := c.getResponseTo(msg)
+	resp, err := c.getResponseTo(ctx, msg)
 	if err != nil {
 		return false, err
 	}
@@ -6,6 +6,8 @@ import (
 	"github.com/xlab-si/emmy/dlog"
 	"github.com/xlab-si/emmy/dlogproofs"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
 	"math/big"
 )
 
@@ -18,20 +20,15 @@ type SchnorrECClient struct {
 }
 
 // NewSchnorrECClient returns an initialized struct of type SchnorrECClient.
-func NewSchnorrECClient(endpoint string, variant pb.SchemaVariant, dlog *dlog.ECDLog,
+func NewSchnorrECClient(t transport.Transport, variant pb.SchemaVariant, dlog *dlog.ECDLog,
 	s *big.Int) (*SchnorrECClient, error) {
-	genericClient, err := newGenericClient(endpoint)
-	if err != nil {
-		return nil, err
-	}
-
 	prover, err := dlogproofs.NewSchnorrECProver(common.ToProtocolType(variant))
 	if err != nil {
 		return nil, fmt.Errorf("Could not create schnorr EC prover: %v", err)
 	}
 
 	return &SchnorrECClient{
-		genericClient: *genericClient,
+		genericClient: *newGenericClient(t),
 		prover:        prover,
 		variant:       variant,
 		secret:        s,
@@ -45,20 +42,34 @@ func NewSchnorrECClient(endpoint string, variant pb.SchemaVariant, dlog *dlog.EC
 // Run starts the Schnorr protocol for proving knowledge of a discrete logarithm in elliptic curve
 // group. It executes either sigma protocol or Zero Knowledge Proof (of knowledge)
 func (c *SchnorrECClient) Run() error {
+	_, err := c.RunWithContext(context.Background())
+	return err
+}
+
+// RunWithContext runs the protocol just like Run, but aborts the protocol execution
+// when ctx is done. It returns the result of the protocol execution.
+func (c *SchnorrECClient) RunWithContext(ctx context.Context) (*Result, error) {
+	c.startRun()
+	err := c.run(ctx)
+	return c.finishRun(ctx, err)
+}
+
+// run executes the steps of the protocol.
+func (c *SchnorrECClient) run(ctx context.Context) error {
 	if c.variant == pb.SchemaVariant_SIGMA {
-		return c.runSigma()
+		return c.runSigma(ctx)
 	}
-	return c.runZeroKnowledge()
+	return c.runZeroKnowledge(ctx)
 }
 
 // RunSigma runs the sigma version of the Schnorr protocol in the elliptic curve group
-func (c *SchnorrECClient) runSigma() error {
-	pedersenDecommitment, err := c.getProofRandomData(true)
+func (c *SchnorrECClient) runSigma(ctx context.Context) error {
+	pedersenDecommitment, err := c.getProofRandomData(ctx, true)
 	if err != nil {
 		return err
 	}
 	challenge := new(big.Int).SetBytes(pedersenDecommitment.X)
-	proved, err := c.getProofData(challenge)
+	proved, err := c.getProofData(ctx, challenge)
 	if err != nil {
 		return err
 	}
@@ -73,14 +84,14 @@ func (c *SchnorrECClient) runSigma() error {
 
 // runZeroKnowledge runs the ZKP or ZKPOK version of Schnorr protocol in the elliptic curve group,
 // depending on the value of SchnorrClient's variant field.
-func (c *SchnorrECClient) runZeroKnowledge() error {
-	commitment, err := c.open()
+func (c *SchnorrECClient) runZeroKnowledge(ctx context.Context) error {
+	commitment, err := c.open(ctx)
 	if err != nil {
 		return err
 	}
 
 	c.prover.PedersenReceiver.SetCommitment(commitment)
-	pedersenDecommitment, err := c.getProofRandomData(false)
+	pedersenDecommitment, err := c.getProofRandomData(ctx, false)
 	if err != nil {
 		return err
 	}
@@ -90,7 +101,7 @@ func (c *SchnorrECClient) runZeroKnowledge() error {
 
 	success := c.prover.PedersenReceiver.CheckDecommitment(r, challenge)
 	if success {
-		proved, err := c.getProofData(challenge)
+		proved, err := c.getProofData(ctx, challenge)
 		logger.Noticef("Decommitment successful, proved: %v", proved)
 		if err != nil {
 			return err
@@ -105,7 +116,7 @@ func (c *SchnorrECClient) runZeroKnowledge() error {
 	return nil
 }
 
-func (c *SchnorrECClient) open() (*common.ECGroupElement, error) {
+func (c *SchnorrECClient) open(ctx context.Context) (*common.ECGroupElement, error) {
 	h := c.prover.GetOpeningMsg()
 	ecge := common.ToPbECGroupElement(h)
 	openMsg := &pb.Message{
@@ -115,7 +126,7 @@ func (c *SchnorrECClient) open() (*common.ECGroupElement, error) {
 		Content:       &pb.Message_EcGroupElement{ecge},
 	}
 
-	resp, err := c.getResponseTo(openMsg)
+	resp, err := c.getResponseTo(ctx, openMsg)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +135,7 @@ func (c *SchnorrECClient) open() (*common.ECGroupElement, error) {
 	return common.ToECGroupElement(ecge), nil
 }
 
-func (c *SchnorrECClient) getProofRandomData(isFirstMsg bool) (*pb.PedersenDecommitment, error) {
+func (c *SchnorrECClient) getProofRandomData(ctx context.Context, isFirstMsg bool) (*pb.PedersenDecommitment, error) {
 	x := c.prover.GetProofRandomData(c.secret, c.a) // x = a^r, b = a^secret is "public key"
 	b1, b2 := c.prover.DLog.Exponentiate(c.a.X, c.a.Y, c.secret)
 	b := &common.ECGroupElement{X: b1, Y: b2}
@@ -147,7 +158,7 @@ func (c *SchnorrECClient) getProofRandomData(isFirstMsg bool) (*pb.PedersenDecom
 		&pRandomData,
 	}
 
-	resp, err := c.getResponseTo(req)
+	resp, err := c.getResponseTo(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +166,7 @@ func (c *SchnorrECClient) getProofRandomData(isFirstMsg bool) (*pb.PedersenDecom
 	return resp.GetPedersenDecommitment(), nil
 }
 
-func (c *SchnorrECClient) getProofData(challenge *big.Int) (bool, error) {
+func (c *SchnorrECClient) getProofData(ctx context.Context, challenge *big.Int) (bool, error) {
 	z, trapdoor := c.prover.GetProofData(challenge)
 	if trapdoor == nil { // sigma protocol and ZKP
 		trapdoor = new(big.Int)
@@ -169,7 +180,7 @@ func (c *SchnorrECClient) getProofData(challenge *big.Int) (bool, error) {
 		},
 	}
 
-	resp, err := c.getResponseTo(msg)
+	resp, err := c.getResponseTo(ctx, msg)
 	if err != nil {
 		return false, err
 	}
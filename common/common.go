@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"math/big"
+	"runtime"
 )
 
 // It takes big.Int numbers, transform them to bytes, and concatenate the bytes.
@@ -235,7 +236,6 @@ func GetSchnorrGroup(qBitLength int) (*big.Int, *big.Int, *big.Int, error) {
 
 	params := dsa.Parameters{}
 	err := dsa.GenerateParameters(&params, rand.Reader, sizes)
-	log.Println(err)
 	if err == nil {
 		return params.G, params.Q, params.P, nil
 	} else {
@@ -263,39 +263,47 @@ func GetSafePrime(bits int) (p *big.Int, err error) {
 func GetGermainPrime(bits int) (p *big.Int) {
 	// multiple germainPrime goroutines are called and we assume at least one will compute a
 	// safe prime and send it to the channel, thus we do not handle errors in germainPrime
-	var c chan *big.Int = make(chan *big.Int)
-	var quit chan int = make(chan int)
-	for j := int(0); j < 8; j++ {
+	c := make(chan *big.Int)
+	quit := make(chan struct{})
+	for j := 0; j < runtime.NumCPU(); j++ {
 		go germainPrime(bits, c, quit)
 	}
-	msg := <-c
-	close(c)
+	p = <-c
+	// the remaining goroutines stop searching, or stop waiting to send their result
 	close(quit)
-	return msg
+	return p
 }
 
-var smallPrimes = []uint8{
-	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53,
-}
+// smallPrimes are the odd primes below 2^11. Candidates p for which p or 2*p + 1 is
+// divisible by any of them are sieved out before running (much more expensive)
+// primality tests.
+var smallPrimes = func() []uint64 {
+	var primes []uint64
+	for n := uint64(3); n < 1<<11; n += 2 {
+		if big.NewInt(int64(n)).ProbablyPrime(0) {
+			primes = append(primes, n)
+		}
+	}
+	return primes
+}()
 
-// smallPrimesProduct is the product of the values in smallPrimes and allows us
-// to reduce a candidate prime by this number and then determine whether it's
-// coprime to all the elements of smallPrimes without further big.Int
-// operations.
-var smallPrimesProduct = new(big.Int).SetUint64(16294579238595022365)
+// minSievedBits is the minimal length of germain primes searched for with the sieve,
+// which would otherwise sieve out germain primes that are themselves small primes.
+const minSievedBits = 16
 
 // germainPrime is slightly modified Prime function from:
 // https://github.com/golang/go/blob/master/src/crypto/rand/util.go
-// germainPrime returns a number, p, of the given size, such that p and 2*p+1 are primes
-// with high probability.
+// germainPrime sends to c a number, p, of the given size, such that p and 2*p+1 are primes
+// with high probability. It stops searching once quit is closed.
 // germainPrime will return error for any error returned by rand.Read or if bits < 2.
-func germainPrime(bits int, c chan *big.Int, quit chan int) (p *big.Int, err error) {
-	rand := rand.Reader
-
+func germainPrime(bits int, c chan<- *big.Int, quit <-chan struct{}) (p *big.Int, err error) {
 	if bits < 2 {
 		err = errors.New("crypto/rand: prime size must be at least 2-bit")
 		return
 	}
+	if bits < minSievedBits {
+		return smallGermainPrime(bits, c, quit)
+	}
 
 	b := uint(bits % 8)
 	if b == 0 {
@@ -303,10 +311,10 @@ func germainPrime(bits int, c chan *big.Int, quit chan int) (p *big.Int, err err
 	}
 
 	bytes := make([]byte, (bits+7)/8)
-	p = new(big.Int)
-	p1 := new(big.Int)
-
+	start := new(big.Int)
+	residues := make([]uint64, len(smallPrimes))
 	bigMod := new(big.Int)
+	bigPrime := new(big.Int)
 
 	for {
 		select {
@@ -316,7 +324,7 @@ func germainPrime(bits int, c chan *big.Int, quit chan int) (p *big.Int, err err
 			// this is to make it non-blocking
 		}
 
-		_, err = io.ReadFull(rand, bytes)
+		_, err = io.ReadFull(rand.Reader, bytes)
 		if err != nil {
 			return nil, err
 		}
@@ -339,61 +347,78 @@ func germainPrime(bits int, c chan *big.Int, quit chan int) (p *big.Int, err err
 		// Make the value odd since an even number this large certainly isn't prime.
 		bytes[len(bytes)-1] |= 1
 
-		p.SetBytes(bytes)
-
-		// Calculate the value mod the product of smallPrimes. If it's
-		// a multiple of any of these primes we add two until it isn't.
-		// The probability of overflowing is minimal and can be ignored
-		// because we still perform Miller-Rabin tests on the result.
-		bigMod.Mod(p, smallPrimesProduct)
-		mod := bigMod.Uint64()
+		start.SetBytes(bytes)
+		for i, prime := range smallPrimes {
+			residues[i] = bigMod.Mod(start, bigPrime.SetUint64(prime)).Uint64()
+		}
 
+		// Candidates start + delta are sieved with the residues of start, so that only
+		// the few candidates for which neither p nor 2*p + 1 has a small factor are
+		// tested for primality.
 	NextDelta:
 		for delta := uint64(0); delta < 1<<20; delta += 2 {
-			m := mod + delta
-			for _, prime := range smallPrimes {
-				if m%uint64(prime) == 0 && (bits > 6 || m != uint64(prime)) {
-					continue NextDelta
-				}
-
-				// 2*mod + 2*delta + 1	should not be divisible by smallPrimes as well
-				m1 := (2*m + 1) % smallPrimesProduct.Uint64()
-
-				if m1%uint64(prime) == 0 && (bits > 6 || m1 != uint64(prime)) {
+			for i, prime := range smallPrimes {
+				r := (residues[i] + delta) % prime
+				if r == 0 || (2*r+1)%prime == 0 {
 					continue NextDelta
 				}
 			}
 
-			if delta > 0 {
-				bigMod.SetUint64(delta)
-				p.Add(p, bigMod)
+			p = new(big.Int).Add(start, bigMod.SetUint64(delta))
+			// There is a tiny possibility that, by adding delta, we caused
+			// the number to be one bit too long.
+			if p.BitLen() != bits {
+				break
 			}
-
-			p1.Add(p, p)
+			p1 := new(big.Int).Add(p, p)
 			p1.Add(p1, big.NewInt(1))
-			break
-		}
 
-		// There is a tiny possibility that, by adding delta, we caused
-		// the number to be one bit too long. Thus we check BitLen
-		// here.
-		if p.ProbablyPrime(20) && p.BitLen() == bits {
-			if p1.ProbablyPrime(20) {
-				// waiting for a message about channel being closed is repeated here,
-				// because it might happen that channel is closed after waiting at the
-				// beginning of for loop above (but we want to have it there also,
-				// otherwise it this goroutine might be searching for a germain
-				// prime for some time after one was found by another goroutine
+			// Fermat tests with base 2 reject almost all composite candidates at the
+			// cost of a single exponentiation, before running the full tests
+			if !isFermatProbablePrime(p) || !isFermatProbablePrime(p1) {
+				continue
+			}
+			if p.ProbablyPrime(20) && p1.ProbablyPrime(20) {
 				select {
+				case c <- p:
 				case <-quit:
-					return
-				default:
-					// this is to make it non-blocking
 				}
+				return p, nil
+			}
+		}
+	}
+}
+
+// smallGermainPrime sends to c a germain prime of the given (small) size. It stops
+// searching once quit is closed.
+func smallGermainPrime(bits int, c chan<- *big.Int, quit <-chan struct{}) (*big.Int, error) {
+	for {
+		select {
+		case <-quit:
+			return nil, nil
+		default:
+			// this is to make it non-blocking
+		}
 
-				c <- p
-				return
+		p, err := rand.Prime(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		p1 := new(big.Int).Add(p, p)
+		p1.Add(p1, big.NewInt(1))
+		if p1.ProbablyPrime(20) {
+			select {
+			case c <- p:
+			case <-quit:
 			}
+			return p, nil
 		}
 	}
 }
+
+// isFermatProbablePrime returns false if n is certainly composite, based on the Fermat
+// test with base 2, that is whether 2^(n-1) = 1 mod n.
+func isFermatProbablePrime(n *big.Int) bool {
+	nMin := new(big.Int).Sub(n, big.NewInt(1))
+	return new(big.Int).Exp(big.NewInt(2), nMin, n).Cmp(big.NewInt(1)) == 0
+}
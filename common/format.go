@@ -0,0 +1,204 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FormatVersion is the version of JSON and PEM encodings of keys, group parameters,
+// signatures and credentials. It is increased whenever an encoding changes in an
+// incompatible way.
+const FormatVersion = 1
+
+// pemTypePrefix prefixes types of PEM blocks holding emmy objects.
+const pemTypePrefix = "EMMY "
+
+// Int is a big.Int encoded in JSON as a string holding its decimal representation,
+// so that it is not subject to precision limits of JSON numbers in other implementations.
+type Int big.Int
+
+// NewInt returns x as Int.
+func NewInt(x *big.Int) *Int {
+	return (*Int)(x)
+}
+
+// NewInts returns xs as Ints.
+func NewInts(xs []*big.Int) []*Int {
+	ints := make([]*Int, len(xs))
+	for i, x := range xs {
+		ints[i] = NewInt(x)
+	}
+	return ints
+}
+
+// BigInt returns i as big.Int.
+func (i *Int) BigInt() *big.Int {
+	return (*big.Int)(i)
+}
+
+// BigInts returns ints as big.Ints.
+func BigInts(ints []*Int) []*big.Int {
+	xs := make([]*big.Int, len(ints))
+	for j, i := range ints {
+		xs[j] = i.BigInt()
+	}
+	return xs
+}
+
+func (i *Int) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.BigInt().String())
+}
+
+func (i *Int) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("Integer must be encoded as a decimal string: %v", err)
+	}
+	if _, ok := i.BigInt().SetString(s, 10); !ok {
+		return fmt.Errorf("Invalid decimal integer: %q", s)
+	}
+	return nil
+}
+
+// versioned is the JSON encoding of an emmy object. Type identifies the kind of
+// object (for example "paillier-public-key") and Value holds its fields.
+type versioned struct {
+	Version int             `json:"version"`
+	Type    string          `json:"type"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// MarshalVersioned returns the JSON encoding of value, which is a pointer to a struct
+// holding the fields of an object of type objType, together with the format version.
+func MarshalVersioned(objType string, value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&versioned{
+		Version: FormatVersion,
+		Type:    objType,
+		Value:   data,
+	})
+}
+
+// UnmarshalVersioned decodes the JSON encoding produced by MarshalVersioned into value.
+// It returns an error if the encoding is not of type objType, is of unsupported version
+// or misses any of the fields of value, except the ones tagged with omitempty.
+func UnmarshalVersioned(data []byte, objType string, value interface{}) error {
+	var v versioned
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("Invalid encoding of %v: %v", objType, err)
+	}
+	if v.Type != objType {
+		return fmt.Errorf("Expected %v, got %q", objType, v.Type)
+	}
+	if v.Version < 1 || v.Version > FormatVersion {
+		return fmt.Errorf("Unsupported version of %v encoding: %d", objType, v.Version)
+	}
+	if err := json.Unmarshal(v.Value, value); err != nil {
+		return fmt.Errorf("Invalid encoding of %v: %v", objType, err)
+	}
+	if err := checkRequired(value); err != nil {
+		return fmt.Errorf("Invalid encoding of %v: %v", objType, err)
+	}
+	return nil
+}
+
+// MarshalPEM returns the PEM-armored JSON encoding of v.
+// The type of the PEM block is derived from the type of encoded object, for example
+// "EMMY PAILLIER PUBLIC KEY", and the format version is stored in its Version header.
+func MarshalPEM(v json.Marshaler) ([]byte, error) {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var ver versioned
+	if err := json.Unmarshal(data, &ver); err != nil || ver.Type == "" {
+		return nil, errors.New("Only versioned objects can be PEM encoded")
+	}
+
+	block := &pem.Block{
+		Type:    pemType(ver.Type),
+		Headers: map[string]string{"Version": strconv.Itoa(ver.Version)},
+		Bytes:   data,
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// UnmarshalPEM decodes the first PEM block in data, as produced by MarshalPEM, into v.
+func UnmarshalPEM(data []byte, v json.Unmarshaler) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return errors.New("No PEM block found")
+	}
+	if !strings.HasPrefix(block.Type, pemTypePrefix) {
+		return fmt.Errorf("Unexpected PEM block type %q", block.Type)
+	}
+	return v.UnmarshalJSON(block.Bytes)
+}
+
+// IsJSONOrPEM returns true if data holds the JSON or PEM encoding of an object (as
+// opposed to, for example, its protobuf encoding).
+func IsJSONOrPEM(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return bytes.HasPrefix(data, []byte("{")) || bytes.HasPrefix(data, []byte("-----BEGIN "))
+}
+
+// UnmarshalJSONOrPEM decodes either the JSON or the PEM encoding of an object into v.
+func UnmarshalJSONOrPEM(data []byte, v json.Unmarshaler) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN ")) {
+		return UnmarshalPEM(data, v)
+	}
+	return v.UnmarshalJSON(data)
+}
+
+// pemType returns the type of PEM block holding an object of type objType.
+func pemType(objType string) string {
+	return pemTypePrefix + strings.ToUpper(strings.Replace(objType, "-", " ", -1))
+}
+
+// checkRequired returns an error if any pointer, slice or map field of the struct
+// pointed to by value (or of structs embedded in it) is nil, unless its JSON tag
+// contains omitempty.
+func checkRequired(value interface{}) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return checkRequiredFields(v.Elem())
+}
+
+func checkRequiredFields(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if len(tag) > 1 && tag[1] == "omitempty" {
+			continue
+		}
+		switch v.Field(i).Kind() {
+		case reflect.Struct:
+			if field.Anonymous {
+				if err := checkRequiredFields(v.Field(i)); err != nil {
+					return err
+				}
+			}
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if v.Field(i).IsNil() {
+				name := tag[0]
+				if name == "" {
+					name = field.Name
+				}
+				return fmt.Errorf("missing field %v", name)
+			}
+		}
+	}
+	return nil
+}
@@ -27,6 +27,20 @@ func NewRandomPolynomial(degree int, prime *big.Int) (*Polynomial, error) {
 	return &polynomial, nil
 }
 
+// NewPolynomial returns the polynomial with the given coefficients [a_0, a_1, ..., a_degree]
+// from Z_prime.
+func NewPolynomial(coefficients []*big.Int, prime *big.Int) *Polynomial {
+	return &Polynomial{
+		coefficients: coefficients,
+		degree:       len(coefficients) - 1,
+		prime:        prime,
+	}
+}
+
+func (polynomial *Polynomial) GetCoefficient(coeff_ind int) *big.Int {
+	return polynomial.coefficients[coeff_ind]
+}
+
 func (polynomial *Polynomial) SetCoefficient(coeff_ind int, coefficient *big.Int) {
 	polynomial.coefficients[coeff_ind] = coefficient
 }
@@ -1,28 +1,85 @@
 package config
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
 	"github.com/spf13/viper"
 	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/keystore"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
-// init loads the default config file
+// defaultConfig holds the default configuration, compiled into emmy so that it
+// does not depend on the location of the source tree.
+//
+//go:embed defaults.yml
+var defaultConfig []byte
+
+// configName is the name (without suffix) of the config file looked up in searchPaths.
+const configName = "emmy"
+
+// searchPaths are the standard folders where emmy looks for a config file.
+var searchPaths = []string{".", "$HOME/.emmy", "/etc/emmy"}
+
+// init loads the default configuration and enables overriding its values with
+// environment variables prefixed with EMMY_ (for example EMMY_PORT or EMMY_KEY_FOLDER).
 func init() {
-	viper.AddConfigPath("$GOPATH/src/github.com/xlab-si/emmy/config")
-	LoadConfig("defaults", "yml")
+	viper.SetConfigType("yml")
+	if err := viper.ReadConfig(bytes.NewReader(defaultConfig)); err != nil {
+		panic(fmt.Errorf("Cannot read default configuration: %s\n", err))
+	}
+
+	viper.SetEnvPrefix("emmy")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
 }
 
-// LoadConfig reads in the config file with configName being the name of the file (without suffix)
-// and configType being "yml" or "json".
-func LoadConfig(configName string, configType string) {
-	viper.SetConfigName(configName)
+// LoadConfigFile reads in the config file at the given path. Its values override
+// the defaults, while values missing from the file keep their default values.
+// The type of the file ("yml" or "json") is determined from its extension.
+// Config files holding secrets can be encrypted (see keystore package), in which
+// case the passphrase is obtained with keystore.Passphrase.
+func LoadConfigFile(path string) error {
+	data, err := keystore.Load(path)
+	if err != nil {
+		return fmt.Errorf("Cannot read configuration file %v: %v", path, err)
+	}
+
+	configType := strings.TrimPrefix(filepath.Ext(path), ".")
+	if configType == "" {
+		configType = "yml"
+	}
 	viper.SetConfigType(configType)
+	defer viper.SetConfigType("yml")
+	if err := viper.MergeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("Cannot read configuration file %v: %v", path, err)
+	}
+	return nil
+}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		panic(fmt.Errorf("Cannot read configuration file: %s\n", err))
+// LoadConfigFromSearchPaths looks for a config file named emmy.yml in the current
+// folder, $HOME/.emmy and /etc/emmy (in this order) and reads in the first one found,
+// as in LoadConfigFile. It is not an error if there is no config file.
+func LoadConfigFromSearchPaths() error {
+	for _, dir := range searchPaths {
+		for _, ext := range viper.SupportedExts {
+			path := filepath.Join(os.ExpandEnv(dir), configName+"."+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return LoadConfigFile(path)
+			}
+		}
 	}
+	return nil
+}
+
+// Set overrides the configured value for the given key, for example with a value
+// passed as a command line flag.
+func Set(key string, value interface{}) {
+	viper.Set(key, value)
 }
 
 // LoadServerPort returns the port where emmy server will be listening.
@@ -30,6 +87,11 @@ func LoadServerPort() int {
 	return viper.GetInt("port")
 }
 
+// LoadGatewayPort returns the port where HTTP/JSON gateway of emmy server will be listening.
+func LoadGatewayPort() int {
+	return viper.GetInt("gateway_port")
+}
+
 // LoadServerEndpoint returns the endpoint of the emmy server where clients will be contacting it.
 func LoadServerEndpoint() string {
 	ip := viper.GetString("ip")
@@ -53,49 +115,169 @@ func LoadTestKeyDirFromConfig() string {
 	return key_path
 }
 
-func LoadDLog(scheme string) *dlog.ZpDLog {
-	dlogMap := viper.GetStringMap(scheme)
-	p, _ := new(big.Int).SetString(dlogMap["p"].(string), 10)
-	g, _ := new(big.Int).SetString(dlogMap["g"].(string), 10)
-	q, _ := new(big.Int).SetString(dlogMap["q"].(string), 10)
-	dlog := dlog.ZpDLog{
-		P:               p,
-		G:               g,
-		OrderOfSubgroup: q,
+// LoadDLog returns the group parameters p, g and q configured for the given scheme.
+func LoadDLog(scheme string) (*dlog.ZpDLog, error) {
+	return global.loadDLog(scheme)
+}
+
+func LoadPseudonymsysUserSecret(user string) (*big.Int, error) {
+	return global.loadBigInt("pseudonymsys", user)
+}
+
+func LoadPseudonymsysOrgSecrets(org string) (*big.Int, *big.Int, error) {
+	return global.loadBigIntPair("pseudonymsys", org, "s1", "s2")
+}
+
+func LoadPseudonymsysOrgPubKeys(org string) (*big.Int, *big.Int, error) {
+	return global.loadBigIntPair("pseudonymsys", org, "h1", "h2")
+}
+
+func LoadPseudonymsysCASecret(caName string) (*big.Int, error) {
+	return global.loadBigInt("pseudonymsys", caName, "D")
+}
+
+func LoadPseudonymsysCAPubKey(caName string) (*big.Int, *big.Int, error) {
+	return global.loadBigIntPair("pseudonymsys", caName, "X", "Y")
+}
+
+// LoadTenantToken returns the token that clients present to emmy server to authenticate
+// as a tenant (see Tenant). It is empty if clients don't authenticate.
+func LoadTenantToken() string {
+	return viper.GetString("tenant_token")
+}
+
+// LoadTrusteeLabels returns the labels (for example case IDs) of ciphertexts that emmy
+// server, acting as a trustee, may decrypt under its global keys, each with the IDs of
+// the tenants that may request decryption and the threshold ciphertexts registered for
+// it. An error is returned if any of the labels lists no requesters.
+func LoadTrusteeLabels() ([]TrusteeLabel, error) {
+	labels, err := global.loadTrusteeLabels()
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		if len(label.Requesters) == 0 {
+			return nil, fmt.Errorf("trustee.labels: label %q lists no requesters", label.Label)
+		}
 	}
+	return labels, nil
+}
+
+// LoadTrusteeAuditLog returns the path of the audit log of decryption requests.
+func LoadTrusteeAuditLog() string {
+	if path := viper.GetString("trustee.audit_log"); path != "" {
+		return path
+	}
+	return filepath.Join(LoadKeyDirFromConfig(), trusteeAuditLogName)
+}
+
+// LoadTrusteeKeyShare returns the path of the threshold Paillier key share held by
+// emmy server acting as a trustee.
+func LoadTrusteeKeyShare() string {
+	if path := viper.GetString("trustee.key_share"); path != "" {
+		return path
+	}
+	return filepath.Join(LoadKeyDirFromConfig(), trusteeKeyShareName)
+}
+
+// LoadTrusteeElGamalKeyShare returns the path of the threshold ElGamal key share held
+// by emmy server acting as a trustee.
+func LoadTrusteeElGamalKeyShare() string {
+	if path := viper.GetString("trustee.elgamal_key_share"); path != "" {
+		return path
+	}
+	return filepath.Join(LoadKeyDirFromConfig(), trusteeElGamalKeyShareName)
+}
+
+// trusteeKeyShareName is the name of the key share file in the key folder, used unless
+// key_share is configured.
+const trusteeKeyShareName = "paillier-key-share.pem"
+
+// trusteeElGamalKeyShareName is the name of the threshold ElGamal key share file in the
+// key folder, used unless elgamal_key_share is configured.
+const trusteeElGamalKeyShareName = "elgamal-key-share.pem"
+
+// trusteeAuditLogName is the name of the audit log file in the key folder, used unless
+// audit_log is configured.
+const trusteeAuditLogName = "trustee-audit.log"
+
+// scope is a prefix of configuration keys. The empty scope holds the global
+// configuration, while tenants have their configuration in scope "tenants", "<id>".
+type scope []string
 
-	return &dlog
+// global is the scope of the global configuration.
+var global = scope{}
+
+// key returns the configuration key composed of the scope and the given path elements.
+func (s scope) key(path ...string) string {
+	return strings.Join(append(append([]string{}, s...), path...), ".")
 }
 
-func LoadPseudonymsysUserSecret(user string) *big.Int {
-	m := viper.GetStringMap("pseudonymsys")
-	s, _ := new(big.Int).SetString(m[user].(string), 10)
-	return s
+// isSet returns true if the configuration key composed of the given path elements is set.
+func (s scope) isSet(path ...string) bool {
+	return viper.IsSet(s.key(path...))
 }
 
-func LoadPseudonymsysOrgSecrets(org string) (*big.Int, *big.Int) {
-	m := viper.GetStringMap("pseudonymsys")
-	s1, _ := new(big.Int).SetString(m[org].(map[string]interface{})["s1"].(string), 10)
-	s2, _ := new(big.Int).SetString(m[org].(map[string]interface{})["s2"].(string), 10)
-	return s1, s2
+// section returns the configuration section with the given path, or nil if it is
+// not a section.
+func (s scope) section(path ...string) map[string]interface{} {
+	key := s.key(path...)
+	if key == "" {
+		return viper.AllSettings()
+	}
+	section, _ := viper.Get(key).(map[string]interface{})
+	return section
 }
 
-func LoadPseudonymsysOrgPubKeys(org string) (*big.Int, *big.Int) {
-	m := viper.GetStringMap("pseudonymsys")
-	h1, _ := new(big.Int).SetString(m[org].(map[string]interface{})["h1"].(string), 10)
-	h2, _ := new(big.Int).SetString(m[org].(map[string]interface{})["h2"].(string), 10)
-	return h1, h2
+// loadDLog returns the group parameters p, g and q configured for the given scheme.
+func (s scope) loadDLog(scheme string) (*dlog.ZpDLog, error) {
+	p, err := s.loadBigInt(scheme, "p")
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.loadBigInt(scheme, "g")
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.loadBigInt(scheme, "q")
+	if err != nil {
+		return nil, err
+	}
+
+	dlog := dlog.ZpDLog{
+		P:               p,
+		G:               g,
+		OrderOfSubgroup: q,
+	}
+
+	return &dlog, nil
 }
 
-func LoadPseudonymsysCASecret(caName string) *big.Int {
-	m := viper.GetStringMap("pseudonymsys")
-	s, _ := new(big.Int).SetString(m[caName].(map[string]interface{})["D"].(string), 10)
-	return s
+// loadBigInt returns the integer stored (in decimal notation) under the key composed
+// of the given path elements, for example "pedersen", "p".
+func (s scope) loadBigInt(path ...string) (*big.Int, error) {
+	key := s.key(path...)
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("Missing configuration value %v", key)
+	}
+
+	val := strings.TrimSpace(viper.GetString(key))
+	i, ok := new(big.Int).SetString(val, 10)
+	if !ok {
+		return nil, fmt.Errorf("Configuration value %v is not a decimal integer: %q", key, val)
+	}
+	return i, nil
 }
 
-func LoadPseudonymsysCAPubKey(caName string) (*big.Int, *big.Int) {
-	m := viper.GetStringMap("pseudonymsys")
-	x, _ := new(big.Int).SetString(m[caName].(map[string]interface{})["X"].(string), 10)
-	y, _ := new(big.Int).SetString(m[caName].(map[string]interface{})["Y"].(string), 10)
-	return x, y
+// loadBigIntPair returns two integers stored under the given keys of a config section.
+func (s scope) loadBigIntPair(scheme, section, key1, key2 string) (*big.Int, *big.Int, error) {
+	i1, err := s.loadBigInt(scheme, section, key1)
+	if err != nil {
+		return nil, nil, err
+	}
+	i2, err := s.loadBigInt(scheme, section, key2)
+	if err != nil {
+		return nil, nil, err
+	}
+	return i1, i2, nil
 }
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/keystore"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// StoreValues writes the given values in decimal notation as a YAML configuration
+// snippet to the file at path. The values are nested under the keys given in section,
+// for example "pseudonymsys", "org1". The file can be passed to emmy as a config file,
+// or merged into an existing one.
+func StoreValues(path string, values map[string]*big.Int, section ...string) error {
+	if err := common.Store(formatValues(values, section), path); err != nil {
+		return fmt.Errorf("Cannot write configuration file %v: %v", path, err)
+	}
+	return nil
+}
+
+// StoreSecretValues writes the given values as StoreValues does, but the file is only
+// readable by its owner and, if passphrase is not empty, encrypted (see keystore package).
+// Such a file can still be passed to emmy as a config file.
+func StoreSecretValues(path string, passphrase []byte, values map[string]*big.Int,
+	section ...string) error {
+	if err := keystore.Store(formatValues(values, section), path, passphrase); err != nil {
+		return fmt.Errorf("Cannot write configuration file %v: %v", path, err)
+	}
+	return nil
+}
+
+// formatValues returns the YAML representation of values nested under section.
+func formatValues(values map[string]*big.Int, section []string) []byte {
+	var buf bytes.Buffer
+	for i, key := range section {
+		fmt.Fprintf(&buf, "%v%q:\n", indent(i), key)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%v%q: %q\n", indent(len(section)), key, values[key].String())
+	}
+	return buf.Bytes()
+}
+
+func indent(level int) string {
+	return strings.Repeat("  ", level)
+}
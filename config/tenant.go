@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"github.com/xlab-si/emmy/dlog"
+	"math/big"
+	"path/filepath"
+	"sort"
+)
+
+// Tenant gives access to the configuration of a tenant, that is of an independent party
+// (for example a business unit) served by the same emmy deployment. Tenants are
+// configured in the tenants section:
+//
+//	tenants:
+//	  unit1:
+//	    key_folder: /var/emmy/unit1  # defaults to <key_folder>/unit1
+//	    protocols: [schnorr, cspaillier]  # defaults to all protocols
+//	    token_sha256: ["<hex encoded SHA-256 hash of the token>"]
+//	    tls_names: ["unit1.example.com"]
+//	    trustee:
+//	      labels:                   # decrypted with tenant's keys on request of the tenant
+//	        - Case-2017-41
+//	        - label: Case-2017-42
+//	          ciphertexts: ["<hex encoded hash of the ciphertext>"]  # for decryption shares
+//	      audit_log: /var/emmy/unit1/audit.log  # defaults to trustee-audit.log in key_folder
+//	      key_share: /var/emmy/unit1/share.pem  # defaults to paillier-key-share.pem in key_folder
+//	      elgamal_key_share: ...                # defaults to elgamal-key-share.pem in key_folder
+//	    schnorr:
+//	      p: ...
+//	    pseudonymsys:
+//	      org1:
+//	        h1: ...
+//
+// Group parameters missing from the tenant's section are taken from the global
+// configuration, while its pseudonymsys organizations and CAs and its trustee labels
+// are only those from its own section.
+type Tenant struct {
+	Id    string
+	scope scope
+}
+
+// LoadTenantIds returns the sorted IDs of configured tenants.
+func LoadTenantIds() []string {
+	var ids []string
+	for id := range viper.GetStringMap("tenants") {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LoadTenant returns the configuration of the tenant with the given ID.
+func LoadTenant(id string) (*Tenant, error) {
+	if _, ok := viper.GetStringMap("tenants")[id]; !ok || id == "" {
+		return nil, fmt.Errorf("Unknown tenant %q", id)
+	}
+	return &Tenant{
+		Id:    id,
+		scope: scope{"tenants", id},
+	}, nil
+}
+
+// LoadDLog returns the group parameters p, g and q configured for the given scheme,
+// either in tenant's section or in the global configuration.
+func (t *Tenant) LoadDLog(scheme string) (*dlog.ZpDLog, error) {
+	if t.scope.isSet(scheme, "p") {
+		return t.scope.loadDLog(scheme)
+	}
+	return LoadDLog(scheme)
+}
+
+// LoadKeyDir returns the folder holding tenant's keys.
+func (t *Tenant) LoadKeyDir() string {
+	if t.scope.isSet("key_folder") {
+		return viper.GetString(t.scope.key("key_folder"))
+	}
+	return filepath.Join(LoadKeyDirFromConfig(), t.Id)
+}
+
+// LoadProtocols returns the names of protocols (as in emmy client's --protocol flag)
+// that the tenant is allowed to run. It returns nil if all protocols are allowed.
+func (t *Tenant) LoadProtocols() []string {
+	return viper.GetStringSlice(t.scope.key("protocols"))
+}
+
+// LoadTokenHashes returns hex encoded SHA-256 hashes of tokens that authenticate
+// clients as the tenant.
+func (t *Tenant) LoadTokenHashes() []string {
+	return viper.GetStringSlice(t.scope.key("token_sha256"))
+}
+
+// LoadTLSNames returns the names (common names or DNS names) in client certificates
+// that authenticate clients as the tenant.
+func (t *Tenant) LoadTLSNames() []string {
+	return viper.GetStringSlice(t.scope.key("tls_names"))
+}
+
+// LoadTrusteeLabels returns the labels of ciphertexts that may be decrypted with
+// tenant's keys on request of the tenant. An error is returned if any of the labels
+// lists requesters, as they are implied.
+func (t *Tenant) LoadTrusteeLabels() ([]TrusteeLabel, error) {
+	labels, err := t.scope.loadTrusteeLabels()
+	if err != nil {
+		return nil, err
+	}
+	for i, label := range labels {
+		if len(label.Requesters) > 0 {
+			return nil, fmt.Errorf("%v: label %q of a tenant cannot list requesters",
+				t.scope.key("trustee", "labels"), label.Label)
+		}
+		labels[i].Requesters = []string{t.Id}
+	}
+	return labels, nil
+}
+
+// LoadTrusteeAuditLog returns the path of the audit log of decryption requests made
+// by the tenant.
+func (t *Tenant) LoadTrusteeAuditLog() string {
+	if path := viper.GetString(t.scope.key("trustee", "audit_log")); path != "" {
+		return path
+	}
+	return filepath.Join(t.LoadKeyDir(), trusteeAuditLogName)
+}
+
+// LoadTrusteeKeyShare returns the path of the threshold Paillier key share used to
+// issue decryption shares for the tenant.
+func (t *Tenant) LoadTrusteeKeyShare() string {
+	if path := viper.GetString(t.scope.key("trustee", "key_share")); path != "" {
+		return path
+	}
+	return filepath.Join(t.LoadKeyDir(), trusteeKeyShareName)
+}
+
+// LoadTrusteeElGamalKeyShare returns the path of the threshold ElGamal key share used
+// to issue decryption shares for the tenant.
+func (t *Tenant) LoadTrusteeElGamalKeyShare() string {
+	if path := viper.GetString(t.scope.key("trustee", "elgamal_key_share")); path != "" {
+		return path
+	}
+	return filepath.Join(t.LoadKeyDir(), trusteeElGamalKeyShareName)
+}
+
+func (t *Tenant) LoadPseudonymsysOrgSecrets(org string) (*big.Int, *big.Int, error) {
+	return t.scope.loadBigIntPair("pseudonymsys", org, "s1", "s2")
+}
+
+func (t *Tenant) LoadPseudonymsysOrgPubKeys(org string) (*big.Int, *big.Int, error) {
+	return t.scope.loadBigIntPair("pseudonymsys", org, "h1", "h2")
+}
+
+func (t *Tenant) LoadPseudonymsysCASecret(caName string) (*big.Int, error) {
+	return t.scope.loadBigInt("pseudonymsys", caName, "D")
+}
+
+func (t *Tenant) LoadPseudonymsysCAPubKey(caName string) (*big.Int, *big.Int, error) {
+	return t.scope.loadBigIntPair("pseudonymsys", caName, "X", "Y")
+}
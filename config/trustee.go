@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+)
+
+// TrusteeLabel is a label (for example a case ID) of ciphertexts that emmy server, acting
+// as a trustee, may decrypt. Labels are configured as a list, so that their case is
+// preserved (unlike keys of config sections):
+//
+//	trustee:
+//	  labels:
+//	    - label: Case-2017-42
+//	      requesters: [unit1]
+//	      ciphertexts: ["<hex encoded hash of the ciphertext>"]
+type TrusteeLabel struct {
+	Label string
+	// Requesters are the IDs of the tenants that may request decryption.
+	Requesters []string
+	// Ciphertexts are the hashes of threshold ciphertexts (see trustee.CiphertextHash)
+	// whose decryption shares may be issued under the label.
+	Ciphertexts []string
+}
+
+// loadTrusteeLabels returns the labels listed in the trustee section of scope s. Each
+// entry is either a label with its options, or just a label.
+func (s scope) loadTrusteeLabels() ([]TrusteeLabel, error) {
+	key := s.key("trustee", "labels")
+	value := viper.Get(key)
+	if value == nil {
+		return nil, nil
+	}
+	entries, ok := toSlice(value)
+	if !ok {
+		return nil, fmt.Errorf("%v: expected a list of labels", key)
+	}
+
+	labels := make([]TrusteeLabel, len(entries))
+	for i, entry := range entries {
+		if label, ok := entry.(string); ok {
+			labels[i].Label = label
+			continue
+		}
+		options, ok := toStringMap(entry)
+		if !ok {
+			return nil, fmt.Errorf("%v: invalid entry %v", key, entry)
+		}
+		labels[i].Label, ok = options["label"].(string)
+		if !ok || labels[i].Label == "" {
+			return nil, fmt.Errorf("%v: entry %v has no label", key, entry)
+		}
+		if labels[i].Requesters, ok = toStringSlice(options["requesters"]); !ok {
+			return nil, fmt.Errorf("%v: invalid requesters of label %q", key, labels[i].Label)
+		}
+		if labels[i].Ciphertexts, ok = toStringSlice(options["ciphertexts"]); !ok {
+			return nil, fmt.Errorf("%v: invalid ciphertexts of label %q", key, labels[i].Label)
+		}
+	}
+	return labels, nil
+}
+
+// toSlice returns value as a slice, if it is a slice.
+func toSlice(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = e
+		}
+		return s, true
+	default:
+		return nil, false
+	}
+}
+
+// toStringSlice returns value as a slice of strings, if it is a (possibly missing) list
+// of strings.
+func toStringSlice(value interface{}) ([]string, bool) {
+	if value == nil {
+		return nil, true
+	}
+	elements, ok := toSlice(value)
+	if !ok {
+		return nil, false
+	}
+	s := make([]string, len(elements))
+	for i, e := range elements {
+		if s[i], ok = e.(string); !ok {
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+// toStringMap returns value as a map with string keys, if it is a map.
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, e := range v {
+			m[fmt.Sprint(key)] = e
+		}
+		return m, true
+	default:
+		return nil, false
+	}
+}
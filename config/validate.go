@@ -0,0 +1,236 @@
+package config
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"github.com/xlab-si/emmy/dlog"
+	"math/big"
+	"sort"
+)
+
+// primalityTestRounds is the number of Miller-Rabin rounds used when checking
+// that the configured group parameters are prime.
+const primalityTestRounds = 20
+
+// Validate checks the consistency of all configured group parameters, pseudonymsys
+// organization keys, CA keys and trustee labels, globally and for each tenant. It
+// returns an error for each problem found, or nil if the configuration is valid.
+func Validate() []error {
+	problems := validateScope(global, LoadDLog)
+	if _, err := LoadTrusteeLabels(); err != nil {
+		problems = append(problems, err)
+	}
+	for _, id := range LoadTenantIds() {
+		tenant, err := LoadTenant(id)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		problems = append(problems, validateScope(tenant.scope, tenant.LoadDLog)...)
+		if _, err := tenant.LoadTrusteeLabels(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// validateScope validates group parameters and pseudonymsys keys configured in scope s.
+// loadDLog returns the group parameters that apply to s.
+func validateScope(s scope, loadDLog func(string) (*dlog.ZpDLog, error)) []error {
+	var problems []error
+	for _, scheme := range groupSchemes(s) {
+		problems = append(problems, validateGroup(s, scheme)...)
+	}
+	if group, err := loadDLog("pseudonymsys"); err == nil {
+		// missing group parameters are reported when validating groups
+		problems = append(problems, validatePseudonymsys(s, group)...)
+	}
+	return problems
+}
+
+// groupSchemes returns the (sorted) names of config sections in scope s holding group
+// parameters.
+func groupSchemes(s scope) []string {
+	var schemes []string
+	for key, val := range s.section() {
+		section, ok := val.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, param := range []string{"p", "g", "q"} {
+			if _, ok := section[param]; ok {
+				schemes = append(schemes, key)
+				break
+			}
+		}
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// validateGroup checks that p and q are prime, q divides p-1 and g generates
+// a subgroup of order q in Z_p*.
+func validateGroup(s scope, scheme string) []error {
+	group, err := s.loadDLog(scheme)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	report := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Errorf("%v: %v", s.key(scheme), fmt.Sprintf(format, a...)))
+	}
+
+	p, g, q := group.P, group.G, group.OrderOfSubgroup
+	if !p.ProbablyPrime(primalityTestRounds) {
+		report("p is not prime")
+	}
+	if !q.ProbablyPrime(primalityTestRounds) {
+		report("q is not prime")
+	}
+	pMinusOne := new(big.Int).Sub(p, big.NewInt(1))
+	if q.Sign() <= 0 || new(big.Int).Mod(pMinusOne, q).Sign() != 0 {
+		report("q does not divide p-1")
+	}
+	if g.Cmp(big.NewInt(1)) <= 0 || g.Cmp(p) >= 0 {
+		report("g is not in the range (1, p)")
+	} else if !inSubgroup(group, g) {
+		report("g is not of order q")
+	}
+	return problems
+}
+
+// validatePseudonymsys checks that the keys of pseudonymsys organizations are in the
+// pseudonymsys group and match their secrets, that CA keys are valid P-256 keys, and
+// that user secrets are not trivial.
+func validatePseudonymsys(s scope, group *dlog.ZpDLog) []error {
+	settings := s.section("pseudonymsys")
+	if len(settings) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []error
+	for _, name := range names {
+		switch val := settings[name].(type) {
+		case map[string]interface{}:
+			if isCASection(val) {
+				problems = append(problems, validateCA(s, name)...)
+			} else {
+				problems = append(problems, validateOrg(s, group, name, val)...)
+			}
+		default:
+			if name == "p" || name == "g" || name == "q" {
+				continue
+			}
+			secret, err := s.loadBigInt("pseudonymsys", name)
+			if err != nil {
+				problems = append(problems, err)
+			} else if isTrivialExponent(secret, group) {
+				problems = append(problems, fmt.Errorf("%v: secret is 0 modulo q", s.key("pseudonymsys", name)))
+			}
+		}
+	}
+	return problems
+}
+
+// validateOrg checks that organization's public keys h1, h2 are in the pseudonymsys group
+// and, if secrets are configured as well, that h1 = g^s1 and h2 = g^s2.
+func validateOrg(s scope, group *dlog.ZpDLog, org string, section map[string]interface{}) []error {
+	h1, h2, err := s.loadBigIntPair("pseudonymsys", org, "h1", "h2")
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	report := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Errorf("%v: %v", s.key("pseudonymsys", org), fmt.Sprintf(format, a...)))
+	}
+
+	_, hasS1 := section["s1"]
+	_, hasS2 := section["s2"]
+	if !hasS1 && !hasS2 {
+		for i, h := range []*big.Int{h1, h2} {
+			if !inSubgroup(group, h) {
+				report("h%d is not in the subgroup of order q", i+1)
+			}
+		}
+		return problems
+	}
+
+	s1, s2, err := s.loadBigIntPair("pseudonymsys", org, "s1", "s2")
+	if err != nil {
+		return []error{err}
+	}
+	for i, keys := range [][]*big.Int{{s1, h1}, {s2, h2}} {
+		s, h := keys[0], keys[1]
+		if isTrivialExponent(s, group) {
+			report("s%d is 0 modulo q", i+1)
+		}
+		if expected, _ := group.ExponentiateBaseG(s); expected.Cmp(h) != 0 {
+			report("h%d does not equal g^s%d", i+1, i+1)
+		}
+	}
+	return problems
+}
+
+// validateCA checks that CA's public key (X, Y) is a point on P-256 curve and, if the
+// secret key D is configured as well, that it corresponds to the public key.
+func validateCA(s scope, caName string) []error {
+	x, y, err := s.loadBigIntPair("pseudonymsys", caName, "X", "Y")
+	if err != nil {
+		return []error{err}
+	}
+
+	section := s.key("pseudonymsys", caName)
+	curve := elliptic.P256()
+	if !curve.IsOnCurve(x, y) {
+		return []error{fmt.Errorf("%v: public key is not a point on P-256 curve", section)}
+	}
+
+	if !s.isSet("pseudonymsys", caName, "D") {
+		return nil
+	}
+	d, err := s.loadBigInt("pseudonymsys", caName, "D")
+	if err != nil {
+		return []error{err}
+	}
+	if !inRange(d, curve.Params().N) {
+		return []error{fmt.Errorf("%v: secret key D is not in the range (0, N)", section)}
+	}
+	if pubX, pubY := curve.ScalarBaseMult(d.Bytes()); pubX.Cmp(x) != 0 || pubY.Cmp(y) != 0 {
+		return []error{fmt.Errorf("%v: secret key D does not match public key (X, Y)", section)}
+	}
+	return nil
+}
+
+// isCASection returns true if the config section holds (public or secret) ECDSA key of a CA.
+func isCASection(section map[string]interface{}) bool {
+	_, hasX := section["x"]
+	_, hasD := section["d"]
+	return hasX || hasD
+}
+
+// inSubgroup returns true if x is an element of the subgroup of order q.
+func inSubgroup(group *dlog.ZpDLog, x *big.Int) bool {
+	if x.Sign() <= 0 || x.Cmp(group.P) >= 0 {
+		return false
+	}
+	xToQ := new(big.Int).Exp(x, group.OrderOfSubgroup, group.P)
+	return xToQ.Cmp(big.NewInt(1)) == 0
+}
+
+// isTrivialExponent returns true if g^x is 1 for the generator g of the group.
+func isTrivialExponent(x *big.Int, group *dlog.ZpDLog) bool {
+	return new(big.Int).Mod(x, group.OrderOfSubgroup).Sign() == 0
+}
+
+// inRange returns true if 0 < x < max.
+func inRange(x, max *big.Int) bool {
+	return x.Sign() > 0 && x.Cmp(max) < 0
+}
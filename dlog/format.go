@@ -0,0 +1,74 @@
+package dlog
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"github.com/xlab-si/emmy/common"
+)
+
+// Types of encoded group parameters (see common.MarshalVersioned).
+const (
+	ZpDLogType = "zp-group"
+	ECDLogType = "ec-group"
+)
+
+type zpDLogJSON struct {
+	P *common.Int `json:"p"`
+	G *common.Int `json:"g"`
+	Q *common.Int `json:"q"`
+}
+
+// MarshalJSON encodes group parameters p, g and q.
+func (dlog *ZpDLog) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ZpDLogType, &zpDLogJSON{
+		P: common.NewInt(dlog.P),
+		G: common.NewInt(dlog.G),
+		Q: common.NewInt(dlog.OrderOfSubgroup),
+	})
+}
+
+func (dlog *ZpDLog) UnmarshalJSON(data []byte) error {
+	var v zpDLogJSON
+	if err := common.UnmarshalVersioned(data, ZpDLogType, &v); err != nil {
+		return err
+	}
+	dlog.P = v.P.BigInt()
+	dlog.G = v.G.BigInt()
+	dlog.OrderOfSubgroup = v.Q.BigInt()
+	return nil
+}
+
+type ecDLogJSON struct {
+	Curve string `json:"curve"`
+}
+
+// MarshalJSON encodes the name of the (standard) elliptic curve, for example "P-224".
+func (dlog *ECDLog) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ECDLogType, &ecDLogJSON{
+		Curve: dlog.Curve.Params().Name,
+	})
+}
+
+func (dlog *ECDLog) UnmarshalJSON(data []byte) error {
+	var v ecDLogJSON
+	if err := common.UnmarshalVersioned(data, ECDLogType, &v); err != nil {
+		return err
+	}
+
+	var curve elliptic.Curve
+	switch v.Curve {
+	case "P-224":
+		curve = elliptic.P224()
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return fmt.Errorf("Unsupported elliptic curve: %q", v.Curve)
+	}
+	dlog.Curve = curve
+	dlog.OrderOfSubgroup = curve.Params().N
+	return nil
+}
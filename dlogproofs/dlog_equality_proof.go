@@ -0,0 +1,119 @@
+package dlogproofs
+
+import (
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"math/big"
+)
+
+// DLogEqualityProof is a non-interactive (Fiat-Shamir) variant of the Chaum-Pedersen
+// proof run by DLogEqualityProver and DLogEqualityVerifier: it proves that
+// log_g1(t1) = log_g2(t2), where the challenge is a hash of the statement and of the
+// prover's first message instead of being chosen by the verifier.
+type DLogEqualityProof struct {
+	X1 *big.Int // g1^r
+	X2 *big.Int // g2^r
+	Z  *big.Int // r + challenge * secret
+}
+
+// ProveDLogEquality returns a proof that log_g1(t1) = log_g2(t2) = secret.
+func ProveDLogEquality(secret, g1, g2, t1, t2 *big.Int, dlog *dlog.ZpDLog) *DLogEqualityProof {
+	prover := NewDLogEqualityProver(dlog)
+	x1, x2 := prover.GetProofRandomData(secret, g1, g2)
+	challenge := getDLogEqualityChallenge(dlog.GetOrderOfSubgroup(), dlog.P, g1, g2, t1, t2, x1, x2)
+	return &DLogEqualityProof{
+		X1: x1,
+		X2: x2,
+		Z:  prover.GetProofData(challenge),
+	}
+}
+
+// VerifyDLogEquality verifies the proof that log_g1(t1) = log_g2(t2).
+func VerifyDLogEquality(proof *DLogEqualityProof, g1, g2, t1, t2 *big.Int, dlog *dlog.ZpDLog) bool {
+	if proof == nil || proof.X1 == nil || proof.X2 == nil || proof.Z == nil {
+		return false
+	}
+	for _, x := range []*big.Int{g1, g2, t1, t2, proof.X1, proof.X2} {
+		if x.Sign() <= 0 || x.Cmp(dlog.P) >= 0 {
+			return false
+		}
+	}
+	verifier := NewDLogEqualityVerifier(dlog)
+	verifier.g1, verifier.g2 = g1, g2
+	verifier.t1, verifier.t2 = t1, t2
+	verifier.x1, verifier.x2 = proof.X1, proof.X2
+	verifier.challenge = getDLogEqualityChallenge(dlog.GetOrderOfSubgroup(), dlog.P, g1, g2, t1, t2,
+		proof.X1, proof.X2)
+	return verifier.Verify(proof.Z)
+}
+
+// DLogEqualityECProof is DLogEqualityProof for elliptic curve groups.
+type DLogEqualityECProof struct {
+	X1 *common.ECGroupElement // g1^r
+	X2 *common.ECGroupElement // g2^r
+	Z  *big.Int               // r + challenge * secret
+}
+
+// ProveDLogEqualityEC returns a proof that log_g1(t1) = log_g2(t2) = secret.
+func ProveDLogEqualityEC(secret *big.Int, g1, g2, t1, t2 *common.ECGroupElement,
+	dlog *dlog.ECDLog) *DLogEqualityECProof {
+	r := common.GetRandomInt(dlog.GetOrderOfSubgroup())
+	x1X, x1Y := dlog.Exponentiate(g1.X, g1.Y, r)
+	x2X, x2Y := dlog.Exponentiate(g2.X, g2.Y, r)
+	x1 := &common.ECGroupElement{X: x1X, Y: x1Y}
+	x2 := &common.ECGroupElement{X: x2X, Y: x2Y}
+
+	challenge := getDLogEqualityChallenge(dlog.GetOrderOfSubgroup(), dlog.Curve.Params().P,
+		ecCoordinates(g1, g2, t1, t2, x1, x2)...)
+	z := new(big.Int).Mul(challenge, secret)
+	z.Add(z, r)
+	z.Mod(z, dlog.GetOrderOfSubgroup())
+	return &DLogEqualityECProof{
+		X1: x1,
+		X2: x2,
+		Z:  z,
+	}
+}
+
+// VerifyDLogEqualityEC verifies the proof that log_g1(t1) = log_g2(t2).
+func VerifyDLogEqualityEC(proof *DLogEqualityECProof, g1, g2, t1, t2 *common.ECGroupElement,
+	dlog *dlog.ECDLog) bool {
+	if proof == nil || proof.Z == nil {
+		return false
+	}
+	for _, x := range []*common.ECGroupElement{g1, g2, t1, t2, proof.X1, proof.X2} {
+		if x == nil || x.X == nil || x.Y == nil || !dlog.Curve.IsOnCurve(x.X, x.Y) {
+			return false
+		}
+	}
+	challenge := getDLogEqualityChallenge(dlog.GetOrderOfSubgroup(), dlog.Curve.Params().P,
+		ecCoordinates(g1, g2, t1, t2, proof.X1, proof.X2)...)
+
+	// check if g1^z = x1 * t1^challenge and g2^z = x2 * t2^challenge
+	for _, pair := range [][3]*common.ECGroupElement{{g1, t1, proof.X1}, {g2, t2, proof.X2}} {
+		g, t, x := pair[0], pair[1], pair[2]
+		leftX, leftY := dlog.Exponentiate(g.X, g.Y, proof.Z)
+		rX, rY := dlog.Exponentiate(t.X, t.Y, challenge)
+		rightX, rightY := dlog.Multiply(rX, rY, x.X, x.Y)
+		if leftX.Cmp(rightX) != 0 || leftY.Cmp(rightY) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// getDLogEqualityChallenge returns the hash of the group modulus and the given values,
+// reduced modulo the order of the group.
+func getDLogEqualityChallenge(order, modulus *big.Int, values ...*big.Int) *big.Int {
+	hashed := append([]*big.Int{modulus}, values...)
+	return new(big.Int).Mod(common.Hash(hashed...), order)
+}
+
+// ecCoordinates returns the coordinates of the given points.
+func ecCoordinates(points ...*common.ECGroupElement) []*big.Int {
+	coordinates := make([]*big.Int, 0, 2*len(points))
+	for _, p := range points {
+		coordinates = append(coordinates, p.X, p.Y)
+	}
+	return coordinates
+}
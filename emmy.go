@@ -9,6 +9,8 @@ import (
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/config"
 	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/encryption"
+	"github.com/xlab-si/emmy/gateway"
 	"github.com/xlab-si/emmy/log"
 	pb "github.com/xlab-si/emmy/protobuf"
 	"github.com/xlab-si/emmy/server"
@@ -18,19 +20,32 @@ import (
 	"net"
 	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
 var cLogger = log.ClientLogger
 var sLogger = log.ServerLogger
 
-func main() {
-	// endpoint where emmy clients will contact emmy server
-	emmyServerEndpoint := config.LoadServerEndpoint()
+// healthCheckInterval is the interval at which emmy server re-evaluates its readiness.
+const healthCheckInterval = 30 * time.Second
+
+// cspaillierKeyId is the ID of the server's key that cspaillier clients use.
+var cspaillierKeyId string
+
+// defaultCSPaillierSecParams are the security parameters of CSPaillier keys that emmy
+// server generates when it has none.
+var defaultCSPaillierSecParams = encryption.CSPaillierSecParams{
+	L:        512,
+	RoLength: 160,
+	K:        158,
+	K1:       158,
+}
 
+func main() {
 	// whether to run clients concurrently or not
 	var runConcurrently bool
 
@@ -44,6 +59,30 @@ func main() {
 	app.Name = "emmy"
 	app.Version = "0.1"
 	app.Usage = "A CLI app for running emmy server, emmy clients and examples of proofs offered by the emmy library"
+	app.Flags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "config",
+			Usage: "Path to the config file (by default, emmy.yml is looked up in ., $HOME/.emmy and /etc/emmy). " +
+				"Can be repeated, later files override values from earlier ones",
+		},
+		cli.StringFlag{
+			Name:  "ip",
+			Usage: "IP of emmy server that clients connect to",
+		},
+		cli.IntFlag{
+			Name:  "port",
+			Usage: "Port of emmy server",
+		},
+		cli.Float64Flag{
+			Name:  "timeout",
+			Usage: "Timeout (in seconds) for connections to emmy server",
+		},
+		cli.StringFlag{
+			Name:  "keyfolder",
+			Usage: "Folder where keys are stored",
+		},
+	}
+	app.Before = loadConfig
 
 	serverApp := cli.Command{
 		Name:  "server",
@@ -82,13 +121,18 @@ func main() {
 			Name:        "concurrent",
 			Destination: &runConcurrently,
 		},
+		cli.StringFlag{
+			Name:        "keyid",
+			Usage:       "ID of the server's key (cspaillier only), the default key is used if not given",
+			Destination: &cspaillierKeyId,
+		},
 	}
 	clientApp := cli.Command{
 		Name:  "client",
 		Usage: "A client that wants to prove something to the verifier (server)",
 		Flags: clientFlags,
 		Action: func(ctx *cli.Context) error {
-			runClients(n, runConcurrently, protocolType, protocolVariant, emmyServerEndpoint)
+			runClients(n, runConcurrently, protocolType, protocolVariant, config.LoadServerEndpoint())
 			return nil
 		},
 	}
@@ -100,18 +144,85 @@ func main() {
 		Flags: clientFlags,
 		Action: func(ctx *cli.Context) error {
 			go startEmmyServer()
-			runClients(n, runConcurrently, protocolType, protocolVariant, emmyServerEndpoint)
+			runClients(n, runConcurrently, protocolType, protocolVariant, config.LoadServerEndpoint())
 			return nil
 		},
 	}
 
-	app.Commands = []cli.Command{serverApp, clientApp, exampleApp}
-	app.Run(os.Args)
+	configApp := cli.Command{
+		Name:  "config",
+		Usage: "Commands for inspecting emmy configuration",
+		Subcommands: []cli.Command{
+			{
+				Name:  "validate",
+				Usage: "Checks consistency of configured group parameters and keys",
+				Action: func(ctx *cli.Context) error {
+					return validateConfig()
+				},
+			},
+		},
+	}
+
+	app.Commands = []cli.Command{serverApp, clientApp, exampleApp, configApp, keygenCommand(), shareCommand()}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig reads in the config file and overrides configured values with those
+// passed as command line flags.
+func loadConfig(ctx *cli.Context) error {
+	if ctx.IsSet("config") {
+		for _, path := range ctx.StringSlice("config") {
+			if err := config.LoadConfigFile(path); err != nil {
+				return err
+			}
+		}
+	} else if err := config.LoadConfigFromSearchPaths(); err != nil {
+		return err
+	}
+
+	if ctx.IsSet("ip") {
+		config.Set("ip", ctx.String("ip"))
+	}
+	if ctx.IsSet("port") {
+		config.Set("port", ctx.Int("port"))
+	}
+	if ctx.IsSet("timeout") {
+		config.Set("timeout", ctx.Float64("timeout"))
+	}
+	if ctx.IsSet("keyfolder") {
+		config.Set("key_folder", ctx.String("keyfolder"))
+	}
+	return nil
+}
+
+// validateConfig reports problems with the configured group parameters and keys.
+func validateConfig() error {
+	problems := config.Validate()
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("Found %d problem(s) in configuration", len(problems))
+	}
+
+	fmt.Println("Configuration is valid")
+	return nil
 }
 
 // runClients runs emmy clients for the chosen protocol either concurrently or
-// sequentially and times the execution.
+// sequentially and times the execution. All clients share a single connection
+// to emmy server.
 func runClients(n int, concurrently bool, protocolType, protocolVariant, endpoint string) {
+	conn, err := client.NewConnection(endpoint)
+	if err != nil {
+		cLogger.Criticalf("%v", err)
+		return
+	}
+	defer conn.Close()
+
 	var wg sync.WaitGroup
 	start := time.Now()
 	for i := 0; i < n; i++ {
@@ -120,10 +231,10 @@ func runClients(n int, concurrently bool, protocolType, protocolVariant, endpoin
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				runClient(protocolType, protocolVariant, endpoint)
+				runClient(conn, protocolType, protocolVariant)
 			}()
 		} else {
-			runClient(protocolType, protocolVariant, endpoint)
+			runClient(conn, protocolType, protocolVariant)
 		}
 	}
 	wg.Wait()
@@ -131,65 +242,88 @@ func runClients(n int, concurrently bool, protocolType, protocolVariant, endpoin
 	cLogger.Noticef("Time: %v seconds", elapsed.Seconds())
 }
 
-// runClient creates a client for the chosen protocol and executes it.
+// runClient creates a client for the chosen protocol and executes it on a new
+// stream of the given connection.
 // Parameters passed to the client in client.ProtocolParams struct have fixed
 // values for demonstration purposes.
-func runClient(protocolType, protocolVariant, endpoint string) {
+func runClient(conn *client.Connection, protocolType, protocolVariant string) {
 	_, pbVariant, err := parseSchema(protocolType, protocolVariant)
 	if err != nil {
 		cLogger.Criticalf("%v", err)
 		return
 	}
 
+	t, err := conn.NewTransport()
+	if err != nil {
+		cLogger.Criticalf("%v", err)
+		return
+	}
+
 	switch protocolType {
 	case "pedersen":
 		commitVal := big.NewInt(121212121)
-		dlog := config.LoadDLog("pedersen")
-		client, err := client.NewPedersenClient(endpoint, pbVariant, dlog, commitVal)
+		dlog, err := config.LoadDLog("pedersen")
+		if err != nil {
+			cLogger.Criticalf("%v", err)
+			t.Close()
+			return
+		}
+		client, err := client.NewPedersenClient(t, pbVariant, dlog, commitVal)
 		if err != nil {
 			cLogger.Errorf("Error creating client: %v", err)
+			t.Close()
 		} else {
 			err = client.Run()
 		}
 	case "pedersen_ec":
 		commitVal := big.NewInt(121212121)
-		client, err := client.NewPedersenECClient(endpoint, commitVal)
+		client, err := client.NewPedersenECClient(t, commitVal)
 		if err != nil {
 			cLogger.Errorf("Error creating client: %v", err)
+			t.Close()
 		} else {
 			err = client.Run()
 		}
 	case "schnorr":
-		dlog := config.LoadDLog("schnorr")
+		dlog, err := config.LoadDLog("schnorr")
+		if err != nil {
+			cLogger.Criticalf("%v", err)
+			t.Close()
+			return
+		}
 		secret := big.NewInt(345345345334)
-		client, err := client.NewSchnorrClient(endpoint, pbVariant, dlog, secret)
+		client, err := client.NewSchnorrClient(t, pbVariant, dlog, secret)
 		if err != nil {
 			cLogger.Errorf("Error creating client: %v", err)
+			t.Close()
 		} else {
 			err = client.Run()
 		}
 	case "schnorr_ec":
 		ec_dlog := dlog.NewECDLog()
 		secret := big.NewInt(345345345334)
-		client, err := client.NewSchnorrECClient(endpoint, pbVariant, ec_dlog, secret)
+		client, err := client.NewSchnorrECClient(t, pbVariant, ec_dlog, secret)
 		if err != nil {
 			cLogger.Errorf("Error creating client: %v", err)
+			t.Close()
 		} else {
 			err = client.Run()
 		}
 	case "cspaillier":
-		keyDir := config.LoadKeyDirFromConfig()
-		pubKeyPath := filepath.Join(keyDir, "cspaillierpubkey.txt")
+		// the public key is fetched from emmy server
 		m := common.GetRandomInt(big.NewInt(8685849))
 		label := common.GetRandomInt(big.NewInt(340002223232))
-		client, err := client.NewCSPaillierClient(endpoint, pubKeyPath, m, label)
+		client, err := client.NewCSPaillierClient(t, "", m, label)
 		if err != nil {
 			cLogger.Errorf("Error creating client: %v", err)
+			t.Close()
 		} else {
+			client.SetKeyId(cspaillierKeyId)
 			err = client.Run()
 		}
 	default:
 		cLogger.Criticalf("ERROR: Invalid protocol type: %s", protocolType)
+		t.Close()
 		return
 	}
 
@@ -240,14 +374,48 @@ func startEmmyServer() {
 
 	// Register our generic service
 	sLogger.Info("Registering services")
-	pb.RegisterProtocolServer(emmyServer, server.NewProtocolServer())
+	protocolServer := server.NewProtocolServer()
+	if err := protocolServer.GenerateMissingKeys(&defaultCSPaillierSecParams); err != nil {
+		sLogger.Criticalf("%v", err)
+	}
+	pb.RegisterProtocolServer(emmyServer, protocolServer)
+	go reloadKeysOnSignal(protocolServer)
+
+	// Register standard health service, reporting whether configured keys and group
+	// parameters were loaded successfully, and server reflection
+	healthServer := server.RegisterHealthAndReflection(emmyServer, protocolServer)
+	go server.MonitorHealth(healthServer, protocolServer, healthCheckInterval)
 
 	// Enable debugging
 	grpc_prometheus.Register(emmyServer)
 	http.Handle("/metrics", prometheus.Handler())
 	go http.ListenAndServe(":8881", nil)
 
+	// Serve the same protocols over HTTP/JSON and WebSockets for clients without
+	// gRPC support
+	gatewayPort := config.LoadGatewayPort()
+	gw := gateway.NewGateway(protocolServer)
+	mux := http.NewServeMux()
+	mux.Handle("/sessions", gw)
+	mux.Handle("/sessions/", gw)
+	mux.Handle("/ws", protocolServer.WebSocketHandler())
+	sLogger.Infof("Emmy HTTP/JSON gateway listening on port %d", gatewayPort)
+	go http.ListenAndServe(fmt.Sprintf(":%d", gatewayPort), mux)
+
 	// From here on, gRPC server will accept connections
 	sLogger.Infof("Emmy server listening for connections on port %d", port)
 	emmyServer.Serve(listener)
 }
+
+// reloadKeysOnSignal reloads the keys of emmy server s whenever the process receives
+// SIGHUP, so that keys can be rotated without restarting the server.
+func reloadKeysOnSignal(s *server.Server) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	for range sighup {
+		sLogger.Info("Received SIGHUP, reloading keys")
+		if err := s.ReloadKeys(); err != nil {
+			sLogger.Warning(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package encryption
+
+import (
+	"math/big"
+)
+
+// crtModulus speeds up exponentiation modulo n^2 for n = p * q with known primes p
+// and q: x^y mod n^2 is computed from x^y mod p^2 and x^y mod q^2 (with exponents
+// reduced modulo the orders of Z_{p^2}* and Z_{q^2}*), which are combined with the
+// Chinese remainder theorem. Both exponentiations work with numbers of half the size,
+// which makes them about four times faster in total.
+type crtModulus struct {
+	n     *big.Int
+	p2    *big.Int // p^2
+	q2    *big.Int // q^2
+	phiP2 *big.Int // p * (p - 1), the order of Z_{p^2}*
+	phiQ2 *big.Int // q * (q - 1), the order of Z_{q^2}*
+	q2Inv *big.Int // q^-2 mod p^2
+}
+
+// newCRTModulus returns crtModulus for n = p * q.
+func newCRTModulus(p, q *big.Int) *crtModulus {
+	p2 := new(big.Int).Mul(p, p)
+	q2 := new(big.Int).Mul(q, q)
+	return &crtModulus{
+		n:     new(big.Int).Mul(p, q),
+		p2:    p2,
+		q2:    q2,
+		phiP2: new(big.Int).Mul(p, new(big.Int).Sub(p, big.NewInt(1))),
+		phiQ2: new(big.Int).Mul(q, new(big.Int).Sub(q, big.NewInt(1))),
+		q2Inv: new(big.Int).ModInverse(q2, p2),
+	}
+}
+
+// exp returns x^y mod n^2 for y >= 0.
+func (m *crtModulus) exp(x, y *big.Int) *big.Int {
+	if new(big.Int).GCD(nil, nil, x, m.n).Cmp(big.NewInt(1)) != 0 {
+		// exponents cannot be reduced for x outside of Z_{n^2}*
+		n2 := new(big.Int).Mul(m.p2, m.q2)
+		return new(big.Int).Exp(x, y, n2)
+	}
+	xp := new(big.Int).Exp(x, new(big.Int).Mod(y, m.phiP2), m.p2)
+	xq := new(big.Int).Exp(x, new(big.Int).Mod(y, m.phiQ2), m.q2)
+	return m.combine(xp, xq)
+}
+
+// combine returns the number modulo n^2, which is xp modulo p^2 and xq modulo q^2.
+func (m *crtModulus) combine(xp, xq *big.Int) *big.Int {
+	// x = xq + q^2 * ((xp - xq) * q^-2 mod p^2)
+	t := new(big.Int).Sub(xp, xq)
+	t.Mul(t, m.q2Inv)
+	t.Mod(t, m.p2)
+	t.Mul(t, m.q2)
+	return t.Add(t, xq)
+}
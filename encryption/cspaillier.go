@@ -5,6 +5,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/keystore"
 	pb "github.com/xlab-si/emmy/protobuf"
 	"log"
 	"math/big"
@@ -19,6 +20,7 @@ type CSPaillier struct {
 	n1        *big.Int // n'
 	PubKey    *CSPaillierPubKey
 	SecretKey *CSPaillierSecretKey
+	crt       *crtModulus // for decryption, if the secret key contains p and q
 	// verifierRandomData: encryptor stores s, r1, s1, m1;
 	verifierRandomData *CSPaillierVerifierRandomData
 	// proverRandomData stores c, u1, e1, v1, delta1, l1
@@ -41,6 +43,10 @@ type CSPaillierSecretKey struct {
 	X1 *big.Int
 	X2 *big.Int
 	X3 *big.Int
+	// P and Q are the prime factors of N, used for faster decryption. They are nil
+	// for keys generated by older versions of emmy.
+	P *big.Int
+	Q *big.Int
 	// the parameters below are for verifiable encryption
 	Gamma                *dlog.ZpDLog // for discrete logarithm
 	VerifiableEncGroupN  *big.Int
@@ -108,11 +114,21 @@ func NewCSPaillier(secParams *CSPaillierSecParams) *CSPaillier {
 	return &cspaillier
 }
 
+// NewCSPaillierFromSecKey returns CSPaillier with the secret key stored at path, either
+// protobuf (see StoreSecKey), JSON or PEM encoded. If the key is encrypted, the passphrase
+// is obtained with keystore.Passphrase.
 func NewCSPaillierFromSecKey(path string) (*CSPaillier, error) {
-	bytes, err := common.Load(path)
+	bytes, err := keystore.Load(path)
 	if err != nil {
 		return nil, err
 	}
+	if common.IsJSONOrPEM(bytes) {
+		var secKey CSPaillierSecretKey
+		if err := common.UnmarshalJSONOrPEM(bytes, &secKey); err != nil {
+			return nil, err
+		}
+		return NewCSPaillierFromSecretKey(&secKey), nil
+	}
 	sKey := &pb.CSPaillierSecretKey{}
 	err = proto.Unmarshal(bytes, sKey)
 	if err != nil {
@@ -124,7 +140,7 @@ func NewCSPaillierFromSecKey(path string) (*CSPaillier, error) {
 		G:               new(big.Int).SetBytes(sKey.DLogG),
 		OrderOfSubgroup: new(big.Int).SetBytes(sKey.DLogQ),
 	}
-	secKey := CSPaillierSecretKey{
+	secKey := &CSPaillierSecretKey{
 		N:                    new(big.Int).SetBytes(sKey.N),
 		G:                    new(big.Int).SetBytes(sKey.G),
 		X1:                   new(big.Int).SetBytes(sKey.X1),
@@ -137,20 +153,32 @@ func NewCSPaillierFromSecKey(path string) (*CSPaillier, error) {
 		K:                    int(sKey.K),
 		K1:                   int(sKey.K1),
 	}
+	if len(sKey.P) > 0 && len(sKey.Q) > 0 {
+		secKey.P = new(big.Int).SetBytes(sKey.P)
+		secKey.Q = new(big.Int).SetBytes(sKey.Q)
+	}
+
+	return NewCSPaillierFromSecretKey(secKey), nil
+}
 
+// NewCSPaillierFromSecretKey returns CSPaillier with the given secret key and the
+// corresponding public key. If the key contains the prime factors of N, they are used
+// for faster decryption.
+func NewCSPaillierFromSecretKey(secKey *CSPaillierSecretKey) *CSPaillier {
 	var cspaillier CSPaillier
 	cspaillier = CSPaillier{
-		SecretKey: &secKey,
-	}
-
-	pKey := &CSPaillierPubKey{
-		N: secKey.N,
+		SecretKey: secKey,
+		PubKey:    secKey.GetPubKey(),
 	}
-	cspaillier.PubKey = pKey // Abs is used also in decrypt where PubKey is called
+	cspaillier.precompute()
 
-	return &cspaillier, nil
+	return &cspaillier
 }
 
+// NewCSPaillierFromPubKey returns CSPaillier with the given public key. It can encrypt
+// and prove that a ciphertext encrypts a discrete logarithm, as well as verify such
+// proofs (interactively with SetVerifierEncData, GetChallenge, SetProofRandomData and
+// Verify, or non-interactively with VerifyProof), but it cannot decrypt.
 func NewCSPaillierFromPubKey(pubKey *CSPaillierPubKey) *CSPaillier {
 	var cspaillier CSPaillier
 
@@ -161,23 +189,37 @@ func NewCSPaillierFromPubKey(pubKey *CSPaillierPubKey) *CSPaillier {
 	return &cspaillier
 }
 
+// NewCSPaillierFromPubKeyFile returns CSPaillier with the public key stored at path, either
+// protobuf (see StorePubKey), JSON or PEM encoded.
 func NewCSPaillierFromPubKeyFile(path string) (*CSPaillier, error) {
 	bytes, err := common.Load(path)
 	if err != nil {
 		return nil, err
 	}
+	if common.IsJSONOrPEM(bytes) {
+		var pubKey CSPaillierPubKey
+		if err := common.UnmarshalJSONOrPEM(bytes, &pubKey); err != nil {
+			return nil, err
+		}
+		return NewCSPaillierFromPubKey(&pubKey), nil
+	}
 	pKey := &pb.CSPaillierPubKey{}
 	err = proto.Unmarshal(bytes, pKey)
 	if err != nil {
 		return nil, err
 	}
 
+	return NewCSPaillierFromPubKey(ToCSPaillierPubKey(pKey)), nil
+}
+
+// ToCSPaillierPubKey returns the public key encoded in pKey.
+func ToCSPaillierPubKey(pKey *pb.CSPaillierPubKey) *CSPaillierPubKey {
 	gamma := dlog.ZpDLog{
 		P:               new(big.Int).SetBytes(pKey.DLogP),
 		G:               new(big.Int).SetBytes(pKey.DLogG),
 		OrderOfSubgroup: new(big.Int).SetBytes(pKey.DLogQ),
 	}
-	pubKey := CSPaillierPubKey{
+	return &CSPaillierPubKey{
 		N:                    new(big.Int).SetBytes(pKey.N),
 		G:                    new(big.Int).SetBytes(pKey.G),
 		Y1:                   new(big.Int).SetBytes(pKey.Y1),
@@ -190,17 +232,56 @@ func NewCSPaillierFromPubKeyFile(path string) (*CSPaillier, error) {
 		K:                    int(pKey.K),
 		K1:                   int(pKey.K1),
 	}
+}
 
-	var cspaillier CSPaillier
-
-	cspaillier = CSPaillier{
-		PubKey: &pubKey,
+// ToPbCSPaillierPubKey returns the protobuf encoding of pubKey.
+func ToPbCSPaillierPubKey(pubKey *CSPaillierPubKey) *pb.CSPaillierPubKey {
+	return &pb.CSPaillierPubKey{
+		N:                    pubKey.N.Bytes(),
+		G:                    pubKey.G.Bytes(),
+		Y1:                   pubKey.Y1.Bytes(),
+		Y2:                   pubKey.Y2.Bytes(),
+		Y3:                   pubKey.Y3.Bytes(),
+		DLogP:                pubKey.Gamma.P.Bytes(),
+		DLogG:                pubKey.Gamma.G.Bytes(),
+		DLogQ:                pubKey.Gamma.OrderOfSubgroup.Bytes(),
+		VerifiableEncGroupN:  pubKey.VerifiableEncGroupN.Bytes(),
+		VerifiableEncGroupG1: pubKey.VerifiableEncGroupG1.Bytes(),
+		VerifiableEncGroupH1: pubKey.VerifiableEncGroupH1.Bytes(),
+		K:                    int32(pubKey.K),
+		K1:                   int32(pubKey.K1),
 	}
+}
 
-	return &cspaillier, nil
+// GetPubKey returns the public key corresponding to secKey.
+func (secKey *CSPaillierSecretKey) GetPubKey() *CSPaillierPubKey {
+	n2 := new(big.Int).Mul(secKey.N, secKey.N)
+	return &CSPaillierPubKey{
+		N:                    secKey.N,
+		G:                    secKey.G,
+		Y1:                   new(big.Int).Exp(secKey.G, secKey.X1, n2),
+		Y2:                   new(big.Int).Exp(secKey.G, secKey.X2, n2),
+		Y3:                   new(big.Int).Exp(secKey.G, secKey.X3, n2),
+		Gamma:                secKey.Gamma,
+		VerifiableEncGroupN:  secKey.VerifiableEncGroupN,
+		VerifiableEncGroupG1: secKey.VerifiableEncGroupG1,
+		VerifiableEncGroupH1: secKey.VerifiableEncGroupH1,
+		K:                    secKey.K,
+		K1:                   secKey.K1,
+	}
 }
 
+// StoreSecKey writes the secret key (unencrypted) to the file at path, readable only by its owner.
 func (cspaillier *CSPaillier) StoreSecKey(path string) error {
+	return cspaillier.storeSecKey(path, nil)
+}
+
+// StoreEncryptedSecKey writes the secret key encrypted with passphrase to the file at path.
+func (cspaillier *CSPaillier) StoreEncryptedSecKey(path string, passphrase []byte) error {
+	return cspaillier.storeSecKey(path, passphrase)
+}
+
+func (cspaillier *CSPaillier) storeSecKey(path string, passphrase []byte) error {
 	secKey := &pb.CSPaillierSecretKey{
 		N:                    cspaillier.SecretKey.N.Bytes(),
 		G:                    cspaillier.SecretKey.G.Bytes(),
@@ -216,34 +297,19 @@ func (cspaillier *CSPaillier) StoreSecKey(path string) error {
 		K:                    int32(cspaillier.SecretKey.K),
 		K1:                   int32(cspaillier.SecretKey.K1),
 	}
-	data, err := proto.Marshal(secKey)
-	if err != nil {
-		return err
+	if cspaillier.SecretKey.P != nil && cspaillier.SecretKey.Q != nil {
+		secKey.P = cspaillier.SecretKey.P.Bytes()
+		secKey.Q = cspaillier.SecretKey.Q.Bytes()
 	}
-	err = common.Store(data, path)
+	data, err := proto.Marshal(secKey)
 	if err != nil {
 		return err
 	}
-	return nil
+	return keystore.Store(data, path, passphrase)
 }
 
 func (cspaillier *CSPaillier) StorePubKey(path string) error {
-	pubKey := &pb.CSPaillierPubKey{
-		N:                    cspaillier.PubKey.N.Bytes(),
-		G:                    cspaillier.PubKey.G.Bytes(),
-		Y1:                   cspaillier.PubKey.Y1.Bytes(),
-		Y2:                   cspaillier.PubKey.Y2.Bytes(),
-		Y3:                   cspaillier.PubKey.Y3.Bytes(),
-		DLogP:                cspaillier.PubKey.Gamma.P.Bytes(),
-		DLogG:                cspaillier.PubKey.Gamma.G.Bytes(),
-		DLogQ:                cspaillier.PubKey.Gamma.OrderOfSubgroup.Bytes(),
-		VerifiableEncGroupN:  cspaillier.PubKey.VerifiableEncGroupN.Bytes(),
-		VerifiableEncGroupG1: cspaillier.PubKey.VerifiableEncGroupG1.Bytes(),
-		VerifiableEncGroupH1: cspaillier.PubKey.VerifiableEncGroupH1.Bytes(),
-		K:                    int32(cspaillier.PubKey.K),
-		K1:                   int32(cspaillier.PubKey.K1),
-	}
-	data, err := proto.Marshal(pubKey)
+	data, err := proto.Marshal(ToPbCSPaillierPubKey(cspaillier.PubKey))
 	if err != nil {
 		return err
 	}
@@ -313,8 +379,7 @@ func (cspaillier *CSPaillier) Decrypt(u, e, v, label *big.Int) (*big.Int, error)
 	t.Mul(t, big.NewInt(2))
 
 	n2 := new(big.Int).Mul(cspaillier.PubKey.N, cspaillier.PubKey.N)
-	t.Exp(u, t, n2)
-	t.Mod(t, n2)
+	t = cspaillier.expModN2(u, t)
 
 	v2 := new(big.Int).Mul(v, v)
 	v2.Mod(v2, n2)
@@ -325,8 +390,8 @@ func (cspaillier *CSPaillier) Decrypt(u, e, v, label *big.Int) (*big.Int, error)
 	}
 
 	// check whether m1 is of the form h^m for some m from Z_n (meaning m1 = 1 + m * n)
-	ux1 := new(big.Int).Exp(u, cspaillier.SecretKey.X1, n2) // u^x1
-	ux1Inv := new(big.Int).ModInverse(ux1, n2)              // u^x1_inv
+	ux1 := cspaillier.expModN2(u, cspaillier.SecretKey.X1) // u^x1
+	ux1Inv := new(big.Int).ModInverse(ux1, n2)             // u^x1_inv
 
 	m1 := new(big.Int).Mul(e, ux1Inv)
 	m1.Mod(m1, n2)
@@ -344,6 +409,26 @@ func (cspaillier *CSPaillier) Decrypt(u, e, v, label *big.Int) (*big.Int, error)
 	return m, nil
 }
 
+// expModN2 returns x^y mod n^2 for y >= 0, using CRT if the prime factors of n are known.
+func (cspaillier *CSPaillier) expModN2(x, y *big.Int) *big.Int {
+	if cspaillier.crt != nil {
+		return cspaillier.crt.exp(x, y)
+	}
+	n2 := new(big.Int).Mul(cspaillier.PubKey.N, cspaillier.PubKey.N)
+	return new(big.Int).Exp(x, y, n2)
+}
+
+// precompute prepares CRT exponentiation modulo n^2 if the secret key contains the
+// prime factors of n.
+func (cspaillier *CSPaillier) precompute() {
+	p, q := cspaillier.SecretKey.P, cspaillier.SecretKey.Q
+	if p == nil || q == nil || new(big.Int).Mul(p, q).Cmp(cspaillier.SecretKey.N) != 0 {
+		cspaillier.crt = nil
+		return
+	}
+	cspaillier.crt = newCRTModulus(p, q)
+}
+
 func (cspaillier *CSPaillier) Abs(a *big.Int) (*big.Int, error) {
 	n2 := new(big.Int).Mul(cspaillier.PubKey.N, cspaillier.PubKey.N)
 	if a.Cmp(n2) >= 0 {
@@ -420,6 +505,8 @@ func (cspaillier *CSPaillier) generateKey() {
 
 	secretKey := CSPaillierSecretKey{
 		N:  n,
+		P:  p,
+		Q:  q,
 		K:  cspaillier.SecParams.K,
 		K1: cspaillier.SecParams.K1,
 	}
@@ -451,6 +538,7 @@ func (cspaillier *CSPaillier) generateKey() {
 	pubKey.Y3 = new(big.Int).Exp(pubKey.G, secretKey.X3, n2)
 	cspaillier.PubKey = &pubKey
 	cspaillier.SecretKey = &secretKey
+	cspaillier.precompute()
 }
 
 // Returns l = g1^m * h1^s where s is a random integer smaller than n/4.
@@ -576,7 +664,7 @@ func (cspaillier *CSPaillier) Verify(rTilde, sTilde, mTilde *big.Int) bool {
 	twoRTilde := new(big.Int).Mul(rTilde, big.NewInt(2))
 
 	t1 := common.Exponentiate(cspaillier.verifierEncData.U, twoC, n2)
-	t2 := common.Exponentiate(cspaillier.SecretKey.G, twoRTilde, n2)
+	t2 := common.Exponentiate(cspaillier.PubKey.G, twoRTilde, n2)
 	t := new(big.Int).Mul(t1, t2)
 	t.Mod(t, n2)
 	if cspaillier.verifierRandomData.U1.Cmp(t) != 0 {
@@ -586,8 +674,7 @@ func (cspaillier *CSPaillier) Verify(rTilde, sTilde, mTilde *big.Int) bool {
 
 	// check if e1 = e^(2*c) * y1^(2*rTilde) * h^(2*mTilde)
 	t1 = common.Exponentiate(cspaillier.verifierEncData.E, twoC, n2)
-	y1 := common.Exponentiate(cspaillier.SecretKey.G, cspaillier.SecretKey.X1, n2)
-	t2 = common.Exponentiate(y1, twoRTilde, n2)
+	t2 = common.Exponentiate(cspaillier.PubKey.Y1, twoRTilde, n2)
 	h := new(big.Int).Add(cspaillier.PubKey.N, big.NewInt(1)) // 1 + n
 	t3 := common.Exponentiate(h, new(big.Int).Mul(big.NewInt(2), mTilde), n2)
 	t.Mul(t1, t2)
@@ -602,10 +689,8 @@ func (cspaillier *CSPaillier) Verify(rTilde, sTilde, mTilde *big.Int) bool {
 	t1 = common.Exponentiate(cspaillier.verifierEncData.V, twoC, n2)
 	hashNum := common.Hash(cspaillier.verifierEncData.U, cspaillier.verifierEncData.E,
 		cspaillier.verifierEncData.Label)
-	y3 := common.Exponentiate(cspaillier.SecretKey.G, cspaillier.SecretKey.X3, n2)
-	t21 := new(big.Int).Exp(y3, hashNum, n2)
-	y2 := common.Exponentiate(cspaillier.SecretKey.G, cspaillier.SecretKey.X2, n2)
-	t21.Mul(y2, t21)
+	t21 := new(big.Int).Exp(cspaillier.PubKey.Y3, hashNum, n2)
+	t21.Mul(cspaillier.PubKey.Y2, t21)
 	t2 = common.Exponentiate(t21, twoRTilde, n2)
 	t.Mul(t1, t2)
 	t.Mod(t, n2)
@@ -616,10 +701,10 @@ func (cspaillier *CSPaillier) Verify(rTilde, sTilde, mTilde *big.Int) bool {
 
 	// check if delta1 = delta^c * Gamma.G^mTilde
 	t1.Exp(cspaillier.verifierEncData.Delta, cspaillier.verifierRandomData.C,
-		cspaillier.SecretKey.Gamma.P)
-	t2 = common.Exponentiate(cspaillier.SecretKey.Gamma.G, mTilde, cspaillier.SecretKey.Gamma.P)
+		cspaillier.PubKey.Gamma.P)
+	t2 = common.Exponentiate(cspaillier.PubKey.Gamma.G, mTilde, cspaillier.PubKey.Gamma.P)
 	t.Mul(t1, t2)
-	t.Mod(t, cspaillier.SecretKey.Gamma.P)
+	t.Mod(t, cspaillier.PubKey.Gamma.P)
 	if cspaillier.verifierRandomData.Delta1.Cmp(t) != 0 {
 		log.Println("NOT OK 4")
 		return false
@@ -627,13 +712,13 @@ func (cspaillier *CSPaillier) Verify(rTilde, sTilde, mTilde *big.Int) bool {
 
 	// check if l1 = l^c * g1^mTilde * h1^sTilde
 	t1.Exp(cspaillier.verifierRandomData.L, cspaillier.verifierRandomData.C, n2)
-	t2 = common.Exponentiate(cspaillier.SecretKey.VerifiableEncGroupG1,
-		mTilde, cspaillier.SecretKey.VerifiableEncGroupN)
-	t3 = common.Exponentiate(cspaillier.SecretKey.VerifiableEncGroupH1,
-		sTilde, cspaillier.SecretKey.VerifiableEncGroupN)
+	t2 = common.Exponentiate(cspaillier.PubKey.VerifiableEncGroupG1,
+		mTilde, cspaillier.PubKey.VerifiableEncGroupN)
+	t3 = common.Exponentiate(cspaillier.PubKey.VerifiableEncGroupH1,
+		sTilde, cspaillier.PubKey.VerifiableEncGroupN)
 	t.Mul(t1, t2)
 	t.Mul(t, t3)
-	t.Mod(t, cspaillier.SecretKey.VerifiableEncGroupN)
+	t.Mod(t, cspaillier.PubKey.VerifiableEncGroupN)
 	if cspaillier.verifierRandomData.L1.Cmp(t) != 0 {
 		log.Println("NOT OK 5")
 		return false
@@ -650,7 +735,7 @@ func (cspaillier *CSPaillier) Verify(rTilde, sTilde, mTilde *big.Int) bool {
 }
 
 func (cspaillier *CSPaillier) GetChallenge() *big.Int {
-	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(cspaillier.SecretKey.K)), nil)
+	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(cspaillier.PubKey.K)), nil)
 	c := common.GetRandomInt(b)
 	return c
 }
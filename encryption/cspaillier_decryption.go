@@ -0,0 +1,84 @@
+package encryption
+
+import (
+	"errors"
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+)
+
+// CSPaillierDecryptionProof is a non-interactive (Fiat-Shamir) proof that m is the
+// plaintext of a CSPaillier ciphertext (u, e, v). The decryptor proves that it knows x1
+// such that y1 = g^x1 and e / h^m = u^x1 (mod n^2), without revealing x1, so anybody
+// holding the public key can check that the ciphertext was opened correctly.
+//
+// The proof does not cover the check of v, which is done by Decrypt before m is
+// revealed.
+type CSPaillierDecryptionProof struct {
+	T1 *big.Int // g^r
+	T2 *big.Int // u^r
+	Z  *big.Int // r + c * x1
+}
+
+// DecryptWithProof decrypts the ciphertext (u, e, v) with the given label and returns
+// the plaintext together with a proof of correct decryption.
+func (cspaillier *CSPaillier) DecryptWithProof(u, e, v, label *big.Int) (*big.Int,
+	*CSPaillierDecryptionProof, error) {
+	if cspaillier.SecretKey == nil {
+		return nil, nil, errors.New("decryption requires the secret key")
+	}
+	m, err := cspaillier.Decrypt(u, e, v, label)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// r is chosen from [0, n^2 * 2^(K + K1)), so that it statistically hides c * x1
+	n2 := new(big.Int).Mul(cspaillier.PubKey.N, cspaillier.PubKey.N)
+	b := new(big.Int).Lsh(n2, uint(cspaillier.PubKey.K+cspaillier.PubKey.K1))
+	r := common.GetRandomInt(b)
+
+	t1 := cspaillier.expModN2(cspaillier.PubKey.G, r)
+	t2 := cspaillier.expModN2(u, r)
+	c := cspaillier.PubKey.getFiatShamirChallenge(u, e, v, label, m, t1, t2)
+
+	z := new(big.Int).Mul(c, cspaillier.SecretKey.X1)
+	z.Add(z, r)
+
+	return m, &CSPaillierDecryptionProof{
+		T1: t1,
+		T2: t2,
+		Z:  z,
+	}, nil
+}
+
+// VerifyDecryption verifies the proof that m is the plaintext of the ciphertext
+// (u, e, v) with the given label. Only the public key is used.
+func (cspaillier *CSPaillier) VerifyDecryption(u, e, v, label, m *big.Int,
+	proof *CSPaillierDecryptionProof) bool {
+	if proof == nil || proof.T1 == nil || proof.T2 == nil || proof.Z == nil ||
+		proof.Z.Sign() < 0 || m.Sign() < 0 || m.Cmp(cspaillier.PubKey.N) >= 0 {
+		return false
+	}
+
+	n2 := new(big.Int).Mul(cspaillier.PubKey.N, cspaillier.PubKey.N)
+	c := cspaillier.PubKey.getFiatShamirChallenge(u, e, v, label, m, proof.T1, proof.T2)
+
+	// check if g^z = t1 * y1^c
+	left := new(big.Int).Exp(cspaillier.PubKey.G, proof.Z, n2)
+	right := new(big.Int).Exp(cspaillier.PubKey.Y1, c, n2)
+	right.Mul(right, proof.T1)
+	right.Mod(right, n2)
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// check if u^z = t2 * (e / h^m)^c
+	h := new(big.Int).Add(cspaillier.PubKey.N, big.NewInt(1)) // 1 + n
+	w := common.Exponentiate(h, new(big.Int).Neg(m), n2)
+	w.Mul(w, e)
+	w.Mod(w, n2)
+	left.Exp(u, proof.Z, n2)
+	right.Exp(w, c, n2)
+	right.Mul(right, proof.T2)
+	right.Mod(right, n2)
+	return left.Cmp(right) == 0
+}
@@ -0,0 +1,95 @@
+package encryption
+
+import (
+	"errors"
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+)
+
+// CSPaillierProof is a non-interactive (Fiat-Shamir) proof that a CSPaillier ciphertext
+// (u, e, v) with some label encrypts the discrete logarithm of delta (with regard to
+// Gamma.G). It holds the same values as the interactive protocol: the opening l, the
+// first sigma protocol message (u1, e1, v1, delta1, l1) and the last one (rTilde, sTilde,
+// mTilde), while the challenge is computed as a hash of the statement and the first
+// message. The proof can be verified by anybody holding the public key.
+type CSPaillierProof struct {
+	L      *big.Int
+	U1     *big.Int
+	E1     *big.Int
+	V1     *big.Int
+	Delta1 *big.Int
+	L1     *big.Int
+	RTilde *big.Int
+	STilde *big.Int
+	MTilde *big.Int
+}
+
+// EncryptWithProof encrypts m with the given label and returns the ciphertext (u, e, v),
+// delta = Gamma.G^m and a non-interactive proof that the ciphertext encrypts the discrete
+// logarithm of delta.
+func (cspaillier *CSPaillier) EncryptWithProof(m, label *big.Int) (*big.Int, *big.Int, *big.Int,
+	*big.Int, *CSPaillierProof, error) {
+	u, e, v, err := cspaillier.Encrypt(m, label)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	l, delta := cspaillier.GetOpeningMsg(m)
+	u1, e1, v1, delta1, l1, err := cspaillier.GetProofRandomData(u, e, label)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	c := cspaillier.PubKey.getFiatShamirChallenge(u, e, v, label, delta, l, u1, e1, v1, delta1, l1)
+	rTilde, sTilde, mTilde := cspaillier.GetProofData(c)
+
+	proof := &CSPaillierProof{
+		L:      l,
+		U1:     u1,
+		E1:     e1,
+		V1:     v1,
+		Delta1: delta1,
+		L1:     l1,
+		RTilde: rTilde,
+		STilde: sTilde,
+		MTilde: mTilde,
+	}
+	return u, e, v, delta, proof, nil
+}
+
+// VerifyProof verifies the non-interactive proof that the ciphertext (u, e, v) with the
+// given label encrypts the discrete logarithm of delta. Only the public key is used.
+func (cspaillier *CSPaillier) VerifyProof(u, e, v, label, delta *big.Int,
+	proof *CSPaillierProof) (bool, error) {
+	if u == nil || e == nil || v == nil || label == nil || delta == nil {
+		return false, errors.New("incomplete CSPaillier ciphertext")
+	}
+	if proof == nil || proof.L == nil || proof.U1 == nil || proof.E1 == nil ||
+		proof.V1 == nil || proof.Delta1 == nil || proof.L1 == nil ||
+		proof.RTilde == nil || proof.STilde == nil || proof.MTilde == nil {
+		return false, errors.New("incomplete CSPaillier proof")
+	}
+
+	c := cspaillier.PubKey.getFiatShamirChallenge(u, e, v, label, delta, proof.L,
+		proof.U1, proof.E1, proof.V1, proof.Delta1, proof.L1)
+
+	// a separate verifier is used, so that verification doesn't interfere with
+	// the state of an interactive protocol run by cspaillier
+	verifier := NewCSPaillierFromPubKey(cspaillier.PubKey)
+	verifier.SetVerifierEncData(u, e, v, delta, label, proof.L)
+	verifier.SetProofRandomData(proof.U1, proof.E1, proof.V1, proof.Delta1, proof.L1, c)
+	return verifier.Verify(proof.RTilde, proof.STilde, proof.MTilde), nil
+}
+
+// getFiatShamirChallenge returns the challenge from [0, 2^K) for the given statement and
+// the first sigma protocol message. The public key is hashed as well, so that a proof
+// cannot be reused under a different key.
+func (pubKey *CSPaillierPubKey) getFiatShamirChallenge(values ...*big.Int) *big.Int {
+	hashed := []*big.Int{pubKey.N, pubKey.G, pubKey.Y1, pubKey.Y2, pubKey.Y3,
+		pubKey.Gamma.P, pubKey.Gamma.G, pubKey.Gamma.OrderOfSubgroup,
+		pubKey.VerifiableEncGroupN, pubKey.VerifiableEncGroupG1, pubKey.VerifiableEncGroupH1}
+	hashed = append(hashed, values...)
+
+	b := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(pubKey.K)), nil)
+	return new(big.Int).Mod(common.Hash(hashed...), b)
+}
@@ -0,0 +1,255 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+)
+
+// DamgardJurik is the generalization of Paillier by Damgard and Jurik
+// (https://www.brics.dk/RS/00/45/BRICS-RS-00-45.pdf), which computes modulo n^(s+1)
+// instead of n^2, so that plaintexts are from Z_{n^s}. For s = 1 it is Paillier with
+// g = n + 1. Larger s allows encrypting long values (for example 2048-bit secrets, or
+// several values encoded into one plaintext) without splitting them, while ciphertexts
+// are only (s + 1) / s times longer than plaintexts.
+type DamgardJurik struct {
+	primeLength int
+	lambda      *big.Int
+	lambdaInv   *big.Int // lambda^-1 mod n^s
+	p, q        *big.Int
+	pubKey      *DamgardJurikPubKey
+}
+
+// DamgardJurikPubKey is the public key n with exponent s. The generator is g = n + 1.
+type DamgardJurikPubKey struct {
+	n   *big.Int
+	s   int
+	ns  *big.Int // n^s
+	ns1 *big.Int // n^(s+1)
+	g   *big.Int
+}
+
+type DamgardJurikSecretKey struct {
+	N      *big.Int
+	S      int
+	Lambda *big.Int
+	P      *big.Int
+	Q      *big.Int
+}
+
+// NewDamgardJurik generates a key with primes of length primeLength and exponent s >= 1.
+func NewDamgardJurik(primeLength, s int) (*DamgardJurik, error) {
+	if s < 1 {
+		return nil, errors.New("s should be at least 1")
+	}
+	dj := DamgardJurik{
+		primeLength: primeLength,
+	}
+	dj.generateKey(s)
+
+	return &dj, nil
+}
+
+// NewPubDamgardJurik returns DamgardJurik with the given public key. It can encrypt,
+// compute with ciphertexts and produce and verify proofs about them, but it cannot decrypt.
+func NewPubDamgardJurik(pubKey *DamgardJurikPubKey) *DamgardJurik {
+	return &DamgardJurik{
+		pubKey: pubKey,
+	}
+}
+
+// NewDamgardJurikFromSecretKey returns DamgardJurik with the given secret key.
+func NewDamgardJurikFromSecretKey(secKey *DamgardJurikSecretKey) (*DamgardJurik, error) {
+	if secKey.S < 1 {
+		return nil, errors.New("s should be at least 1")
+	}
+	dj := DamgardJurik{
+		lambda: secKey.Lambda,
+		p:      secKey.P,
+		q:      secKey.Q,
+		pubKey: NewDamgardJurikPubKey(secKey.N, secKey.S),
+	}
+	dj.lambdaInv = new(big.Int).ModInverse(dj.lambda, dj.pubKey.ns)
+	if dj.lambdaInv == nil {
+		return nil, errors.New("lambda is not invertible modulo n^s")
+	}
+
+	return &dj, nil
+}
+
+// NewDamgardJurikPubKey returns the public key with modulus n and exponent s.
+func NewDamgardJurikPubKey(n *big.Int, s int) *DamgardJurikPubKey {
+	ns := new(big.Int).Exp(n, big.NewInt(int64(s)), nil)
+	return &DamgardJurikPubKey{
+		n:   n,
+		s:   s,
+		ns:  ns,
+		ns1: new(big.Int).Mul(ns, n),
+		g:   new(big.Int).Add(n, big.NewInt(1)),
+	}
+}
+
+// GetN returns the modulus n of the public key.
+func (pubKey *DamgardJurikPubKey) GetN() *big.Int {
+	return pubKey.n
+}
+
+// GetS returns the exponent s of the public key.
+func (pubKey *DamgardJurikPubKey) GetS() int {
+	return pubKey.s
+}
+
+// GetPlaintextSpace returns n^s; plaintexts are from Z_{n^s}.
+func (pubKey *DamgardJurikPubKey) GetPlaintextSpace() *big.Int {
+	return pubKey.ns
+}
+
+func (dj *DamgardJurik) GetPubKey() *DamgardJurikPubKey {
+	return dj.pubKey
+}
+
+func (dj *DamgardJurik) GetSecretKey() *DamgardJurikSecretKey {
+	return &DamgardJurikSecretKey{
+		N:      dj.pubKey.n,
+		S:      dj.pubKey.s,
+		Lambda: dj.lambda,
+		P:      dj.p,
+		Q:      dj.q,
+	}
+}
+
+func (dj *DamgardJurik) Encrypt(m *big.Int) (*big.Int, error) {
+	return dj.EncryptWithRandomness(m, dj.GetRandomness())
+}
+
+// EncryptWithRandomness encrypts m as c = g^m * r^(n^s) mod n^(s+1) with the given r
+// from Z_n*. Proofs about ciphertexts (see ProvePlaintextKnowledge) require the
+// randomness used for encryption, which can be obtained with GetRandomness.
+func (dj *DamgardJurik) EncryptWithRandomness(m, r *big.Int) (*big.Int, error) {
+	if !dj.isPlaintext(m) {
+		err := errors.New("msg is not from Z_{n^s}")
+		return nil, err
+	}
+
+	// c = g^m * r^(n^s) mod n^(s+1)
+	c := new(big.Int).Exp(dj.pubKey.g, m, dj.pubKey.ns1)
+	c.Mul(c, new(big.Int).Exp(r, dj.pubKey.ns, dj.pubKey.ns1))
+	return c.Mod(c, dj.pubKey.ns1), nil
+}
+
+// GetRandomness returns a random r from Z_n*, to be used for encryption or
+// re-randomization of ciphertexts.
+func (dj *DamgardJurik) GetRandomness() *big.Int {
+	// as in Paillier, it is very unlikely that r is not invertible, so we don't check
+	return common.GetRandomInt(dj.pubKey.n)
+}
+
+// Add returns the encryption of m1 + m2 mod n^s, given encryptions c1 of m1 and c2 of m2.
+func (dj *DamgardJurik) Add(c1, c2 *big.Int) *big.Int {
+	c := new(big.Int).Mul(c1, c2)
+	return c.Mod(c, dj.pubKey.ns1)
+}
+
+// MulScalar returns the encryption of k * m mod n^s, given encryption c of m. k can be
+// negative.
+func (dj *DamgardJurik) MulScalar(c, k *big.Int) *big.Int {
+	return common.Exponentiate(c, k, dj.pubKey.ns1)
+}
+
+// Rerandomize returns a fresh encryption c * r^(n^s) mod n^(s+1) of the plaintext of c,
+// together with the randomness r used for re-randomization.
+func (dj *DamgardJurik) Rerandomize(c *big.Int) (*big.Int, *big.Int) {
+	r := dj.GetRandomness()
+	return dj.RerandomizeWithRandomness(c, r), r
+}
+
+// RerandomizeWithRandomness returns c * r^(n^s) mod n^(s+1) for the given r from Z_n*.
+func (dj *DamgardJurik) RerandomizeWithRandomness(c, r *big.Int) *big.Int {
+	t := new(big.Int).Exp(r, dj.pubKey.ns, dj.pubKey.ns1)
+	t.Mul(c, t)
+	return t.Mod(t, dj.pubKey.ns1)
+}
+
+// Decrypt returns the plaintext of c. c is not modified.
+func (dj *DamgardJurik) Decrypt(c *big.Int) (*big.Int, error) {
+	if dj.lambda == nil {
+		return nil, errors.New("decryption requires the secret key")
+	}
+	if c.Sign() < 0 || c.Cmp(dj.pubKey.ns1) >= 0 {
+		err := errors.New("ciphertext is not from Z_{n^(s+1)}")
+		return nil, err
+	}
+
+	// c^lambda = (1 + n)^(lambda * m mod n^s) mod n^(s+1)
+	a := new(big.Int).Exp(c, dj.lambda, dj.pubKey.ns1)
+	m := dj.dlog(a)
+	m.Mul(m, dj.lambdaInv)
+	return m.Mod(m, dj.pubKey.ns), nil
+}
+
+// dlog returns i from Z_{n^s} such that a = (1 + n)^i mod n^(s+1), computed digit by
+// digit (in base n) as in Theorem 1 of the paper.
+func (dj *DamgardJurik) dlog(a *big.Int) *big.Int {
+	n := dj.pubKey.n
+	i := big.NewInt(0)
+	nj := new(big.Int).Set(n) // n^j
+	for j := 1; j <= dj.pubKey.s; j++ {
+		nj1 := new(big.Int).Mul(nj, n) // n^(j+1)
+		t1 := l(new(big.Int).Mod(a, nj1), n)
+		t1.Mod(t1, nj)
+		t2 := new(big.Int).Set(i)
+		nk := big.NewInt(1)         // n^(k-1)
+		kFactorial := big.NewInt(1) // k!
+		for k := 2; k <= j; k++ {
+			i.Sub(i, big.NewInt(1))
+			t2.Mul(t2, i)
+			t2.Mod(t2, nj)
+			nk.Mul(nk, n)
+			kFactorial.Mul(kFactorial, big.NewInt(int64(k)))
+
+			// t1 = t1 - t2 * n^(k-1) / k! mod n^j
+			t := new(big.Int).Mul(t2, nk)
+			t.Mul(t, new(big.Int).ModInverse(kFactorial, nj))
+			t1.Sub(t1, t)
+			t1.Mod(t1, nj)
+		}
+		i = t1
+		nj = nj1
+	}
+	return i
+}
+
+func (dj *DamgardJurik) generateKey(s int) {
+	p, _ := rand.Prime(rand.Reader, dj.primeLength)
+	q, _ := rand.Prime(rand.Reader, dj.primeLength)
+	for p.Cmp(q) == 0 {
+		q, _ = rand.Prime(rand.Reader, dj.primeLength)
+	}
+	pMin := new(big.Int).Sub(p, big.NewInt(1))
+	qMin := new(big.Int).Sub(q, big.NewInt(1))
+
+	dj.p = p
+	dj.q = q
+	dj.lambda = common.LCM(pMin, qMin)
+	dj.pubKey = NewDamgardJurikPubKey(new(big.Int).Mul(p, q), s)
+	// lambda is invertible modulo n^s as p and q have the same length
+	dj.lambdaInv = new(big.Int).ModInverse(dj.lambda, dj.pubKey.ns)
+}
+
+// isPlaintext returns true if m is from Z_{n^s}.
+func (dj *DamgardJurik) isPlaintext(m *big.Int) bool {
+	return m != nil && m.Sign() >= 0 && m.Cmp(dj.pubKey.ns) < 0
+}
+
+// isCiphertext returns true if c is from Z_{n^(s+1)}*.
+func (dj *DamgardJurik) isCiphertext(c *big.Int) bool {
+	return c != nil && c.Sign() > 0 && c.Cmp(dj.pubKey.ns1) < 0 &&
+		new(big.Int).GCD(nil, nil, c, dj.pubKey.n).Cmp(big.NewInt(1)) == 0
+}
+
+// isRandomness returns true if r is from Z_n*.
+func (dj *DamgardJurik) isRandomness(r *big.Int) bool {
+	return r != nil && r.Sign() > 0 && r.Cmp(dj.pubKey.n) < 0 &&
+		new(big.Int).GCD(nil, nil, r, dj.pubKey.n).Cmp(big.NewInt(1)) == 0
+}
@@ -0,0 +1,74 @@
+package encryption
+
+import (
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+)
+
+// Non-interactive (Fiat-Shamir) zero-knowledge proofs about Damgard-Jurik ciphertexts,
+// generalizing the proofs about Paillier ciphertexts (see paillier_proofs.go) to modulus
+// n^(s+1).
+
+var djPlaintextKnowledgeProofName = new(big.Int).SetBytes([]byte("damgard-jurik plaintext knowledge"))
+
+// DamgardJurikPlaintextKnowledgeProof proves knowledge of m and r such that
+// c = g^m * r^(n^s) mod n^(s+1).
+type DamgardJurikPlaintextKnowledgeProof struct {
+	A *big.Int // g^x * u^(n^s)
+	W *big.Int // x + e * m mod n^s
+	Z *big.Int // u * r^e * g^((x + e * m) div n^s) mod n
+}
+
+// ProvePlaintextKnowledge returns a proof that the prover knows the plaintext m of
+// c = EncryptWithRandomness(m, r).
+func (dj *DamgardJurik) ProvePlaintextKnowledge(c, m, r *big.Int) *DamgardJurikPlaintextKnowledgeProof {
+	n, ns, ns1, g := dj.pubKey.n, dj.pubKey.ns, dj.pubKey.ns1, dj.pubKey.g
+	x := common.GetRandomInt(ns)
+	u := dj.GetRandomness()
+	a := new(big.Int).Exp(g, x, ns1)
+	a.Mul(a, new(big.Int).Exp(u, ns, ns1))
+	a.Mod(a, ns1)
+
+	e := dj.getChallenge(djPlaintextKnowledgeProofName, c, a)
+	k, w := new(big.Int).DivMod(new(big.Int).Add(x, new(big.Int).Mul(e, m)), ns, new(big.Int))
+
+	// z = u * r^e * g^k mod n
+	z := new(big.Int).Exp(r, e, n)
+	z.Mul(z, u)
+	z.Mul(z, new(big.Int).Exp(g, k, n))
+	z.Mod(z, n)
+
+	return &DamgardJurikPlaintextKnowledgeProof{
+		A: a,
+		W: w,
+		Z: z,
+	}
+}
+
+// VerifyPlaintextKnowledge verifies the proof that the prover knows the plaintext of c.
+func (dj *DamgardJurik) VerifyPlaintextKnowledge(c *big.Int, proof *DamgardJurikPlaintextKnowledgeProof) bool {
+	if proof == nil || !dj.isCiphertext(c) || !dj.isCiphertext(proof.A) ||
+		!dj.isPlaintext(proof.W) || !dj.isRandomness(proof.Z) {
+		return false
+	}
+	ns, ns1, g := dj.pubKey.ns, dj.pubKey.ns1, dj.pubKey.g
+	e := dj.getChallenge(djPlaintextKnowledgeProofName, c, proof.A)
+
+	// check if g^w * z^(n^s) = a * c^e mod n^(s+1)
+	left := new(big.Int).Exp(g, proof.W, ns1)
+	left.Mul(left, new(big.Int).Exp(proof.Z, ns, ns1))
+	left.Mod(left, ns1)
+	right := new(big.Int).Exp(c, e, ns1)
+	right.Mul(right, proof.A)
+	right.Mod(right, ns1)
+	return left.Cmp(right) == 0
+}
+
+// getChallenge returns the challenge for a proof with the given name about the given
+// values. As for Paillier, the challenge is shorter than the prime factors of n.
+func (dj *DamgardJurik) getChallenge(name *big.Int, values ...*big.Int) *big.Int {
+	hashed := append([]*big.Int{name, dj.pubKey.n, big.NewInt(int64(dj.pubKey.s))}, values...)
+	bits := dj.pubKey.n.BitLen()/2 - 1
+	b := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	return new(big.Int).Mod(common.Hash(hashed...), b)
+}
@@ -0,0 +1,300 @@
+package encryption
+
+import (
+	"errors"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/dlogproofs"
+	"math/big"
+)
+
+// ElGamal encryption in a subgroup of Z_p* of prime order q (for example a Schnorr group,
+// see dlog.NewZpSchnorr). Messages are elements of the subgroup. Exponential ElGamal
+// (see EncryptExponential) encrypts integers m as g^m instead, which makes it additively
+// homomorphic, but only small plaintexts can be decrypted (see DecryptExponential).
+type ElGamal struct {
+	pubKey *ElGamalPubKey
+	x      *big.Int
+}
+
+// ElGamalPubKey is the public key y = g^x in the group DLog.
+type ElGamalPubKey struct {
+	DLog *dlog.ZpDLog
+	Y    *big.Int
+}
+
+type ElGamalSecretKey struct {
+	DLog *dlog.ZpDLog
+	X    *big.Int
+}
+
+// ElGamalCiphertext is the encryption (g^r, m * y^r) of m.
+type ElGamalCiphertext struct {
+	C1 *big.Int
+	C2 *big.Int
+}
+
+// ElGamalPlaintextKnowledgeProof is a non-interactive Schnorr proof of knowledge of the
+// randomness r of a ciphertext (c1 = g^r), and thus of its plaintext c2 / y^r.
+type ElGamalPlaintextKnowledgeProof struct {
+	A *big.Int // g^s
+	Z *big.Int // s + e * r mod q
+}
+
+// NewElGamal generates a key in the given group.
+func NewElGamal(dlog *dlog.ZpDLog) *ElGamal {
+	x := common.GetRandomInt(dlog.GetOrderOfSubgroup())
+	y, _ := dlog.ExponentiateBaseG(x)
+	return &ElGamal{
+		pubKey: &ElGamalPubKey{
+			DLog: dlog,
+			Y:    y,
+		},
+		x: x,
+	}
+}
+
+// NewPubElGamal returns ElGamal with the given public key. It can encrypt, compute with
+// ciphertexts and verify proofs, but it cannot decrypt.
+func NewPubElGamal(pubKey *ElGamalPubKey) *ElGamal {
+	return &ElGamal{
+		pubKey: pubKey,
+	}
+}
+
+// NewElGamalFromSecretKey returns ElGamal with the given secret key.
+func NewElGamalFromSecretKey(secKey *ElGamalSecretKey) *ElGamal {
+	y, _ := secKey.DLog.ExponentiateBaseG(secKey.X)
+	return &ElGamal{
+		pubKey: &ElGamalPubKey{
+			DLog: secKey.DLog,
+			Y:    y,
+		},
+		x: secKey.X,
+	}
+}
+
+func (elgamal *ElGamal) GetPubKey() *ElGamalPubKey {
+	return elgamal.pubKey
+}
+
+func (elgamal *ElGamal) GetSecretKey() *ElGamalSecretKey {
+	return &ElGamalSecretKey{
+		DLog: elgamal.pubKey.DLog,
+		X:    elgamal.x,
+	}
+}
+
+// Encrypt encrypts m, which must be an element of the group.
+func (elgamal *ElGamal) Encrypt(m *big.Int) (*ElGamalCiphertext, error) {
+	return elgamal.EncryptWithRandomness(m, elgamal.GetRandomness())
+}
+
+// EncryptWithRandomness encrypts m as (g^r, m * y^r) with the given r from Z_q.
+func (elgamal *ElGamal) EncryptWithRandomness(m, r *big.Int) (*ElGamalCiphertext, error) {
+	if !elgamal.isElement(m) {
+		err := errors.New("msg is not an element of the group")
+		return nil, err
+	}
+	group := elgamal.pubKey.DLog
+	c1, _ := group.ExponentiateBaseG(r)
+	yr, _ := group.Exponentiate(elgamal.pubKey.Y, r)
+	c2, _ := group.Multiply(m, yr)
+	return &ElGamalCiphertext{
+		C1: c1,
+		C2: c2,
+	}, nil
+}
+
+// EncryptExponential encrypts integer m from Z_q as the encryption of g^m.
+func (elgamal *ElGamal) EncryptExponential(m *big.Int) (*ElGamalCiphertext, error) {
+	return elgamal.EncryptExponentialWithRandomness(m, elgamal.GetRandomness())
+}
+
+// EncryptExponentialWithRandomness encrypts integer m from Z_q as (g^r, g^m * y^r) with
+// the given r from Z_q.
+func (elgamal *ElGamal) EncryptExponentialWithRandomness(m, r *big.Int) (*ElGamalCiphertext, error) {
+	if m.Sign() < 0 || m.Cmp(elgamal.pubKey.DLog.GetOrderOfSubgroup()) >= 0 {
+		err := errors.New("msg is not from Z_q")
+		return nil, err
+	}
+	gm, _ := elgamal.pubKey.DLog.ExponentiateBaseG(m)
+	return elgamal.EncryptWithRandomness(gm, r)
+}
+
+// GetRandomness returns a random r from Z_q, to be used for encryption or
+// re-randomization of ciphertexts.
+func (elgamal *ElGamal) GetRandomness() *big.Int {
+	return common.GetRandomInt(elgamal.pubKey.DLog.GetOrderOfSubgroup())
+}
+
+// Multiply returns the encryption of m1 * m2, given encryptions c1 of m1 and c2 of m2.
+// For exponential ElGamal, it returns the encryption of m1 + m2.
+func (elgamal *ElGamal) Multiply(c1, c2 *ElGamalCiphertext) *ElGamalCiphertext {
+	group := elgamal.pubKey.DLog
+	a, _ := group.Multiply(c1.C1, c2.C1)
+	b, _ := group.Multiply(c1.C2, c2.C2)
+	return &ElGamalCiphertext{
+		C1: a,
+		C2: b,
+	}
+}
+
+// Exponentiate returns the encryption of m^k, given encryption c of m. For exponential
+// ElGamal, it returns the encryption of k * m.
+func (elgamal *ElGamal) Exponentiate(c *ElGamalCiphertext, k *big.Int) *ElGamalCiphertext {
+	k = new(big.Int).Mod(k, elgamal.pubKey.DLog.GetOrderOfSubgroup())
+	group := elgamal.pubKey.DLog
+	a, _ := group.Exponentiate(c.C1, k)
+	b, _ := group.Exponentiate(c.C2, k)
+	return &ElGamalCiphertext{
+		C1: a,
+		C2: b,
+	}
+}
+
+// Rerandomize returns a fresh encryption (c1 * g^r, c2 * y^r) of the plaintext of c,
+// together with the randomness r used for re-randomization.
+func (elgamal *ElGamal) Rerandomize(c *ElGamalCiphertext) (*ElGamalCiphertext, *big.Int) {
+	r := elgamal.GetRandomness()
+	return elgamal.RerandomizeWithRandomness(c, r), r
+}
+
+// RerandomizeWithRandomness returns (c1 * g^r, c2 * y^r) for the given r from Z_q.
+func (elgamal *ElGamal) RerandomizeWithRandomness(c *ElGamalCiphertext, r *big.Int) *ElGamalCiphertext {
+	one, _ := elgamal.EncryptWithRandomness(big.NewInt(1), r)
+	return elgamal.Multiply(c, one)
+}
+
+// Decrypt returns the plaintext c2 / c1^x of c.
+func (elgamal *ElGamal) Decrypt(c *ElGamalCiphertext) (*big.Int, error) {
+	if elgamal.x == nil {
+		return nil, errors.New("decryption requires the secret key")
+	}
+	if !elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return nil, errors.New("ciphertext is not from the group")
+	}
+	group := elgamal.pubKey.DLog
+	s, _ := group.Exponentiate(c.C1, elgamal.x)
+	s.ModInverse(s, group.P)
+	m, _ := group.Multiply(c.C2, s)
+	return m, nil
+}
+
+// DecryptExponential returns the plaintext m of exponential ElGamal ciphertext c, if
+// 0 <= m < bound. As it computes the discrete logarithm of g^m, bound should be small
+// (about 2^40 at most).
+func (elgamal *ElGamal) DecryptExponential(c *ElGamalCiphertext, bound *big.Int) (*big.Int, error) {
+	gm, err := elgamal.Decrypt(c)
+	if err != nil {
+		return nil, err
+	}
+	return discreteLog(elgamal.pubKey.DLog, gm, bound)
+}
+
+// DecryptWithProof returns the plaintext of c, together with a Chaum-Pedersen proof
+// that it was decrypted correctly, that is that log_g(y) = log_c1(c2 / m).
+func (elgamal *ElGamal) DecryptWithProof(c *ElGamalCiphertext) (*big.Int,
+	*dlogproofs.DLogEqualityProof, error) {
+	m, err := elgamal.Decrypt(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	group := elgamal.pubKey.DLog
+	c1x := new(big.Int).ModInverse(m, group.P)
+	c1x, _ = group.Multiply(c.C2, c1x)
+	proof := dlogproofs.ProveDLogEquality(elgamal.x, group.G, c.C1, elgamal.pubKey.Y, c1x, group)
+	return m, proof, nil
+}
+
+// VerifyDecryption verifies the proof that m is the plaintext of c.
+func (elgamal *ElGamal) VerifyDecryption(c *ElGamalCiphertext, m *big.Int,
+	proof *dlogproofs.DLogEqualityProof) bool {
+	if !elgamal.isElement(m) || !elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return false
+	}
+	group := elgamal.pubKey.DLog
+	c1x := new(big.Int).ModInverse(m, group.P)
+	c1x, _ = group.Multiply(c.C2, c1x)
+	return dlogproofs.VerifyDLogEquality(proof, group.G, c.C1, elgamal.pubKey.Y, c1x, group)
+}
+
+// ProvePlaintextKnowledge returns a proof that the prover knows the plaintext of
+// c = EncryptWithRandomness(m, r) (or c = EncryptExponentialWithRandomness(m, r)),
+// which is a proof of knowledge of r.
+func (elgamal *ElGamal) ProvePlaintextKnowledge(c *ElGamalCiphertext, r *big.Int) *ElGamalPlaintextKnowledgeProof {
+	group := elgamal.pubKey.DLog
+	s := elgamal.GetRandomness()
+	a, _ := group.ExponentiateBaseG(s)
+	e := elgamal.getChallenge(c, a)
+	z := new(big.Int).Mul(e, r)
+	z.Add(z, s)
+	z.Mod(z, group.GetOrderOfSubgroup())
+	return &ElGamalPlaintextKnowledgeProof{
+		A: a,
+		Z: z,
+	}
+}
+
+// VerifyPlaintextKnowledge verifies the proof that the prover knows the plaintext of c.
+func (elgamal *ElGamal) VerifyPlaintextKnowledge(c *ElGamalCiphertext, proof *ElGamalPlaintextKnowledgeProof) bool {
+	if proof == nil || proof.Z == nil || !elgamal.isElement(proof.A) ||
+		!elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return false
+	}
+	group := elgamal.pubKey.DLog
+	e := elgamal.getChallenge(c, proof.A)
+
+	// check if g^z = a * c1^e
+	left, _ := group.ExponentiateBaseG(proof.Z)
+	right, _ := group.Exponentiate(c.C1, e)
+	right, _ = group.Multiply(right, proof.A)
+	return left.Cmp(right) == 0
+}
+
+// getChallenge returns the challenge for the proof of plaintext knowledge of c.
+func (elgamal *ElGamal) getChallenge(c *ElGamalCiphertext, a *big.Int) *big.Int {
+	group := elgamal.pubKey.DLog
+	h := common.Hash(group.P, group.G, elgamal.pubKey.Y, c.C1, c.C2, a)
+	return h.Mod(h, group.GetOrderOfSubgroup())
+}
+
+// isElement returns true if x is an element of the subgroup of order q.
+func (elgamal *ElGamal) isElement(x *big.Int) bool {
+	group := elgamal.pubKey.DLog
+	if x == nil || x.Sign() <= 0 || x.Cmp(group.P) >= 0 {
+		return false
+	}
+	t, _ := group.Exponentiate(x, group.GetOrderOfSubgroup())
+	return t.Cmp(big.NewInt(1)) == 0
+}
+
+// discreteLog returns m such that gm = g^m, if 0 <= m < bound, computed with the
+// baby-step giant-step algorithm in O(sqrt(bound)) time and memory.
+func discreteLog(group *dlog.ZpDLog, gm, bound *big.Int) (*big.Int, error) {
+	// baby-step giant-step: gm = g^(i*k + j) for 0 <= i, j < k
+	k := new(big.Int).Sqrt(bound)
+	k.Add(k, big.NewInt(1))
+	babySteps := make(map[string]int64)
+	t := big.NewInt(1)
+	for j := int64(0); j < k.Int64(); j++ {
+		if _, ok := babySteps[t.String()]; !ok {
+			babySteps[t.String()] = j
+		}
+		t, _ = group.Multiply(t, group.G)
+	}
+	// t = g^k, giant step is multiplication with g^-k
+	giantStep := new(big.Int).ModInverse(t, group.P)
+	for i := int64(0); i < k.Int64(); i++ {
+		if j, ok := babySteps[gm.String()]; ok {
+			m := new(big.Int).Mul(big.NewInt(i), k)
+			m.Add(m, big.NewInt(j))
+			if m.Cmp(bound) < 0 {
+				return m, nil
+			}
+			break
+		}
+		gm, _ = group.Multiply(gm, giantStep)
+	}
+	return nil, errors.New("plaintext is not smaller than bound")
+}
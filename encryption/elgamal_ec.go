@@ -0,0 +1,313 @@
+package encryption
+
+import (
+	"errors"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/dlogproofs"
+	"math/big"
+)
+
+// ElGamalEC is ElGamal (see ElGamal) in an elliptic curve group. Messages are points on
+// the curve; exponential ElGamal encrypts integers m as g^m.
+type ElGamalEC struct {
+	pubKey *ElGamalECPubKey
+	x      *big.Int
+}
+
+// ElGamalECPubKey is the public key y = g^x in the group DLog.
+type ElGamalECPubKey struct {
+	DLog *dlog.ECDLog
+	Y    *common.ECGroupElement
+}
+
+type ElGamalECSecretKey struct {
+	DLog *dlog.ECDLog
+	X    *big.Int
+}
+
+// ElGamalECCiphertext is the encryption (g^r, m * y^r) of m.
+type ElGamalECCiphertext struct {
+	C1 *common.ECGroupElement
+	C2 *common.ECGroupElement
+}
+
+// ElGamalECPlaintextKnowledgeProof is ElGamalPlaintextKnowledgeProof for elliptic curve
+// groups.
+type ElGamalECPlaintextKnowledgeProof struct {
+	A *common.ECGroupElement // g^s
+	Z *big.Int               // s + e * r mod q
+}
+
+// NewElGamalEC generates a key in the given group.
+func NewElGamalEC(dlog *dlog.ECDLog) *ElGamalEC {
+	x := common.GetRandomInt(dlog.GetOrderOfSubgroup())
+	yX, yY := dlog.ExponentiateBaseG(x)
+	return &ElGamalEC{
+		pubKey: &ElGamalECPubKey{
+			DLog: dlog,
+			Y:    &common.ECGroupElement{X: yX, Y: yY},
+		},
+		x: x,
+	}
+}
+
+// NewPubElGamalEC returns ElGamalEC with the given public key. It can encrypt, compute
+// with ciphertexts and verify proofs, but it cannot decrypt.
+func NewPubElGamalEC(pubKey *ElGamalECPubKey) *ElGamalEC {
+	return &ElGamalEC{
+		pubKey: pubKey,
+	}
+}
+
+// NewElGamalECFromSecretKey returns ElGamalEC with the given secret key.
+func NewElGamalECFromSecretKey(secKey *ElGamalECSecretKey) *ElGamalEC {
+	yX, yY := secKey.DLog.ExponentiateBaseG(secKey.X)
+	return &ElGamalEC{
+		pubKey: &ElGamalECPubKey{
+			DLog: secKey.DLog,
+			Y:    &common.ECGroupElement{X: yX, Y: yY},
+		},
+		x: secKey.X,
+	}
+}
+
+func (elgamal *ElGamalEC) GetPubKey() *ElGamalECPubKey {
+	return elgamal.pubKey
+}
+
+func (elgamal *ElGamalEC) GetSecretKey() *ElGamalECSecretKey {
+	return &ElGamalECSecretKey{
+		DLog: elgamal.pubKey.DLog,
+		X:    elgamal.x,
+	}
+}
+
+// Encrypt encrypts m, which must be a point on the curve.
+func (elgamal *ElGamalEC) Encrypt(m *common.ECGroupElement) (*ElGamalECCiphertext, error) {
+	return elgamal.EncryptWithRandomness(m, elgamal.GetRandomness())
+}
+
+// EncryptWithRandomness encrypts m as (g^r, m * y^r) with the given r from Z_q.
+func (elgamal *ElGamalEC) EncryptWithRandomness(m *common.ECGroupElement,
+	r *big.Int) (*ElGamalECCiphertext, error) {
+	if !elgamal.isElement(m) {
+		err := errors.New("msg is not a point on the curve")
+		return nil, err
+	}
+	return elgamal.encrypt(m, r), nil
+}
+
+// EncryptExponential encrypts integer m from Z_q as the encryption of g^m.
+func (elgamal *ElGamalEC) EncryptExponential(m *big.Int) (*ElGamalECCiphertext, error) {
+	return elgamal.EncryptExponentialWithRandomness(m, elgamal.GetRandomness())
+}
+
+// EncryptExponentialWithRandomness encrypts integer m from Z_q as (g^r, g^m * y^r) with
+// the given r from Z_q.
+func (elgamal *ElGamalEC) EncryptExponentialWithRandomness(m, r *big.Int) (*ElGamalECCiphertext, error) {
+	if m.Sign() < 0 || m.Cmp(elgamal.pubKey.DLog.GetOrderOfSubgroup()) >= 0 {
+		err := errors.New("msg is not from Z_q")
+		return nil, err
+	}
+	// g^0 is the point at infinity, which is not on the curve, thus no check here
+	gmX, gmY := elgamal.pubKey.DLog.ExponentiateBaseG(m)
+	return elgamal.encrypt(&common.ECGroupElement{X: gmX, Y: gmY}, r), nil
+}
+
+// GetRandomness returns a random r from Z_q, to be used for encryption or
+// re-randomization of ciphertexts.
+func (elgamal *ElGamalEC) GetRandomness() *big.Int {
+	return common.GetRandomInt(elgamal.pubKey.DLog.GetOrderOfSubgroup())
+}
+
+// Multiply returns the encryption of m1 * m2, given encryptions c1 of m1 and c2 of m2.
+// For exponential ElGamal, it returns the encryption of m1 + m2.
+func (elgamal *ElGamalEC) Multiply(c1, c2 *ElGamalECCiphertext) *ElGamalECCiphertext {
+	return &ElGamalECCiphertext{
+		C1: elgamal.multiply(c1.C1, c2.C1),
+		C2: elgamal.multiply(c1.C2, c2.C2),
+	}
+}
+
+// Exponentiate returns the encryption of m^k, given encryption c of m. For exponential
+// ElGamal, it returns the encryption of k * m.
+func (elgamal *ElGamalEC) Exponentiate(c *ElGamalECCiphertext, k *big.Int) *ElGamalECCiphertext {
+	k = new(big.Int).Mod(k, elgamal.pubKey.DLog.GetOrderOfSubgroup())
+	return &ElGamalECCiphertext{
+		C1: elgamal.exponentiate(c.C1, k),
+		C2: elgamal.exponentiate(c.C2, k),
+	}
+}
+
+// Rerandomize returns a fresh encryption (c1 * g^r, c2 * y^r) of the plaintext of c,
+// together with the randomness r used for re-randomization.
+func (elgamal *ElGamalEC) Rerandomize(c *ElGamalECCiphertext) (*ElGamalECCiphertext, *big.Int) {
+	r := elgamal.GetRandomness()
+	return elgamal.RerandomizeWithRandomness(c, r), r
+}
+
+// RerandomizeWithRandomness returns (c1 * g^r, c2 * y^r) for the given r from Z_q.
+func (elgamal *ElGamalEC) RerandomizeWithRandomness(c *ElGamalECCiphertext, r *big.Int) *ElGamalECCiphertext {
+	gX, gY := elgamal.pubKey.DLog.ExponentiateBaseG(r)
+	return &ElGamalECCiphertext{
+		C1: elgamal.multiply(c.C1, &common.ECGroupElement{X: gX, Y: gY}),
+		C2: elgamal.multiply(c.C2, elgamal.exponentiate(elgamal.pubKey.Y, r)),
+	}
+}
+
+// Decrypt returns the plaintext c2 / c1^x of c.
+func (elgamal *ElGamalEC) Decrypt(c *ElGamalECCiphertext) (*common.ECGroupElement, error) {
+	if elgamal.x == nil {
+		return nil, errors.New("decryption requires the secret key")
+	}
+	if !elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return nil, errors.New("ciphertext is not from the group")
+	}
+	s := elgamal.exponentiate(c.C1, elgamal.x)
+	return elgamal.multiply(c.C2, elgamal.inverse(s)), nil
+}
+
+// DecryptExponential returns the plaintext m of exponential ElGamal ciphertext c, if
+// 0 <= m < bound. As it computes the discrete logarithm of g^m, bound should be small
+// (about 2^40 at most).
+func (elgamal *ElGamalEC) DecryptExponential(c *ElGamalECCiphertext, bound *big.Int) (*big.Int, error) {
+	gm, err := elgamal.Decrypt(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// baby-step giant-step: gm = g^(i*k + j) for 0 <= i, j < k
+	k := new(big.Int).Sqrt(bound)
+	k.Add(k, big.NewInt(1))
+	babySteps := make(map[string]int64)
+	g := elgamal.getGenerator()
+	t := elgamal.exponentiate(g, big.NewInt(0))
+	for j := int64(0); j < k.Int64(); j++ {
+		if _, ok := babySteps[ecKey(t)]; !ok {
+			babySteps[ecKey(t)] = j
+		}
+		t = elgamal.multiply(t, g)
+	}
+	// t = g^k, giant step is multiplication with g^-k
+	giantStep := elgamal.inverse(t)
+	for i := int64(0); i < k.Int64(); i++ {
+		if j, ok := babySteps[ecKey(gm)]; ok {
+			m := new(big.Int).Mul(big.NewInt(i), k)
+			m.Add(m, big.NewInt(j))
+			if m.Cmp(bound) < 0 {
+				return m, nil
+			}
+			break
+		}
+		gm = elgamal.multiply(gm, giantStep)
+	}
+	return nil, errors.New("plaintext is not smaller than bound")
+}
+
+// DecryptWithProof returns the plaintext of c, together with a Chaum-Pedersen proof
+// that it was decrypted correctly, that is that log_g(y) = log_c1(c2 / m).
+func (elgamal *ElGamalEC) DecryptWithProof(c *ElGamalECCiphertext) (*common.ECGroupElement,
+	*dlogproofs.DLogEqualityECProof, error) {
+	m, err := elgamal.Decrypt(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	c1x := elgamal.multiply(c.C2, elgamal.inverse(m))
+	proof := dlogproofs.ProveDLogEqualityEC(elgamal.x, elgamal.getGenerator(), c.C1, elgamal.pubKey.Y,
+		c1x, elgamal.pubKey.DLog)
+	return m, proof, nil
+}
+
+// VerifyDecryption verifies the proof that m is the plaintext of c.
+func (elgamal *ElGamalEC) VerifyDecryption(c *ElGamalECCiphertext, m *common.ECGroupElement,
+	proof *dlogproofs.DLogEqualityECProof) bool {
+	if !elgamal.isElement(m) || !elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return false
+	}
+	c1x := elgamal.multiply(c.C2, elgamal.inverse(m))
+	return dlogproofs.VerifyDLogEqualityEC(proof, elgamal.getGenerator(), c.C1, elgamal.pubKey.Y,
+		c1x, elgamal.pubKey.DLog)
+}
+
+// ProvePlaintextKnowledge returns a proof that the prover knows the plaintext of
+// c = EncryptWithRandomness(m, r) (or c = EncryptExponentialWithRandomness(m, r)),
+// which is a proof of knowledge of r.
+func (elgamal *ElGamalEC) ProvePlaintextKnowledge(c *ElGamalECCiphertext,
+	r *big.Int) *ElGamalECPlaintextKnowledgeProof {
+	s := elgamal.GetRandomness()
+	aX, aY := elgamal.pubKey.DLog.ExponentiateBaseG(s)
+	a := &common.ECGroupElement{X: aX, Y: aY}
+	e := elgamal.getChallenge(c, a)
+	z := new(big.Int).Mul(e, r)
+	z.Add(z, s)
+	z.Mod(z, elgamal.pubKey.DLog.GetOrderOfSubgroup())
+	return &ElGamalECPlaintextKnowledgeProof{
+		A: a,
+		Z: z,
+	}
+}
+
+// VerifyPlaintextKnowledge verifies the proof that the prover knows the plaintext of c.
+func (elgamal *ElGamalEC) VerifyPlaintextKnowledge(c *ElGamalECCiphertext,
+	proof *ElGamalECPlaintextKnowledgeProof) bool {
+	if proof == nil || proof.Z == nil || !elgamal.isElement(proof.A) ||
+		!elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return false
+	}
+	e := elgamal.getChallenge(c, proof.A)
+
+	// check if g^z = a * c1^e
+	leftX, leftY := elgamal.pubKey.DLog.ExponentiateBaseG(proof.Z)
+	right := elgamal.multiply(elgamal.exponentiate(c.C1, e), proof.A)
+	return leftX.Cmp(right.X) == 0 && leftY.Cmp(right.Y) == 0
+}
+
+func (elgamal *ElGamalEC) encrypt(m *common.ECGroupElement, r *big.Int) *ElGamalECCiphertext {
+	c1X, c1Y := elgamal.pubKey.DLog.ExponentiateBaseG(r)
+	return &ElGamalECCiphertext{
+		C1: &common.ECGroupElement{X: c1X, Y: c1Y},
+		C2: elgamal.multiply(m, elgamal.exponentiate(elgamal.pubKey.Y, r)),
+	}
+}
+
+// getChallenge returns the challenge for the proof of plaintext knowledge of c.
+func (elgamal *ElGamalEC) getChallenge(c *ElGamalECCiphertext, a *common.ECGroupElement) *big.Int {
+	params := elgamal.pubKey.DLog.Curve.Params()
+	y := elgamal.pubKey.Y
+	h := common.Hash(params.P, params.Gx, params.Gy, y.X, y.Y, c.C1.X, c.C1.Y, c.C2.X, c.C2.Y,
+		a.X, a.Y)
+	return h.Mod(h, elgamal.pubKey.DLog.GetOrderOfSubgroup())
+}
+
+func (elgamal *ElGamalEC) getGenerator() *common.ECGroupElement {
+	params := elgamal.pubKey.DLog.Curve.Params()
+	return &common.ECGroupElement{X: params.Gx, Y: params.Gy}
+}
+
+func (elgamal *ElGamalEC) multiply(a, b *common.ECGroupElement) *common.ECGroupElement {
+	x, y := elgamal.pubKey.DLog.Multiply(a.X, a.Y, b.X, b.Y)
+	return &common.ECGroupElement{X: x, Y: y}
+}
+
+func (elgamal *ElGamalEC) exponentiate(a *common.ECGroupElement, k *big.Int) *common.ECGroupElement {
+	x, y := elgamal.pubKey.DLog.Exponentiate(a.X, a.Y, k)
+	return &common.ECGroupElement{X: x, Y: y}
+}
+
+// inverse returns -a, which is (x, -y) on the curves used here.
+func (elgamal *ElGamalEC) inverse(a *common.ECGroupElement) *common.ECGroupElement {
+	y := new(big.Int).Neg(a.Y)
+	y.Mod(y, elgamal.pubKey.DLog.Curve.Params().P)
+	return &common.ECGroupElement{X: new(big.Int).Set(a.X), Y: y}
+}
+
+// isElement returns true if a is a point on the curve (the point at infinity is not).
+func (elgamal *ElGamalEC) isElement(a *common.ECGroupElement) bool {
+	return a != nil && a.X != nil && a.Y != nil && elgamal.pubKey.DLog.Curve.IsOnCurve(a.X, a.Y)
+}
+
+// ecKey returns a map key for point a.
+func ecKey(a *common.ECGroupElement) string {
+	return a.X.String() + "," + a.Y.String()
+}
@@ -0,0 +1,443 @@
+package encryption
+
+import (
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/dlogproofs"
+)
+
+// Types of encoded keys (see common.MarshalVersioned).
+const (
+	PaillierPubKeyType      = "paillier-public-key"
+	PaillierSecretKeyType   = "paillier-secret-key"
+	CSPaillierPubKeyType    = "cspaillier-public-key"
+	CSPaillierSecretKeyType = "cspaillier-secret-key"
+
+	DamgardJurikPubKeyType    = "damgard-jurik-public-key"
+	DamgardJurikSecretKeyType = "damgard-jurik-secret-key"
+
+	ThresholdPaillierPubKeyType   = "threshold-paillier-public-key"
+	ThresholdPaillierKeyShareType = "threshold-paillier-key-share"
+	PaillierDecryptionShareType   = "paillier-decryption-share"
+
+	ThresholdElGamalPubKeyType   = "threshold-elgamal-public-key"
+	ThresholdElGamalKeyShareType = "threshold-elgamal-key-share"
+	ElGamalDecryptionShareType   = "elgamal-decryption-share"
+)
+
+type paillierPubKeyJSON struct {
+	N *common.Int `json:"n"`
+	G *common.Int `json:"g"`
+}
+
+func (pubKey *PaillierPubKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(PaillierPubKeyType, &paillierPubKeyJSON{
+		N: common.NewInt(pubKey.n),
+		G: common.NewInt(pubKey.g),
+	})
+}
+
+func (pubKey *PaillierPubKey) UnmarshalJSON(data []byte) error {
+	var v paillierPubKeyJSON
+	if err := common.UnmarshalVersioned(data, PaillierPubKeyType, &v); err != nil {
+		return err
+	}
+	*pubKey = *NewPaillierPubKey(v.N.BigInt(), v.G.BigInt())
+	return nil
+}
+
+type paillierSecretKeyJSON struct {
+	N      *common.Int `json:"n"`
+	G      *common.Int `json:"g"`
+	Lambda *common.Int `json:"lambda"`
+	P      *common.Int `json:"p,omitempty"`
+	Q      *common.Int `json:"q,omitempty"`
+}
+
+func (secKey *PaillierSecretKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(PaillierSecretKeyType, &paillierSecretKeyJSON{
+		N:      common.NewInt(secKey.N),
+		G:      common.NewInt(secKey.G),
+		Lambda: common.NewInt(secKey.Lambda),
+		P:      common.NewInt(secKey.P),
+		Q:      common.NewInt(secKey.Q),
+	})
+}
+
+func (secKey *PaillierSecretKey) UnmarshalJSON(data []byte) error {
+	var v paillierSecretKeyJSON
+	if err := common.UnmarshalVersioned(data, PaillierSecretKeyType, &v); err != nil {
+		return err
+	}
+	secKey.N = v.N.BigInt()
+	secKey.G = v.G.BigInt()
+	secKey.Lambda = v.Lambda.BigInt()
+	secKey.P = v.P.BigInt()
+	secKey.Q = v.Q.BigInt()
+	return nil
+}
+
+type damgardJurikPubKeyJSON struct {
+	N *common.Int `json:"n"`
+	S int         `json:"s"`
+}
+
+func (pubKey *DamgardJurikPubKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(DamgardJurikPubKeyType, &damgardJurikPubKeyJSON{
+		N: common.NewInt(pubKey.n),
+		S: pubKey.s,
+	})
+}
+
+func (pubKey *DamgardJurikPubKey) UnmarshalJSON(data []byte) error {
+	var v damgardJurikPubKeyJSON
+	if err := common.UnmarshalVersioned(data, DamgardJurikPubKeyType, &v); err != nil {
+		return err
+	}
+	*pubKey = *NewDamgardJurikPubKey(v.N.BigInt(), v.S)
+	return nil
+}
+
+type damgardJurikSecretKeyJSON struct {
+	N      *common.Int `json:"n"`
+	S      int         `json:"s"`
+	Lambda *common.Int `json:"lambda"`
+	P      *common.Int `json:"p,omitempty"`
+	Q      *common.Int `json:"q,omitempty"`
+}
+
+func (secKey *DamgardJurikSecretKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(DamgardJurikSecretKeyType, &damgardJurikSecretKeyJSON{
+		N:      common.NewInt(secKey.N),
+		S:      secKey.S,
+		Lambda: common.NewInt(secKey.Lambda),
+		P:      common.NewInt(secKey.P),
+		Q:      common.NewInt(secKey.Q),
+	})
+}
+
+func (secKey *DamgardJurikSecretKey) UnmarshalJSON(data []byte) error {
+	var v damgardJurikSecretKeyJSON
+	if err := common.UnmarshalVersioned(data, DamgardJurikSecretKeyType, &v); err != nil {
+		return err
+	}
+	*secKey = DamgardJurikSecretKey{
+		N:      v.N.BigInt(),
+		S:      v.S,
+		Lambda: v.Lambda.BigInt(),
+		P:      v.P.BigInt(),
+		Q:      v.Q.BigInt(),
+	}
+	return nil
+}
+
+// cspaillierParamsJSON holds parameters shared by CSPaillier public and secret keys.
+type cspaillierParamsJSON struct {
+	DLogP                *common.Int `json:"dlog_p"`
+	DLogG                *common.Int `json:"dlog_g"`
+	DLogQ                *common.Int `json:"dlog_q"`
+	VerifiableEncGroupN  *common.Int `json:"verifiable_enc_group_n"`
+	VerifiableEncGroupG1 *common.Int `json:"verifiable_enc_group_g1"`
+	VerifiableEncGroupH1 *common.Int `json:"verifiable_enc_group_h1"`
+	K                    int         `json:"k"`
+	K1                   int         `json:"k1"`
+}
+
+type cspaillierPubKeyJSON struct {
+	N  *common.Int `json:"n"`
+	G  *common.Int `json:"g"`
+	Y1 *common.Int `json:"y1"`
+	Y2 *common.Int `json:"y2"`
+	Y3 *common.Int `json:"y3"`
+	cspaillierParamsJSON
+}
+
+type cspaillierSecretKeyJSON struct {
+	N  *common.Int `json:"n"`
+	G  *common.Int `json:"g"`
+	X1 *common.Int `json:"x1"`
+	X2 *common.Int `json:"x2"`
+	X3 *common.Int `json:"x3"`
+	P  *common.Int `json:"p,omitempty"`
+	Q  *common.Int `json:"q,omitempty"`
+	cspaillierParamsJSON
+}
+
+func newCSPaillierParamsJSON(gamma *dlog.ZpDLog, n, g1, h1 *common.Int, k, k1 int) cspaillierParamsJSON {
+	return cspaillierParamsJSON{
+		DLogP:                common.NewInt(gamma.P),
+		DLogG:                common.NewInt(gamma.G),
+		DLogQ:                common.NewInt(gamma.OrderOfSubgroup),
+		VerifiableEncGroupN:  n,
+		VerifiableEncGroupG1: g1,
+		VerifiableEncGroupH1: h1,
+		K:                    k,
+		K1:                   k1,
+	}
+}
+
+func (p *cspaillierParamsJSON) gamma() *dlog.ZpDLog {
+	return &dlog.ZpDLog{
+		P:               p.DLogP.BigInt(),
+		G:               p.DLogG.BigInt(),
+		OrderOfSubgroup: p.DLogQ.BigInt(),
+	}
+}
+
+func (pubKey *CSPaillierPubKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(CSPaillierPubKeyType, &cspaillierPubKeyJSON{
+		N:  common.NewInt(pubKey.N),
+		G:  common.NewInt(pubKey.G),
+		Y1: common.NewInt(pubKey.Y1),
+		Y2: common.NewInt(pubKey.Y2),
+		Y3: common.NewInt(pubKey.Y3),
+		cspaillierParamsJSON: newCSPaillierParamsJSON(pubKey.Gamma,
+			common.NewInt(pubKey.VerifiableEncGroupN),
+			common.NewInt(pubKey.VerifiableEncGroupG1),
+			common.NewInt(pubKey.VerifiableEncGroupH1),
+			pubKey.K, pubKey.K1),
+	})
+}
+
+func (pubKey *CSPaillierPubKey) UnmarshalJSON(data []byte) error {
+	var v cspaillierPubKeyJSON
+	if err := common.UnmarshalVersioned(data, CSPaillierPubKeyType, &v); err != nil {
+		return err
+	}
+	*pubKey = CSPaillierPubKey{
+		N:                    v.N.BigInt(),
+		G:                    v.G.BigInt(),
+		Y1:                   v.Y1.BigInt(),
+		Y2:                   v.Y2.BigInt(),
+		Y3:                   v.Y3.BigInt(),
+		Gamma:                v.gamma(),
+		VerifiableEncGroupN:  v.VerifiableEncGroupN.BigInt(),
+		VerifiableEncGroupG1: v.VerifiableEncGroupG1.BigInt(),
+		VerifiableEncGroupH1: v.VerifiableEncGroupH1.BigInt(),
+		K:                    v.K,
+		K1:                   v.K1,
+	}
+	return nil
+}
+
+func (secKey *CSPaillierSecretKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(CSPaillierSecretKeyType, &cspaillierSecretKeyJSON{
+		N:  common.NewInt(secKey.N),
+		G:  common.NewInt(secKey.G),
+		X1: common.NewInt(secKey.X1),
+		X2: common.NewInt(secKey.X2),
+		X3: common.NewInt(secKey.X3),
+		P:  common.NewInt(secKey.P),
+		Q:  common.NewInt(secKey.Q),
+		cspaillierParamsJSON: newCSPaillierParamsJSON(secKey.Gamma,
+			common.NewInt(secKey.VerifiableEncGroupN),
+			common.NewInt(secKey.VerifiableEncGroupG1),
+			common.NewInt(secKey.VerifiableEncGroupH1),
+			secKey.K, secKey.K1),
+	})
+}
+
+func (secKey *CSPaillierSecretKey) UnmarshalJSON(data []byte) error {
+	var v cspaillierSecretKeyJSON
+	if err := common.UnmarshalVersioned(data, CSPaillierSecretKeyType, &v); err != nil {
+		return err
+	}
+	*secKey = CSPaillierSecretKey{
+		N:                    v.N.BigInt(),
+		G:                    v.G.BigInt(),
+		X1:                   v.X1.BigInt(),
+		X2:                   v.X2.BigInt(),
+		X3:                   v.X3.BigInt(),
+		P:                    v.P.BigInt(),
+		Q:                    v.Q.BigInt(),
+		Gamma:                v.gamma(),
+		VerifiableEncGroupN:  v.VerifiableEncGroupN.BigInt(),
+		VerifiableEncGroupG1: v.VerifiableEncGroupG1.BigInt(),
+		VerifiableEncGroupH1: v.VerifiableEncGroupH1.BigInt(),
+		K:                    v.K,
+		K1:                   v.K1,
+	}
+	return nil
+}
+
+type thresholdPaillierPubKeyJSON struct {
+	N                *common.Int   `json:"n"`
+	Threshold        int           `json:"threshold"`
+	V                *common.Int   `json:"v"`
+	VerificationKeys []*common.Int `json:"verification_keys"`
+}
+
+func (pubKey *ThresholdPaillierPubKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ThresholdPaillierPubKeyType, &thresholdPaillierPubKeyJSON{
+		N:                common.NewInt(pubKey.N),
+		Threshold:        pubKey.Threshold,
+		V:                common.NewInt(pubKey.V),
+		VerificationKeys: common.NewInts(pubKey.VerificationKeys),
+	})
+}
+
+func (pubKey *ThresholdPaillierPubKey) UnmarshalJSON(data []byte) error {
+	var v thresholdPaillierPubKeyJSON
+	if err := common.UnmarshalVersioned(data, ThresholdPaillierPubKeyType, &v); err != nil {
+		return err
+	}
+	*pubKey = ThresholdPaillierPubKey{
+		N:                v.N.BigInt(),
+		Threshold:        v.Threshold,
+		V:                v.V.BigInt(),
+		VerificationKeys: common.BigInts(v.VerificationKeys),
+	}
+	return nil
+}
+
+type thresholdPaillierKeyShareJSON struct {
+	Index  int                      `json:"index"`
+	S      *common.Int              `json:"s"`
+	PubKey *ThresholdPaillierPubKey `json:"public_key"`
+}
+
+func (share *ThresholdPaillierKeyShare) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ThresholdPaillierKeyShareType, &thresholdPaillierKeyShareJSON{
+		Index:  share.Index,
+		S:      common.NewInt(share.S),
+		PubKey: share.PubKey,
+	})
+}
+
+func (share *ThresholdPaillierKeyShare) UnmarshalJSON(data []byte) error {
+	var v thresholdPaillierKeyShareJSON
+	if err := common.UnmarshalVersioned(data, ThresholdPaillierKeyShareType, &v); err != nil {
+		return err
+	}
+	*share = ThresholdPaillierKeyShare{
+		Index:  v.Index,
+		S:      v.S.BigInt(),
+		PubKey: v.PubKey,
+	}
+	return nil
+}
+
+type paillierDecryptionShareJSON struct {
+	Index int         `json:"index"`
+	C     *common.Int `json:"c"`
+	A     *common.Int `json:"a"`
+	B     *common.Int `json:"b"`
+	Z     *common.Int `json:"z"`
+}
+
+func (share *PaillierDecryptionShare) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(PaillierDecryptionShareType, &paillierDecryptionShareJSON{
+		Index: share.Index,
+		C:     common.NewInt(share.C),
+		A:     common.NewInt(share.A),
+		B:     common.NewInt(share.B),
+		Z:     common.NewInt(share.Z),
+	})
+}
+
+func (share *PaillierDecryptionShare) UnmarshalJSON(data []byte) error {
+	var v paillierDecryptionShareJSON
+	if err := common.UnmarshalVersioned(data, PaillierDecryptionShareType, &v); err != nil {
+		return err
+	}
+	*share = PaillierDecryptionShare{
+		Index: v.Index,
+		C:     v.C.BigInt(),
+		A:     v.A.BigInt(),
+		B:     v.B.BigInt(),
+		Z:     v.Z.BigInt(),
+	}
+	return nil
+}
+
+type thresholdElGamalPubKeyJSON struct {
+	Group            *dlog.ZpDLog  `json:"group"`
+	Y                *common.Int   `json:"y"`
+	Threshold        int           `json:"threshold"`
+	VerificationKeys []*common.Int `json:"verification_keys"`
+}
+
+func (pubKey *ThresholdElGamalPubKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ThresholdElGamalPubKeyType, &thresholdElGamalPubKeyJSON{
+		Group:            pubKey.DLog,
+		Y:                common.NewInt(pubKey.Y),
+		Threshold:        pubKey.Threshold,
+		VerificationKeys: common.NewInts(pubKey.VerificationKeys),
+	})
+}
+
+func (pubKey *ThresholdElGamalPubKey) UnmarshalJSON(data []byte) error {
+	var v thresholdElGamalPubKeyJSON
+	if err := common.UnmarshalVersioned(data, ThresholdElGamalPubKeyType, &v); err != nil {
+		return err
+	}
+	*pubKey = ThresholdElGamalPubKey{
+		DLog:             v.Group,
+		Y:                v.Y.BigInt(),
+		Threshold:        v.Threshold,
+		VerificationKeys: common.BigInts(v.VerificationKeys),
+	}
+	return nil
+}
+
+type thresholdElGamalKeyShareJSON struct {
+	Index  int                     `json:"index"`
+	X      *common.Int             `json:"x"`
+	PubKey *ThresholdElGamalPubKey `json:"public_key"`
+}
+
+func (share *ThresholdElGamalKeyShare) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ThresholdElGamalKeyShareType, &thresholdElGamalKeyShareJSON{
+		Index:  share.Index,
+		X:      common.NewInt(share.X),
+		PubKey: share.PubKey,
+	})
+}
+
+func (share *ThresholdElGamalKeyShare) UnmarshalJSON(data []byte) error {
+	var v thresholdElGamalKeyShareJSON
+	if err := common.UnmarshalVersioned(data, ThresholdElGamalKeyShareType, &v); err != nil {
+		return err
+	}
+	*share = ThresholdElGamalKeyShare{
+		Index:  v.Index,
+		X:      v.X.BigInt(),
+		PubKey: v.PubKey,
+	}
+	return nil
+}
+
+type elGamalDecryptionShareJSON struct {
+	Index int         `json:"index"`
+	D     *common.Int `json:"d"`
+	X1    *common.Int `json:"x1"`
+	X2    *common.Int `json:"x2"`
+	Z     *common.Int `json:"z"`
+}
+
+func (share *ElGamalDecryptionShare) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(ElGamalDecryptionShareType, &elGamalDecryptionShareJSON{
+		Index: share.Index,
+		D:     common.NewInt(share.D),
+		X1:    common.NewInt(share.Proof.X1),
+		X2:    common.NewInt(share.Proof.X2),
+		Z:     common.NewInt(share.Proof.Z),
+	})
+}
+
+func (share *ElGamalDecryptionShare) UnmarshalJSON(data []byte) error {
+	var v elGamalDecryptionShareJSON
+	if err := common.UnmarshalVersioned(data, ElGamalDecryptionShareType, &v); err != nil {
+		return err
+	}
+	*share = ElGamalDecryptionShare{
+		Index: v.Index,
+		D:     v.D.BigInt(),
+		Proof: &dlogproofs.DLogEqualityProof{
+			X1: v.X1.BigInt(),
+			X2: v.X2.BigInt(),
+			Z:  v.Z.BigInt(),
+		},
+	}
+	return nil
+}
@@ -3,7 +3,10 @@ package encryption
 import (
 	"crypto/rand"
 	"errors"
+	"github.com/golang/protobuf/proto"
 	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/keystore"
+	pb "github.com/xlab-si/emmy/protobuf"
 	"math/big"
 )
 
@@ -11,15 +14,36 @@ import (
 type Paillier struct {
 	primeLength int
 	lambda      *big.Int
+	mu          *big.Int // L(g^lambda mod n^2)^-1 mod n
+	crt         *paillierCRT
 	pubKey      *PaillierPubKey
 }
 
+// paillierCRT holds the constants for decryption modulo p^2 and q^2 (see section 7 of
+// Paillier's paper), which is much faster than decryption with lambda modulo n^2.
+type paillierCRT struct {
+	p, q   *big.Int
+	p2, q2 *big.Int // p^2, q^2
+	hp, hq *big.Int // L_p(g^(p-1) mod p^2)^-1 mod p, L_q(g^(q-1) mod q^2)^-1 mod q
+	qInv   *big.Int // q^-1 mod p
+}
+
 type PaillierPubKey struct {
 	n  *big.Int
 	n2 *big.Int
 	g  *big.Int
 }
 
+type PaillierSecretKey struct {
+	N      *big.Int
+	G      *big.Int
+	Lambda *big.Int
+	// P and Q are the prime factors of N, used for faster decryption. They are nil
+	// for keys generated by older versions of emmy.
+	P *big.Int
+	Q *big.Int
+}
+
 func NewPaillier(primeLength int) *Paillier {
 	var paillier Paillier
 
@@ -31,6 +55,8 @@ func NewPaillier(primeLength int) *Paillier {
 	return &paillier
 }
 
+// NewPubPaillier returns Paillier with the given public key. It can encrypt, compute
+// with ciphertexts and produce and verify proofs about them, but it cannot decrypt.
 func NewPubPaillier(pubKey *PaillierPubKey) *Paillier {
 	var paillier Paillier
 
@@ -41,16 +67,154 @@ func NewPubPaillier(pubKey *PaillierPubKey) *Paillier {
 	return &paillier
 }
 
+// NewPaillierFromSecKey returns Paillier with the secret key stored at path, either protobuf
+// (see StoreSecKey), JSON or PEM encoded. If the key is encrypted, the passphrase is obtained with keystore.Passphrase.
+func NewPaillierFromSecKey(path string) (*Paillier, error) {
+	bytes, err := keystore.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if common.IsJSONOrPEM(bytes) {
+		var secKey PaillierSecretKey
+		if err := common.UnmarshalJSONOrPEM(bytes, &secKey); err != nil {
+			return nil, err
+		}
+		return NewPaillierFromSecretKey(&secKey), nil
+	}
+	sKey := &pb.PaillierSecretKey{}
+	err = proto.Unmarshal(bytes, sKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secKey := PaillierSecretKey{
+		N:      new(big.Int).SetBytes(sKey.N),
+		G:      new(big.Int).SetBytes(sKey.G),
+		Lambda: new(big.Int).SetBytes(sKey.Lambda),
+	}
+	if len(sKey.P) > 0 && len(sKey.Q) > 0 {
+		secKey.P = new(big.Int).SetBytes(sKey.P)
+		secKey.Q = new(big.Int).SetBytes(sKey.Q)
+	}
+	return NewPaillierFromSecretKey(&secKey), nil
+}
+
+// NewPaillierFromSecretKey returns Paillier with the given secret key. If the key
+// contains the prime factors of N, they are used for faster decryption.
+func NewPaillierFromSecretKey(secKey *PaillierSecretKey) *Paillier {
+	paillier := Paillier{
+		lambda: secKey.Lambda,
+		pubKey: NewPaillierPubKey(secKey.N, secKey.G),
+	}
+	paillier.precompute(secKey.P, secKey.Q)
+
+	return &paillier
+}
+
+// NewPaillierFromPubKeyFile returns Paillier with the public key stored at path, either
+// protobuf (see StorePubKey), JSON or PEM encoded. It can only be used for encryption.
+func NewPaillierFromPubKeyFile(path string) (*Paillier, error) {
+	bytes, err := common.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if common.IsJSONOrPEM(bytes) {
+		var pubKey PaillierPubKey
+		if err := common.UnmarshalJSONOrPEM(bytes, &pubKey); err != nil {
+			return nil, err
+		}
+		return NewPubPaillier(&pubKey), nil
+	}
+	pKey := &pb.PaillierPubKey{}
+	err = proto.Unmarshal(bytes, pKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPubPaillier(ToPaillierPubKey(pKey)), nil
+}
+
+// NewPaillierPubKey returns the public key with modulus n and generator g.
+func NewPaillierPubKey(n, g *big.Int) *PaillierPubKey {
+	return &PaillierPubKey{
+		n:  n,
+		n2: new(big.Int).Mul(n, n),
+		g:  g,
+	}
+}
+
+// ToPaillierPubKey returns the public key encoded in pKey.
+func ToPaillierPubKey(pKey *pb.PaillierPubKey) *PaillierPubKey {
+	return NewPaillierPubKey(new(big.Int).SetBytes(pKey.N), new(big.Int).SetBytes(pKey.G))
+}
+
+// ToPbPaillierPubKey returns the protobuf encoding of pubKey.
+func ToPbPaillierPubKey(pubKey *PaillierPubKey) *pb.PaillierPubKey {
+	return &pb.PaillierPubKey{
+		N: pubKey.n.Bytes(),
+		G: pubKey.g.Bytes(),
+	}
+}
+
+// GetN returns the modulus n of the public key.
+func (pubKey *PaillierPubKey) GetN() *big.Int {
+	return pubKey.n
+}
+
+// GetG returns the generator g of the public key.
+func (pubKey *PaillierPubKey) GetG() *big.Int {
+	return pubKey.g
+}
+
+// StoreSecKey writes the secret key (unencrypted) to the file at path, readable only by its owner.
+func (paillier *Paillier) StoreSecKey(path string) error {
+	return paillier.storeSecKey(path, nil)
+}
+
+// StoreEncryptedSecKey writes the secret key encrypted with passphrase to the file at path.
+func (paillier *Paillier) StoreEncryptedSecKey(path string, passphrase []byte) error {
+	return paillier.storeSecKey(path, passphrase)
+}
+
+func (paillier *Paillier) storeSecKey(path string, passphrase []byte) error {
+	secKey := &pb.PaillierSecretKey{
+		N:      paillier.pubKey.n.Bytes(),
+		G:      paillier.pubKey.g.Bytes(),
+		Lambda: paillier.lambda.Bytes(),
+	}
+	if paillier.crt != nil {
+		secKey.P = paillier.crt.p.Bytes()
+		secKey.Q = paillier.crt.q.Bytes()
+	}
+	data, err := proto.Marshal(secKey)
+	if err != nil {
+		return err
+	}
+	return keystore.Store(data, path, passphrase)
+}
+
+func (paillier *Paillier) StorePubKey(path string) error {
+	data, err := proto.Marshal(ToPbPaillierPubKey(paillier.pubKey))
+	if err != nil {
+		return err
+	}
+	return common.Store(data, path)
+}
+
 func (paillier *Paillier) Encrypt(m *big.Int) (*big.Int, error) {
-	if m.Cmp(paillier.pubKey.n) >= 0 {
-		err := errors.New("msg is too big")
+	return paillier.EncryptWithRandomness(m, paillier.GetRandomness())
+}
+
+// EncryptWithRandomness encrypts m as c = g^m * r^n mod n^2 with the given r from Z_n*.
+// Proofs about ciphertexts (see ProvePlaintextKnowledge) require the randomness used
+// for encryption, which can be obtained with GetRandomness.
+func (paillier *Paillier) EncryptWithRandomness(m, r *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(paillier.pubKey.n) >= 0 {
+		err := errors.New("msg is not from Z_n")
 		return nil, err
 	}
 
 	// c = g^m * r^n mod n^2
-	// r should be from Z_n*, but as it is very unlikely that we get an element which is not
-	// invertible, we don't check
-	r := common.GetRandomInt(paillier.pubKey.n)
 	t1 := new(big.Int).Exp(paillier.pubKey.g, m, paillier.pubKey.n2) // g^m
 	t2 := new(big.Int).Exp(r, paillier.pubKey.n, paillier.pubKey.n2) // r^n
 	c := new(big.Int).Mul(t1, t2)
@@ -59,35 +223,130 @@ func (paillier *Paillier) Encrypt(m *big.Int) (*big.Int, error) {
 	return c, nil
 }
 
+// GetRandomness returns a random r from Z_n*, to be used for encryption or
+// re-randomization of ciphertexts.
+func (paillier *Paillier) GetRandomness() *big.Int {
+	// r should be from Z_n*, but as it is very unlikely that we get an element which is not
+	// invertible, we don't check
+	return common.GetRandomInt(paillier.pubKey.n)
+}
+
+// Add returns the encryption of m1 + m2 mod n, given encryptions c1 of m1 and c2 of m2.
+func (paillier *Paillier) Add(c1, c2 *big.Int) *big.Int {
+	c := new(big.Int).Mul(c1, c2)
+	return c.Mod(c, paillier.pubKey.n2)
+}
+
+// MulScalar returns the encryption of k * m mod n, given encryption c of m. k can be
+// negative, so that for example MulScalar(c, -1) gives the encryption of -m mod n.
+func (paillier *Paillier) MulScalar(c, k *big.Int) *big.Int {
+	return common.Exponentiate(c, k, paillier.pubKey.n2)
+}
+
+// Rerandomize returns a fresh encryption c * r^n mod n^2 of the plaintext of c, which
+// cannot be linked to c by anybody not holding the secret key, together with the
+// randomness r used for re-randomization.
+func (paillier *Paillier) Rerandomize(c *big.Int) (*big.Int, *big.Int) {
+	r := paillier.GetRandomness()
+	return paillier.RerandomizeWithRandomness(c, r), r
+}
+
+// RerandomizeWithRandomness returns c * r^n mod n^2 for the given r from Z_n*.
+func (paillier *Paillier) RerandomizeWithRandomness(c, r *big.Int) *big.Int {
+	t := new(big.Int).Exp(r, paillier.pubKey.n, paillier.pubKey.n2)
+	t.Mul(c, t)
+	return t.Mod(t, paillier.pubKey.n2)
+}
+
+// Decrypt returns the plaintext of c. c is not modified.
 func (paillier *Paillier) Decrypt(c *big.Int) (*big.Int, error) {
-	if c.Cmp(paillier.pubKey.n2) >= 0 {
-		err := errors.New("cipertext is too big")
+	if c.Sign() < 0 || c.Cmp(paillier.pubKey.n2) >= 0 {
+		err := errors.New("ciphertext is not from Z_{n^2}")
 		return nil, err
 	}
 
-	// p = (c^lambda - 1) / (g^lambda - 1) mod n
-	c1 := c.Exp(c, paillier.lambda, paillier.pubKey.n2)
-	c1.Sub(c1, big.NewInt(1))
-	c1.Div(c1, paillier.pubKey.n)
+	if paillier.crt == nil {
+		// m = L(c^lambda mod n^2) * mu mod n
+		m := l(new(big.Int).Exp(c, paillier.lambda, paillier.pubKey.n2), paillier.pubKey.n)
+		m.Mul(m, paillier.mu)
+		return m.Mod(m, paillier.pubKey.n), nil
+	}
 
-	g1 := new(big.Int).Exp(paillier.pubKey.g, paillier.lambda, paillier.pubKey.n2)
-	g1.Sub(g1, big.NewInt(1))
-	g1.Div(g1, paillier.pubKey.n)
+	// mp = L_p(c^(p-1) mod p^2) * hp mod p, mq = L_q(c^(q-1) mod q^2) * hq mod q
+	crt := paillier.crt
+	mp := decryptModPrime(c, crt.p, crt.p2, crt.hp)
+	mq := decryptModPrime(c, crt.q, crt.q2, crt.hq)
 
-	g1_inv := new(big.Int).ModInverse(g1, paillier.pubKey.n2)
+	// m = mq + q * ((mp - mq) * q^-1 mod p)
+	m := mp.Sub(mp, mq)
+	m.Mul(m, crt.qInv)
+	m.Mod(m, crt.p)
+	m.Mul(m, crt.q)
+	return m.Add(m, mq), nil
+}
 
-	p := new(big.Int).Mul(c1, g1_inv)
-	p.Mod(p, paillier.pubKey.n)
-	return p, nil
+// decryptModPrime returns L_p(c^(p-1) mod p^2) * h mod p for a prime factor p of n.
+func decryptModPrime(c, p, p2, h *big.Int) *big.Int {
+	pMin := new(big.Int).Sub(p, big.NewInt(1))
+	m := l(new(big.Int).Exp(c, pMin, p2), p)
+	m.Mul(m, h)
+	return m.Mod(m, p)
+}
+
+// precompute computes the constants needed for decryption. If p and q (the prime
+// factors of n) are given, they are used for decryption modulo p^2 and q^2.
+func (paillier *Paillier) precompute(p, q *big.Int) {
+	n, n2, g := paillier.pubKey.n, paillier.pubKey.n2, paillier.pubKey.g
+	paillier.mu = l(new(big.Int).Exp(g, paillier.lambda, n2), n)
+	paillier.mu.ModInverse(paillier.mu, n)
+
+	if p == nil || q == nil || new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		paillier.crt = nil
+		return
+	}
+	crt := &paillierCRT{
+		p:    p,
+		q:    q,
+		p2:   new(big.Int).Mul(p, p),
+		q2:   new(big.Int).Mul(q, q),
+		qInv: new(big.Int).ModInverse(q, p),
+	}
+	crt.hp = decryptModPrime(g, p, crt.p2, big.NewInt(1))
+	crt.hp.ModInverse(crt.hp, p)
+	crt.hq = decryptModPrime(g, q, crt.q2, big.NewInt(1))
+	crt.hq.ModInverse(crt.hq, q)
+	paillier.crt = crt
+}
+
+// l returns L(x) = (x - 1) / n.
+func l(x, n *big.Int) *big.Int {
+	t := new(big.Int).Sub(x, big.NewInt(1))
+	return t.Div(t, n)
 }
 
 func (paillier *Paillier) GetPubKey() *PaillierPubKey {
 	return paillier.pubKey
 }
 
+func (paillier *Paillier) GetSecretKey() *PaillierSecretKey {
+	secKey := &PaillierSecretKey{
+		N:      paillier.pubKey.n,
+		G:      paillier.pubKey.g,
+		Lambda: paillier.lambda,
+	}
+	if paillier.crt != nil {
+		secKey.P = paillier.crt.p
+		secKey.Q = paillier.crt.q
+	}
+	return secKey
+}
+
 func (paillier *Paillier) generateKey() {
 	p, _ := rand.Prime(rand.Reader, paillier.primeLength)
 	q, _ := rand.Prime(rand.Reader, paillier.primeLength)
+	for p.Cmp(q) == 0 {
+		q, _ = rand.Prime(rand.Reader, paillier.primeLength)
+	}
 	p_min := new(big.Int).Sub(p, big.NewInt(1)) // p-1
 	q_min := new(big.Int).Sub(q, big.NewInt(1)) // q-1
 
@@ -122,5 +381,5 @@ func (paillier *Paillier) generateKey() {
 			break
 		}
 	}
-
+	paillier.precompute(p, q)
 }
@@ -0,0 +1,229 @@
+package encryption
+
+import (
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+)
+
+// Non-interactive (Fiat-Shamir) zero-knowledge proofs about Paillier ciphertexts, as in
+// Cramer, Damgard, Nielsen: Multiparty Computation from Threshold Homomorphic Encryption
+// (https://eprint.iacr.org/2000/055.pdf). They are produced by the party that created
+// the ciphertexts (and thus knows the randomness used), and verified with the public key.
+
+// Names of the proofs, hashed into challenges so that a proof of one kind cannot be
+// passed off as a proof of another kind.
+var (
+	plaintextKnowledgeProofName = new(big.Int).SetBytes([]byte("paillier plaintext knowledge"))
+	zeroProofName               = new(big.Int).SetBytes([]byte("paillier encryption of zero"))
+	multiplicationProofName     = new(big.Int).SetBytes([]byte("paillier multiplication"))
+)
+
+// PaillierPlaintextKnowledgeProof proves knowledge of m and r such that c = g^m * r^n mod n^2.
+type PaillierPlaintextKnowledgeProof struct {
+	A *big.Int // g^x * s^n
+	W *big.Int // x + e * m mod n
+	Z *big.Int // s * r^e * g^((x + e * m) div n) mod n
+}
+
+// PaillierZeroProof proves that c encrypts 0, that is c = r^n mod n^2 for some r.
+type PaillierZeroProof struct {
+	A *big.Int // s^n
+	Z *big.Int // s * r^e mod n
+}
+
+// PaillierMultiplicationProof proves that cc encrypts the product of plaintexts of
+// ca and cb, that is that the prover knows a, ra and rc such that ca = g^a * ra^n and
+// cc = cb^a * rc^n mod n^2.
+type PaillierMultiplicationProof struct {
+	A  *big.Int // g^x * u^n
+	B  *big.Int // cb^x * v^n
+	W  *big.Int // x + e * a mod n
+	Z1 *big.Int // u * ra^e * g^((x + e * a) div n) mod n
+	Z2 *big.Int // v * rc^e * cb^((x + e * a) div n) mod n
+}
+
+// ProvePlaintextKnowledge returns a proof that the prover knows the plaintext m of
+// c = EncryptWithRandomness(m, r).
+func (paillier *Paillier) ProvePlaintextKnowledge(c, m, r *big.Int) *PaillierPlaintextKnowledgeProof {
+	n, n2, g := paillier.pubKey.n, paillier.pubKey.n2, paillier.pubKey.g
+	x := common.GetRandomInt(n)
+	s := paillier.GetRandomness()
+	a := new(big.Int).Exp(g, x, n2)
+	a.Mul(a, new(big.Int).Exp(s, n, n2))
+	a.Mod(a, n2)
+
+	e := paillier.getChallenge(plaintextKnowledgeProofName, c, a)
+	w, k := paillier.divModN(new(big.Int).Add(x, new(big.Int).Mul(e, m)))
+
+	// z = s * r^e * g^k mod n
+	z := new(big.Int).Exp(r, e, n)
+	z.Mul(z, s)
+	z.Mul(z, new(big.Int).Exp(g, k, n))
+	z.Mod(z, n)
+
+	return &PaillierPlaintextKnowledgeProof{
+		A: a,
+		W: w,
+		Z: z,
+	}
+}
+
+// VerifyPlaintextKnowledge verifies the proof that the prover knows the plaintext of c.
+func (paillier *Paillier) VerifyPlaintextKnowledge(c *big.Int, proof *PaillierPlaintextKnowledgeProof) bool {
+	if proof == nil || !paillier.isCiphertext(c) || !paillier.isCiphertext(proof.A) ||
+		!paillier.isPlaintext(proof.W) || !paillier.isRandomness(proof.Z) {
+		return false
+	}
+	n, n2, g := paillier.pubKey.n, paillier.pubKey.n2, paillier.pubKey.g
+	e := paillier.getChallenge(plaintextKnowledgeProofName, c, proof.A)
+
+	// check if g^w * z^n = a * c^e mod n^2
+	left := new(big.Int).Exp(g, proof.W, n2)
+	left.Mul(left, new(big.Int).Exp(proof.Z, n, n2))
+	left.Mod(left, n2)
+	right := new(big.Int).Exp(c, e, n2)
+	right.Mul(right, proof.A)
+	right.Mod(right, n2)
+	return left.Cmp(right) == 0
+}
+
+// ProveZero returns a proof that c = EncryptWithRandomness(0, r) encrypts 0. It is
+// also a proof that two ciphertexts c1 and c2 encrypt the same plaintext, when c is
+// Add(c1, MulScalar(c2, -1)) and r is the quotient of the randomness of c1 and c2.
+func (paillier *Paillier) ProveZero(c, r *big.Int) *PaillierZeroProof {
+	n, n2 := paillier.pubKey.n, paillier.pubKey.n2
+	s := paillier.GetRandomness()
+	a := new(big.Int).Exp(s, n, n2)
+
+	e := paillier.getChallenge(zeroProofName, c, a)
+	z := new(big.Int).Exp(r, e, n)
+	z.Mul(z, s)
+	z.Mod(z, n)
+
+	return &PaillierZeroProof{
+		A: a,
+		Z: z,
+	}
+}
+
+// VerifyZero verifies the proof that c encrypts 0.
+func (paillier *Paillier) VerifyZero(c *big.Int, proof *PaillierZeroProof) bool {
+	if proof == nil || !paillier.isCiphertext(c) || !paillier.isCiphertext(proof.A) ||
+		!paillier.isRandomness(proof.Z) {
+		return false
+	}
+	n, n2 := paillier.pubKey.n, paillier.pubKey.n2
+	e := paillier.getChallenge(zeroProofName, c, proof.A)
+
+	// check if z^n = a * c^e mod n^2
+	left := new(big.Int).Exp(proof.Z, n, n2)
+	right := new(big.Int).Exp(c, e, n2)
+	right.Mul(right, proof.A)
+	right.Mod(right, n2)
+	return left.Cmp(right) == 0
+}
+
+// ProveMultiplication returns a proof that cc encrypts the product of plaintexts of ca
+// and cb, where ca = EncryptWithRandomness(a, ra) and cc is computed from cb as
+// RerandomizeWithRandomness(MulScalar(cb, a), rc).
+func (paillier *Paillier) ProveMultiplication(ca, a, ra, cb, cc, rc *big.Int) *PaillierMultiplicationProof {
+	n, n2, g := paillier.pubKey.n, paillier.pubKey.n2, paillier.pubKey.g
+	x := common.GetRandomInt(n)
+	u := paillier.GetRandomness()
+	v := paillier.GetRandomness()
+
+	// A = g^x * u^n, B = cb^x * v^n
+	A := new(big.Int).Exp(g, x, n2)
+	A.Mul(A, new(big.Int).Exp(u, n, n2))
+	A.Mod(A, n2)
+	B := new(big.Int).Exp(cb, x, n2)
+	B.Mul(B, new(big.Int).Exp(v, n, n2))
+	B.Mod(B, n2)
+
+	e := paillier.getChallenge(multiplicationProofName, ca, cb, cc, A, B)
+	w, k := paillier.divModN(new(big.Int).Add(x, new(big.Int).Mul(e, a)))
+
+	// z1 = u * ra^e * g^k mod n, z2 = v * rc^e * cb^k mod n
+	z1 := new(big.Int).Exp(ra, e, n)
+	z1.Mul(z1, u)
+	z1.Mul(z1, new(big.Int).Exp(g, k, n))
+	z1.Mod(z1, n)
+	z2 := new(big.Int).Exp(rc, e, n)
+	z2.Mul(z2, v)
+	z2.Mul(z2, new(big.Int).Exp(cb, k, n))
+	z2.Mod(z2, n)
+
+	return &PaillierMultiplicationProof{
+		A:  A,
+		B:  B,
+		W:  w,
+		Z1: z1,
+		Z2: z2,
+	}
+}
+
+// VerifyMultiplication verifies the proof that cc encrypts the product of plaintexts of
+// ca and cb.
+func (paillier *Paillier) VerifyMultiplication(ca, cb, cc *big.Int, proof *PaillierMultiplicationProof) bool {
+	if proof == nil || !paillier.isCiphertext(ca) || !paillier.isCiphertext(cb) ||
+		!paillier.isCiphertext(cc) || !paillier.isCiphertext(proof.A) ||
+		!paillier.isCiphertext(proof.B) || !paillier.isPlaintext(proof.W) ||
+		!paillier.isRandomness(proof.Z1) || !paillier.isRandomness(proof.Z2) {
+		return false
+	}
+	n, n2, g := paillier.pubKey.n, paillier.pubKey.n2, paillier.pubKey.g
+	e := paillier.getChallenge(multiplicationProofName, ca, cb, cc, proof.A, proof.B)
+
+	// check if g^w * z1^n = A * ca^e mod n^2
+	left := new(big.Int).Exp(g, proof.W, n2)
+	left.Mul(left, new(big.Int).Exp(proof.Z1, n, n2))
+	left.Mod(left, n2)
+	right := new(big.Int).Exp(ca, e, n2)
+	right.Mul(right, proof.A)
+	right.Mod(right, n2)
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// check if cb^w * z2^n = B * cc^e mod n^2
+	left.Exp(cb, proof.W, n2)
+	left.Mul(left, new(big.Int).Exp(proof.Z2, n, n2))
+	left.Mod(left, n2)
+	right.Exp(cc, e, n2)
+	right.Mul(right, proof.B)
+	right.Mod(right, n2)
+	return left.Cmp(right) == 0
+}
+
+// getChallenge returns the challenge for a proof with the given name about the given
+// values (the statement and the first message of the prover). The challenge is shorter
+// than the prime factors of n, as required for soundness of the proofs.
+func (paillier *Paillier) getChallenge(name *big.Int, values ...*big.Int) *big.Int {
+	hashed := append([]*big.Int{name, paillier.pubKey.n, paillier.pubKey.g}, values...)
+	bits := paillier.pubKey.n.BitLen()/2 - 1
+	b := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	return new(big.Int).Mod(common.Hash(hashed...), b)
+}
+
+// divModN returns x mod n and x div n.
+func (paillier *Paillier) divModN(x *big.Int) (*big.Int, *big.Int) {
+	k, w := new(big.Int).DivMod(x, paillier.pubKey.n, new(big.Int))
+	return w, k
+}
+
+// isCiphertext returns true if c is from Z_{n^2}*.
+func (paillier *Paillier) isCiphertext(c *big.Int) bool {
+	return c != nil && c.Sign() > 0 && c.Cmp(paillier.pubKey.n2) < 0 &&
+		new(big.Int).GCD(nil, nil, c, paillier.pubKey.n).Cmp(big.NewInt(1)) == 0
+}
+
+// isPlaintext returns true if m is from Z_n.
+func (paillier *Paillier) isPlaintext(m *big.Int) bool {
+	return m != nil && m.Sign() >= 0 && m.Cmp(paillier.pubKey.n) < 0
+}
+
+// isRandomness returns true if r is from Z_n*.
+func (paillier *Paillier) isRandomness(r *big.Int) bool {
+	return r != nil && r.Sign() > 0 && r.Cmp(paillier.pubKey.n) < 0 &&
+		new(big.Int).GCD(nil, nil, r, paillier.pubKey.n).Cmp(big.NewInt(1)) == 0
+}
@@ -0,0 +1,223 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/dlogproofs"
+	"github.com/xlab-si/emmy/keystore"
+	"github.com/xlab-si/emmy/secretsharing"
+	"math/big"
+)
+
+// Threshold ElGamal decryption (see for example Cramer, Gennaro, Schoenmakers: A Secure
+// and Optimally Efficient Multi-Authority Election Scheme).
+//
+// A dealer generates the secret key x and splits it with Shamir's scheme modulo the
+// order q of the group among numberOfShares trustees. Ciphertexts are ordinary ElGamal
+// ciphertexts (encrypt with NewPubElGamal(pubKey.GetElGamalPubKey())); for tallying
+// votes, exponential ElGamal is used, as multiplying ciphertexts adds the votes. Each
+// trustee i computes a decryption share c1^x_i of ciphertext (c1, c2), together with a
+// Chaum-Pedersen proof that log_g(h_i) = log_c1(c1^x_i), where h_i = g^x_i is its public
+// verification key. Any threshold valid decryption shares are combined into
+// c1^x = prod (c1^x_i)^lambda_i, which gives the plaintext c2 / c1^x.
+
+// ThresholdElGamalPubKey is the public key of threshold ElGamal.
+type ThresholdElGamalPubKey struct {
+	DLog      *dlog.ZpDLog
+	Y         *big.Int // g^x
+	Threshold int      // number of decryption shares needed for decryption
+	// VerificationKeys[i-1] is g^x_i, where x_i is the share of the trustee with index i.
+	// They are used to verify decryption shares.
+	VerificationKeys []*big.Int
+}
+
+// ThresholdElGamalKeyShare is the key share of the trustee with the given index
+// (from 1 to the number of shares).
+type ThresholdElGamalKeyShare struct {
+	Index  int
+	X      *big.Int // the share of the secret key
+	PubKey *ThresholdElGamalPubKey
+}
+
+// ElGamalDecryptionShare is a decryption share c1^x_i of ciphertext (c1, c2), computed
+// by the trustee with the given index, together with the proof that
+// log_g(h_i) = log_c1(c1^x_i).
+type ElGamalDecryptionShare struct {
+	Index int
+	D     *big.Int
+	Proof *dlogproofs.DLogEqualityProof
+}
+
+// NewThresholdElGamalKeys generates a threshold ElGamal key in the given group, and
+// splits it into numberOfShares key shares, any threshold of which can decrypt. The key
+// shares should be given to the trustees and then deleted.
+func NewThresholdElGamalKeys(group *dlog.ZpDLog, threshold, numberOfShares int) (*ThresholdElGamalPubKey,
+	[]*ThresholdElGamalKeyShare, error) {
+	x := common.GetRandomInt(group.GetOrderOfSubgroup())
+	dealer, _ := secretsharing.NewDealer()
+	shares, err := dealer.SplitInt(x, group.GetOrderOfSubgroup(), threshold, numberOfShares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	y, _ := group.ExponentiateBaseG(x)
+	pubKey := &ThresholdElGamalPubKey{
+		DLog:             group,
+		Y:                y,
+		Threshold:        threshold,
+		VerificationKeys: make([]*big.Int, numberOfShares),
+	}
+	keyShares := make([]*ThresholdElGamalKeyShare, numberOfShares)
+	for i, share := range shares {
+		pubKey.VerificationKeys[i], _ = group.ExponentiateBaseG(share.Value)
+		keyShares[i] = &ThresholdElGamalKeyShare{
+			Index:  share.Index,
+			X:      share.Value,
+			PubKey: pubKey,
+		}
+	}
+
+	return pubKey, keyShares, nil
+}
+
+// NewThresholdElGamalKeyShareFromFile returns the key share stored at path, JSON or PEM
+// encoded (see Store). If the key share is encrypted, the passphrase is obtained with
+// keystore.Passphrase.
+func NewThresholdElGamalKeyShareFromFile(path string) (*ThresholdElGamalKeyShare, error) {
+	bytes, err := keystore.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var share ThresholdElGamalKeyShare
+	if err := common.UnmarshalJSONOrPEM(bytes, &share); err != nil {
+		return nil, err
+	}
+	if share.PubKey == nil || share.PubKey.DLog == nil ||
+		share.Index < 1 || share.Index > share.PubKey.NumberOfShares() {
+		return nil, fmt.Errorf("Invalid threshold ElGamal key share in %v", path)
+	}
+	return &share, nil
+}
+
+// Store writes the key share PEM encoded to the file at path, readable only by its
+// owner. If passphrase is not nil, the key share is encrypted with it.
+func (share *ThresholdElGamalKeyShare) Store(path string, passphrase []byte) error {
+	data, err := common.MarshalPEM(share)
+	if err != nil {
+		return err
+	}
+	return keystore.Store(data, path, passphrase)
+}
+
+// GetElGamalPubKey returns the ElGamal public key for encryption of messages that are
+// decrypted by the trustees.
+func (pubKey *ThresholdElGamalPubKey) GetElGamalPubKey() *ElGamalPubKey {
+	return &ElGamalPubKey{
+		DLog: pubKey.DLog,
+		Y:    pubKey.Y,
+	}
+}
+
+// NumberOfShares returns the number of key shares.
+func (pubKey *ThresholdElGamalPubKey) NumberOfShares() int {
+	return len(pubKey.VerificationKeys)
+}
+
+// Decrypt returns the decryption share of ciphertext c, together with the proof of
+// its correctness.
+func (share *ThresholdElGamalKeyShare) Decrypt(c *ElGamalCiphertext) (*ElGamalDecryptionShare, error) {
+	group := share.PubKey.DLog
+	elgamal := NewPubElGamal(share.PubKey.GetElGamalPubKey())
+	if c == nil || !elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) {
+		return nil, errors.New("ciphertext is not from the group")
+	}
+
+	d, _ := group.Exponentiate(c.C1, share.X)
+	proof := dlogproofs.ProveDLogEquality(share.X, group.G, c.C1,
+		share.PubKey.VerificationKeys[share.Index-1], d, group)
+	return &ElGamalDecryptionShare{
+		Index: share.Index,
+		D:     d,
+		Proof: proof,
+	}, nil
+}
+
+// VerifyDecryptionShare returns true if share is a correct decryption share of c.
+func (pubKey *ThresholdElGamalPubKey) VerifyDecryptionShare(c *ElGamalCiphertext,
+	share *ElGamalDecryptionShare) bool {
+	if share == nil || share.Index < 1 || share.Index > pubKey.NumberOfShares() || c == nil {
+		return false
+	}
+	elgamal := NewPubElGamal(pubKey.GetElGamalPubKey())
+	if !elgamal.isElement(c.C1) || !elgamal.isElement(c.C2) || !elgamal.isElement(share.D) {
+		return false
+	}
+	return dlogproofs.VerifyDLogEquality(share.Proof, pubKey.DLog.G, c.C1,
+		pubKey.VerificationKeys[share.Index-1], share.D, pubKey.DLog)
+}
+
+// Combine returns the plaintext of c, computed from its decryption shares. Shares that
+// don't verify are ignored, and an error is returned if there are less than threshold
+// valid shares from distinct trustees.
+func (pubKey *ThresholdElGamalPubKey) Combine(c *ElGamalCiphertext,
+	shares []*ElGamalDecryptionShare) (*big.Int, error) {
+	var valid []*ElGamalDecryptionShare
+	seen := make(map[int]bool)
+	for _, share := range shares {
+		if len(valid) == pubKey.Threshold {
+			break
+		}
+		if !pubKey.VerifyDecryptionShare(c, share) || seen[share.Index] {
+			continue
+		}
+		seen[share.Index] = true
+		valid = append(valid, share)
+	}
+	if len(valid) < pubKey.Threshold {
+		return nil, fmt.Errorf("%d valid decryption shares, %d needed", len(valid), pubKey.Threshold)
+	}
+
+	// c1^x = prod (c1^x_i)^lambda_i, where lambda_i are Lagrange coefficients modulo q
+	group := pubKey.DLog
+	q := group.GetOrderOfSubgroup()
+	indices := make([]int, len(valid))
+	for i, share := range valid {
+		indices[i] = share.Index
+	}
+	s := big.NewInt(1)
+	for _, share := range valid {
+		num := big.NewInt(1)
+		den := big.NewInt(1)
+		for _, j := range indices {
+			if j == share.Index {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(j)))
+			den.Mul(den, big.NewInt(int64(j-share.Index)))
+		}
+		lambda := new(big.Int).Mod(den, q)
+		lambda.ModInverse(lambda, q)
+		lambda.Mul(lambda, num)
+		lambda.Mod(lambda, q)
+		t, _ := group.Exponentiate(share.D, lambda)
+		s, _ = group.Multiply(s, t)
+	}
+
+	// m = c2 / c1^x
+	s.ModInverse(s, group.P)
+	m, _ := group.Multiply(c.C2, s)
+	return m, nil
+}
+
+// CombineExponential returns the plaintext m of exponential ElGamal ciphertext c, if
+// 0 <= m < bound, computed from its decryption shares as in Combine.
+func (pubKey *ThresholdElGamalPubKey) CombineExponential(c *ElGamalCiphertext,
+	shares []*ElGamalDecryptionShare, bound *big.Int) (*big.Int, error) {
+	gm, err := pubKey.Combine(c, shares)
+	if err != nil {
+		return nil, err
+	}
+	return discreteLog(pubKey.DLog, gm, bound)
+}
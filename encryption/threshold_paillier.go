@@ -0,0 +1,301 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/keystore"
+	"github.com/xlab-si/emmy/secretsharing"
+	"math/big"
+)
+
+// Threshold Paillier decryption as in Damgard, Jurik: A Generalisation, a Simplification
+// and Some Applications of Paillier's Probabilistic Public-Key System
+// (https://www.brics.dk/RS/00/45/BRICS-RS-00-45.pdf), which follows Shoup's threshold RSA.
+//
+// A dealer generates the key and splits the secret exponent among numberOfShares
+// trustees. Ciphertexts are ordinary Paillier ciphertexts with g = n + 1 (encrypt with
+// NewPubPaillier(pubKey.GetPaillierPubKey())). Each trustee computes a decryption share
+// with its key share, together with a proof that it used the share it was given, and
+// any threshold valid decryption shares are combined into the plaintext. No single
+// trustee (and no group of less than threshold trustees) can decrypt.
+
+// ThresholdPaillierPubKey is the public key of threshold Paillier.
+type ThresholdPaillierPubKey struct {
+	N         *big.Int
+	Threshold int // number of decryption shares needed for decryption
+	// V generates the subgroup of squares in Z_{n^2}*, and VerificationKeys[i-1] is
+	// v^(delta * s_i), where s_i is the secret share of trustee i and delta is
+	// numberOfShares!. They are used to verify decryption shares.
+	V                *big.Int
+	VerificationKeys []*big.Int
+}
+
+// ThresholdPaillierKeyShare is the key share of the trustee with the given index
+// (from 1 to the number of shares).
+type ThresholdPaillierKeyShare struct {
+	Index  int
+	S      *big.Int // the share of the secret exponent
+	PubKey *ThresholdPaillierPubKey
+}
+
+// PaillierDecryptionShare is a decryption share c^(2 * delta * s_i) of ciphertext c,
+// computed by the trustee with the given index, together with a non-interactive proof
+// that log_{c^4}(C^2) = log_v(v_i).
+type PaillierDecryptionShare struct {
+	Index int
+	C     *big.Int
+	A     *big.Int // c^(4 * r)
+	B     *big.Int // v^r
+	Z     *big.Int // r + e * delta * s_i
+}
+
+// thresholdPaillierProofName is hashed into challenges of proofs of decryption shares.
+var thresholdPaillierProofName = new(big.Int).SetBytes([]byte("paillier decryption share"))
+
+// NewThresholdPaillierKeys generates a threshold Paillier key with primes of length
+// primeLength, and splits it into numberOfShares key shares, any threshold of which can
+// decrypt. The primes are safe primes, as required for verification of decryption shares.
+// The key shares should be given to the trustees and then deleted.
+func NewThresholdPaillierKeys(primeLength, threshold, numberOfShares int) (*ThresholdPaillierPubKey,
+	[]*ThresholdPaillierKeyShare, error) {
+	p1 := common.GetGermainPrime(primeLength - 1)
+	q1 := common.GetGermainPrime(primeLength - 1)
+	for p1.Cmp(q1) == 0 {
+		q1 = common.GetGermainPrime(primeLength - 1)
+	}
+	p := new(big.Int).Add(p1, p1)
+	p.Add(p, big.NewInt(1))
+	q := new(big.Int).Add(q1, q1)
+	q.Add(q, big.NewInt(1))
+
+	n := new(big.Int).Mul(p, q)
+	n2 := new(big.Int).Mul(n, n)
+	m := new(big.Int).Mul(p1, q1)
+	nm := new(big.Int).Mul(n, m)
+
+	// d = 0 mod m, d = 1 mod n
+	d := new(big.Int).ModInverse(m, n)
+	d.Mul(d, m)
+
+	dealer, _ := secretsharing.NewDealer()
+	shares, err := dealer.SplitInt(d, nm, threshold, numberOfShares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// v is a random square, which with overwhelming probability generates the subgroup
+	// of squares (of order n * m)
+	v := common.GetRandomInt(n2)
+	v.Mul(v, v)
+	v.Mod(v, n2)
+
+	pubKey := &ThresholdPaillierPubKey{
+		N:                n,
+		Threshold:        threshold,
+		V:                v,
+		VerificationKeys: make([]*big.Int, numberOfShares),
+	}
+	delta := pubKey.delta()
+	keyShares := make([]*ThresholdPaillierKeyShare, numberOfShares)
+	for i, share := range shares {
+		exp := new(big.Int).Mul(delta, share.Value)
+		pubKey.VerificationKeys[i] = new(big.Int).Exp(v, exp, n2)
+		keyShares[i] = &ThresholdPaillierKeyShare{
+			Index:  share.Index,
+			S:      share.Value,
+			PubKey: pubKey,
+		}
+	}
+
+	return pubKey, keyShares, nil
+}
+
+// NewThresholdPaillierKeyShareFromFile returns the key share stored at path, JSON or PEM
+// encoded (see Store). If the key share is encrypted, the passphrase is obtained with
+// keystore.Passphrase.
+func NewThresholdPaillierKeyShareFromFile(path string) (*ThresholdPaillierKeyShare, error) {
+	bytes, err := keystore.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var share ThresholdPaillierKeyShare
+	if err := common.UnmarshalJSONOrPEM(bytes, &share); err != nil {
+		return nil, err
+	}
+	if share.PubKey == nil || share.Index < 1 || share.Index > share.PubKey.NumberOfShares() {
+		return nil, fmt.Errorf("Invalid threshold Paillier key share in %v", path)
+	}
+	return &share, nil
+}
+
+// Store writes the key share PEM encoded to the file at path, readable only by its
+// owner. If passphrase is not nil, the key share is encrypted with it.
+func (share *ThresholdPaillierKeyShare) Store(path string, passphrase []byte) error {
+	data, err := common.MarshalPEM(share)
+	if err != nil {
+		return err
+	}
+	return keystore.Store(data, path, passphrase)
+}
+
+// GetPaillierPubKey returns the Paillier public key (with g = n + 1) for encryption of
+// messages that are decrypted by the trustees.
+func (pubKey *ThresholdPaillierPubKey) GetPaillierPubKey() *PaillierPubKey {
+	return NewPaillierPubKey(pubKey.N, new(big.Int).Add(pubKey.N, big.NewInt(1)))
+}
+
+// NumberOfShares returns the number of key shares.
+func (pubKey *ThresholdPaillierPubKey) NumberOfShares() int {
+	return len(pubKey.VerificationKeys)
+}
+
+// Decrypt returns the decryption share of ciphertext c, together with the proof of
+// its correctness.
+func (share *ThresholdPaillierKeyShare) Decrypt(c *big.Int) (*PaillierDecryptionShare, error) {
+	pubKey := share.PubKey
+	n2 := new(big.Int).Mul(pubKey.N, pubKey.N)
+	if !pubKey.isCiphertext(c) {
+		return nil, errors.New("ciphertext is not from Z_{n^2}*")
+	}
+
+	// C = c^(2 * delta * s_i)
+	exp := new(big.Int).Mul(pubKey.delta(), share.S)
+	ci := new(big.Int).Exp(c, new(big.Int).Lsh(exp, 1), n2)
+
+	// prove that log_{c^4}(C^2) = log_v(v_i) = delta * s_i; r is chosen large enough to
+	// statistically hide e * delta * s_i
+	c4 := new(big.Int).Exp(c, big.NewInt(4), n2)
+	bits := n2.BitLen() + pubKey.delta().BitLen() + pubKey.challengeBits() + 128
+	r := common.GetRandomInt(new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	a := new(big.Int).Exp(c4, r, n2)
+	b := new(big.Int).Exp(pubKey.V, r, n2)
+
+	e := pubKey.getChallenge(share.Index, c, ci, a, b)
+	z := new(big.Int).Mul(e, exp)
+	z.Add(z, r)
+
+	return &PaillierDecryptionShare{
+		Index: share.Index,
+		C:     ci,
+		A:     a,
+		B:     b,
+		Z:     z,
+	}, nil
+}
+
+// VerifyDecryptionShare returns true if share is a correct decryption share of c.
+func (pubKey *ThresholdPaillierPubKey) VerifyDecryptionShare(c *big.Int,
+	share *PaillierDecryptionShare) bool {
+	if share == nil || share.Index < 1 || share.Index > pubKey.NumberOfShares() ||
+		!pubKey.isCiphertext(c) || !pubKey.isCiphertext(share.C) ||
+		!pubKey.isCiphertext(share.A) || !pubKey.isCiphertext(share.B) ||
+		share.Z == nil || share.Z.Sign() < 0 {
+		return false
+	}
+	n2 := new(big.Int).Mul(pubKey.N, pubKey.N)
+	e := pubKey.getChallenge(share.Index, c, share.C, share.A, share.B)
+
+	// check if (c^4)^z = a * (C^2)^e
+	c4 := new(big.Int).Exp(c, big.NewInt(4), n2)
+	left := new(big.Int).Exp(c4, share.Z, n2)
+	right := new(big.Int).Exp(share.C, new(big.Int).Lsh(e, 1), n2)
+	right.Mul(right, share.A)
+	right.Mod(right, n2)
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// check if v^z = b * v_i^e
+	left.Exp(pubKey.V, share.Z, n2)
+	right.Exp(pubKey.VerificationKeys[share.Index-1], e, n2)
+	right.Mul(right, share.B)
+	right.Mod(right, n2)
+	return left.Cmp(right) == 0
+}
+
+// Combine returns the plaintext of c, computed from its decryption shares. Shares that
+// don't verify are ignored, and an error is returned if there are less than threshold
+// valid shares from distinct trustees.
+func (pubKey *ThresholdPaillierPubKey) Combine(c *big.Int,
+	shares []*PaillierDecryptionShare) (*big.Int, error) {
+	var valid []*PaillierDecryptionShare
+	seen := make(map[int]bool)
+	for _, share := range shares {
+		if len(valid) == pubKey.Threshold {
+			break
+		}
+		if !pubKey.VerifyDecryptionShare(c, share) || seen[share.Index] {
+			continue
+		}
+		seen[share.Index] = true
+		valid = append(valid, share)
+	}
+	if len(valid) < pubKey.Threshold {
+		return nil, fmt.Errorf("%d valid decryption shares, %d needed", len(valid), pubKey.Threshold)
+	}
+
+	// c' = prod C_i^(2 * mu_i) = c^(4 * delta^2 * d) = (1 + n)^(4 * delta^2 * m) mod n^2
+	n2 := new(big.Int).Mul(pubKey.N, pubKey.N)
+	delta := pubKey.delta()
+	indices := make([]int, len(valid))
+	for i, share := range valid {
+		indices[i] = share.Index
+	}
+	cc := big.NewInt(1)
+	for _, share := range valid {
+		mu := lagrangeCoefficient(share.Index, indices, delta)
+		cc.Mul(cc, common.Exponentiate(share.C, mu.Lsh(mu, 1), n2))
+		cc.Mod(cc, n2)
+	}
+
+	// m = L(c') * (4 * delta^2)^-1 mod n
+	m := l(cc, pubKey.N)
+	inv := new(big.Int).Mul(delta, delta)
+	inv.Lsh(inv, 2)
+	inv.ModInverse(inv, pubKey.N)
+	m.Mul(m, inv)
+	return m.Mod(m, pubKey.N), nil
+}
+
+// delta returns numberOfShares!.
+func (pubKey *ThresholdPaillierPubKey) delta() *big.Int {
+	return new(big.Int).MulRange(1, int64(pubKey.NumberOfShares()))
+}
+
+// challengeBits returns the length of challenges, which are shorter than the prime
+// factors of the order of the group of squares, as required for soundness of proofs.
+func (pubKey *ThresholdPaillierPubKey) challengeBits() int {
+	return pubKey.N.BitLen()/2 - 2
+}
+
+// getChallenge returns the challenge for the proof of the decryption share of the
+// trustee with the given index.
+func (pubKey *ThresholdPaillierPubKey) getChallenge(index int, values ...*big.Int) *big.Int {
+	hashed := append([]*big.Int{thresholdPaillierProofName, pubKey.N, pubKey.V,
+		pubKey.VerificationKeys[index-1]}, values...)
+	b := new(big.Int).Lsh(big.NewInt(1), uint(pubKey.challengeBits()))
+	return new(big.Int).Mod(common.Hash(hashed...), b)
+}
+
+// isCiphertext returns true if c is from Z_{n^2}*.
+func (pubKey *ThresholdPaillierPubKey) isCiphertext(c *big.Int) bool {
+	n2 := new(big.Int).Mul(pubKey.N, pubKey.N)
+	return c != nil && c.Sign() > 0 && c.Cmp(n2) < 0 &&
+		new(big.Int).GCD(nil, nil, c, pubKey.N).Cmp(big.NewInt(1)) == 0
+}
+
+// lagrangeCoefficient returns the integer delta * lambda_{0,index}, where lambda_{0,index}
+// is the Lagrange coefficient for interpolation at 0 from the values at indices.
+func lagrangeCoefficient(index int, indices []int, delta *big.Int) *big.Int {
+	num := new(big.Int).Set(delta)
+	den := big.NewInt(1)
+	for _, j := range indices {
+		if j == index {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(j)))
+		den.Mul(den, big.NewInt(int64(j-index)))
+	}
+	return num.Quo(num, den)
+}
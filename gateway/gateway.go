@@ -0,0 +1,306 @@
+// Package gateway exposes emmy protocols over HTTP/JSON for clients that cannot use
+// a bidirectional gRPC stream (for example browsers).
+//
+// Each protocol execution is a session kept on the gateway. A client starts a session
+// by POSTing the first protocol message to /sessions and continues by POSTing the
+// following messages to /sessions/{id}. Messages are the ones from msgs.proto,
+// encoded as JSON. Every request is answered with the next message of emmy server.
+// The session ends when the server sends the final status message, or is aborted
+// with DELETE /sessions/{id}.
+package gateway
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/xlab-si/emmy/log"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/server"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var logger = log.ServerLogger
+
+// DefaultSessionTimeout is the time after which an idle session is discarded.
+const DefaultSessionTimeout = 2 * time.Minute
+
+// maxMessageSize limits the size of a JSON message accepted by the gateway.
+const maxMessageSize = 1 << 20
+
+// Gateway is a http.Handler that translates HTTP requests into steps of protocols
+// run by emmy server.
+type Gateway struct {
+	server *server.Server
+	// SessionTimeout specifies how long a session may stay idle before it is discarded.
+	SessionTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// session holds the state of a single protocol execution.
+type session struct {
+	sync.Mutex // serializes protocol steps of the session
+	id         string
+	tenant     *server.Tenant
+	transport  transport.Transport // gateway's end of the pipe to the protocol handler
+	ctx        context.Context     // done when the session is closed
+	cancel     context.CancelFunc
+	timer      *time.Timer
+	done       chan struct{}
+	err        error // result of server's Run, valid after done is closed
+}
+
+// response is the JSON body returned for each protocol step.
+type response struct {
+	SessionId string          `json:"sessionId"`
+	Message   json.RawMessage `json:"message"`
+	Finished  bool            `json:"finished"`
+}
+
+// NewGateway returns a Gateway that runs protocols with the given emmy server.
+func NewGateway(s *server.Server) *Gateway {
+	return &Gateway{
+		server:         s,
+		SessionTimeout: DefaultSessionTimeout,
+		sessions:       make(map[string]*session),
+	}
+}
+
+// ServeHTTP dispatches HTTP requests to session handling methods.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/sessions"), "/")
+	if !strings.HasPrefix(r.URL.Path, "/sessions") || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		g.startSession(w, r)
+	case id != "" && r.Method == http.MethodPost:
+		g.continueSession(w, r, id)
+	case id != "" && r.Method == http.MethodDelete:
+		g.abortSession(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// startSession creates a new session and passes the first message of the client to
+// emmy server.
+func (g *Gateway) startSession(w http.ResponseWriter, r *http.Request) {
+	tenant, err := g.server.AuthenticateHTTP(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	req, err := readMessage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s, err := g.newSession(tenant)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	logger.Infof("Gateway started session %v for tenant %q", s.id, tenant.Id)
+
+	g.step(w, s, req)
+}
+
+// continueSession passes the next message of the client to emmy server.
+func (g *Gateway) continueSession(w http.ResponseWriter, r *http.Request, id string) {
+	s := g.getTenantSession(r, id)
+	if s == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown session: %v", id))
+		return
+	}
+
+	req, err := readMessage(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	g.step(w, s, req)
+}
+
+// abortSession discards the session before the protocol is finished.
+func (g *Gateway) abortSession(w http.ResponseWriter, r *http.Request, id string) {
+	s := g.getTenantSession(r, id)
+	if s == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown session: %v", id))
+		return
+	}
+	g.closeSession(s)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// step delivers req to the protocol handler of session s and writes the handler's
+// reply to w.
+func (g *Gateway) step(w http.ResponseWriter, s *session, req *pb.Message) {
+	s.Lock()
+	defer s.Unlock()
+	s.timer.Reset(g.SessionTimeout)
+
+	if err := s.transport.Send(s.ctx, req); err != nil {
+		g.writeSessionError(w, s)
+		return
+	}
+
+	resp, err := s.transport.Receive(s.ctx)
+	if err != nil {
+		g.writeSessionError(w, s)
+		return
+	}
+
+	_, finished := resp.Content.(*pb.Message_Status)
+	if finished {
+		g.closeSession(s)
+	}
+	g.writeResponse(w, s.id, resp, finished)
+}
+
+// newSession registers a new session of the given tenant and starts emmy server's
+// protocol handler for it.
+func (g *Gateway) newSession(tenant *server.Tenant) (*session, error) {
+	id, err := newSessionId()
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayEnd, serverEnd := transport.NewPipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &session{
+		id:        id,
+		tenant:    tenant,
+		transport: gatewayEnd,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	s.timer = time.AfterFunc(g.SessionTimeout, func() {
+		logger.Noticef("Gateway session %v timed out", s.id)
+		g.closeSession(s)
+	})
+
+	g.mu.Lock()
+	g.sessions[id] = s
+	g.mu.Unlock()
+
+	go g.run(s, serverEnd)
+	return s, nil
+}
+
+// run executes the protocol handler for session s over transport t until it finishes.
+func (g *Gateway) run(s *session, t transport.Transport) {
+	defer close(s.done)
+	defer t.Close()
+	defer func() {
+		// malformed messages may cause the handler to panic, which must not bring
+		// down the whole gateway
+		if r := recover(); r != nil {
+			s.err = fmt.Errorf("Protocol handler failed: %v", r)
+			g.closeSession(s)
+		}
+	}()
+
+	s.err = g.server.ServeTenant(t, s.tenant)
+	if s.err != nil {
+		g.closeSession(s)
+	}
+}
+
+// getTenantSession returns the session with the given ID, provided that it belongs to
+// the tenant that the client sending request r authenticates as.
+func (g *Gateway) getTenantSession(r *http.Request, id string) *session {
+	s := g.getSession(id)
+	if s == nil {
+		return nil
+	}
+	if tenant, err := g.server.AuthenticateHTTP(r); err != nil || tenant.Id != s.tenant.Id {
+		return nil
+	}
+	return s
+}
+
+func (g *Gateway) getSession(id string) *session {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.sessions[id]
+}
+
+// closeSession removes the session and stops its protocol handler.
+func (g *Gateway) closeSession(s *session) {
+	g.mu.Lock()
+	delete(g.sessions, s.id)
+	g.mu.Unlock()
+
+	s.timer.Stop()
+	s.cancel()
+	s.transport.Close()
+}
+
+func (g *Gateway) writeSessionError(w http.ResponseWriter, s *session) {
+	// the protocol handler stops once the session is closed, report its error
+	g.closeSession(s)
+	<-s.done
+
+	err := s.err
+	if err == nil {
+		err = fmt.Errorf("Session %v is closed", s.id)
+	}
+	logger.Noticef("Gateway session %v failed: %v", s.id, err)
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+func (g *Gateway) writeResponse(w http.ResponseWriter, id string, msg *pb.Message, finished bool) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{}).Marshal(&buf, msg); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response{
+		SessionId: id,
+		Message:   buf.Bytes(),
+		Finished:  finished,
+	})
+}
+
+// readMessage decodes a JSON encoded pb.Message from the request body.
+func readMessage(w http.ResponseWriter, r *http.Request) (*pb.Message, error) {
+	msg := &pb.Message{}
+	body := http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := jsonpb.Unmarshal(body, msg); err != nil {
+		return nil, fmt.Errorf("Invalid message: %v", err)
+	}
+	return msg, nil
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+func newSessionId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Error generating session id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
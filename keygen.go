@@ -0,0 +1,341 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/urfave/cli"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/encryption"
+	"github.com/xlab-si/emmy/keystore"
+	"github.com/xlab-si/emmy/pseudonymsys"
+	"github.com/xlab-si/emmy/signatures"
+	"math/big"
+	"path/filepath"
+)
+
+// keyFileFlags are the flags for paths of the files where generated public and
+// secret keys are written. If not given, the files are written to the key folder.
+// Secret keys are encrypted with a passphrase if requested.
+var keyFileFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "pub",
+		Usage: "Path to the file where public key is written",
+	},
+	cli.StringFlag{
+		Name:  "sec",
+		Usage: "Path to the file where secret key is written",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "Format of key files: protobuf (CSPaillier, Paillier and CL keys) or yml (other keys), json or pem",
+	},
+	cli.BoolFlag{
+		Name:  "encrypt",
+		Usage: "Encrypt secret key with a passphrase (taken from " + keystore.PassphraseEnv + " or prompted for)",
+	},
+}
+
+// keygenCommand returns the command for generating keys and group parameters
+// for all protocols supported by emmy.
+func keygenCommand() cli.Command {
+	return cli.Command{
+		Name:  "keygen",
+		Usage: "Generates keys and group parameters",
+		Subcommands: []cli.Command{
+			{
+				Name:  "cspaillier",
+				Usage: "Generates Camenisch-Shoup Paillier key pair for verifiable encryption",
+				Flags: append([]cli.Flag{
+					cli.IntFlag{Name: "l", Value: 512, Usage: "Bit length of safe primes p', q'"},
+					cli.IntFlag{Name: "rolength", Value: 160, Usage: "Bit length of the order of discrete log group"},
+					cli.IntFlag{Name: "k", Value: 158, Usage: "Bit length of challenges"},
+					cli.IntFlag{Name: "k1", Value: 158, Usage: "Security parameter k' of proofs"},
+					cli.StringFlag{Name: "id", Usage: "ID of the key, used by emmy server to tell apart several active keys"},
+				}, keyFileFlags...),
+				Action: keygenCSPaillier,
+			},
+			{
+				Name:  "paillier",
+				Usage: "Generates Paillier key pair",
+				Flags: append([]cli.Flag{
+					cli.IntFlag{Name: "bits", Value: 1024, Usage: "Bit length of primes p, q"},
+				}, keyFileFlags...),
+				Action: keygenPaillier,
+			},
+			{
+				Name:  "cl",
+				Usage: "Generates Camenisch-Lysyanskaya signature key pair",
+				Flags: append([]cli.Flag{
+					cli.IntFlag{Name: "blocks", Value: 1, Usage: "Number of message blocks to be signed"},
+				}, keyFileFlags...),
+				Action: keygenCL,
+			},
+			{
+				Name:  "org",
+				Usage: "Generates keys (s1, s2, h1, h2) of pseudonymsys organization in the configured pseudonymsys group",
+				Flags: append([]cli.Flag{
+					cli.StringFlag{Name: "name", Value: "org1", Usage: "Name of the organization"},
+				}, keyFileFlags...),
+				Action: keygenOrg,
+			},
+			{
+				Name:  "ca",
+				Usage: "Generates ECDSA key (D, X, Y) of pseudonymsys CA",
+				Flags: append([]cli.Flag{
+					cli.StringFlag{Name: "name", Value: "ca", Usage: "Name of the CA"},
+				}, keyFileFlags...),
+				Action: keygenCA,
+			},
+			{
+				Name:  "schnorr",
+				Usage: "Generates parameters (p, g, q) of a Schnorr group",
+				Flags: []cli.Flag{
+					cli.StringFlag{Name: "name", Value: "schnorr", Usage: "Name of the config section"},
+					cli.IntFlag{Name: "qbits", Value: 256, Usage: "Bit length of subgroup order q (160, 224 or 256)"},
+					cli.StringFlag{Name: "out", Usage: "Path to the file where parameters are written"},
+					cli.StringFlag{Name: "format", Usage: "Format of the file: yml, json or pem"},
+				},
+				Action: keygenSchnorr,
+			},
+		},
+	}
+}
+
+func keygenCSPaillier(ctx *cli.Context) error {
+	if err := checkFormat(ctx, "protobuf"); err != nil {
+		return err
+	}
+	passphrase, err := secretKeyPassphrase(ctx)
+	if err != nil {
+		return err
+	}
+	secParams := encryption.CSPaillierSecParams{
+		L:        ctx.Int("l"),
+		RoLength: ctx.Int("rolength"),
+		K:        ctx.Int("k"),
+		K1:       ctx.Int("k1"),
+	}
+	cspaillier := encryption.NewCSPaillier(&secParams)
+
+	pubName, secName := "cspaillierpubkey", "cspaillierseckey"
+	if keyId := ctx.String("id"); keyId != "" {
+		pubName += "-" + keyId
+		secName += "-" + keyId
+	}
+	return storeKeys(ctx, pubName, secName, passphrase,
+		cspaillier.PubKey, cspaillier.SecretKey,
+		cspaillier.StorePubKey, cspaillier.StoreEncryptedSecKey)
+}
+
+func keygenPaillier(ctx *cli.Context) error {
+	if err := checkFormat(ctx, "protobuf"); err != nil {
+		return err
+	}
+	passphrase, err := secretKeyPassphrase(ctx)
+	if err != nil {
+		return err
+	}
+	paillier := encryption.NewPaillier(ctx.Int("bits"))
+
+	return storeKeys(ctx, "paillierpubkey", "paillierseckey", passphrase,
+		paillier.GetPubKey(), paillier.GetSecretKey(),
+		paillier.StorePubKey, paillier.StoreEncryptedSecKey)
+}
+
+func keygenCL(ctx *cli.Context) error {
+	if err := checkFormat(ctx, "protobuf"); err != nil {
+		return err
+	}
+	passphrase, err := secretKeyPassphrase(ctx)
+	if err != nil {
+		return err
+	}
+	cl := signatures.NewCL(ctx.Int("blocks"))
+
+	return storeKeys(ctx, "clpubkey", "clseckey", passphrase,
+		cl.GetPubKey(), cl.GetSecretKey(),
+		cl.StorePubKey, cl.StoreEncryptedSecKey)
+}
+
+// keygenOrg writes the keys of pseudonymsys organization, by default as config snippets,
+// so that the public keys can be distributed to users and the secret keys to the organization.
+func keygenOrg(ctx *cli.Context) error {
+	if err := checkFormat(ctx, "yml"); err != nil {
+		return err
+	}
+	group, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		return err
+	}
+	passphrase, err := secretKeyPassphrase(ctx)
+	if err != nil {
+		return err
+	}
+	secKeys, pubKeys, err := pseudonymsys.GenerateOrgKeys(group)
+	if err != nil {
+		return fmt.Errorf("Cannot generate organization keys: %v", err)
+	}
+
+	name := ctx.String("name")
+	storePub := func(path string) error {
+		values := map[string]*big.Int{"h1": pubKeys.H1, "h2": pubKeys.H2}
+		return config.StoreValues(path, values, "pseudonymsys", name)
+	}
+	storeSec := func(path string, passphrase []byte) error {
+		values := map[string]*big.Int{"s1": secKeys.S1, "s2": secKeys.S2}
+		return config.StoreSecretValues(path, passphrase, values, "pseudonymsys", name)
+	}
+	return storeKeys(ctx, name+"pubkey", name+"seckey", passphrase,
+		pubKeys, secKeys, storePub, storeSec)
+}
+
+// keygenCA writes the keys of pseudonymsys CA, by default as config snippets.
+func keygenCA(ctx *cli.Context) error {
+	if err := checkFormat(ctx, "yml"); err != nil {
+		return err
+	}
+	passphrase, err := secretKeyPassphrase(ctx)
+	if err != nil {
+		return err
+	}
+	key, err := pseudonymsys.GenerateCAKey()
+	if err != nil {
+		return fmt.Errorf("Cannot generate CA key: %v", err)
+	}
+
+	name := ctx.String("name")
+	storePub := func(path string) error {
+		values := map[string]*big.Int{"X": key.X, "Y": key.Y}
+		return config.StoreValues(path, values, "pseudonymsys", name)
+	}
+	storeSec := func(path string, passphrase []byte) error {
+		values := map[string]*big.Int{"D": key.D}
+		return config.StoreSecretValues(path, passphrase, values, "pseudonymsys", name)
+	}
+	return storeKeys(ctx, name+"pubkey", name+"seckey", passphrase,
+		&pseudonymsys.CAPubKey{X: key.X, Y: key.Y}, &pseudonymsys.CASecretKey{D: key.D},
+		storePub, storeSec)
+}
+
+// keygenSchnorr writes parameters of a fresh Schnorr group, by default as a config snippet.
+func keygenSchnorr(ctx *cli.Context) error {
+	if err := checkFormat(ctx, "yml"); err != nil {
+		return err
+	}
+	group, err := dlog.NewZpSchnorr(ctx.Int("qbits"))
+	if err != nil {
+		return fmt.Errorf("Cannot generate Schnorr group: %v", err)
+	}
+
+	name := ctx.String("name")
+	format := ctx.String("format")
+	path := ctx.String("out")
+	if path == "" {
+		path = filepath.Join(config.LoadKeyDirFromConfig(), name+"."+format)
+	}
+	if format == "yml" {
+		values := map[string]*big.Int{"p": group.P, "g": group.G, "q": group.OrderOfSubgroup}
+		err = config.StoreValues(path, values, name)
+	} else {
+		err = storeEncoded(group, format, path, false, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Group parameters written to %v\n", path)
+	return nil
+}
+
+// storeKeys writes the public and the secret key to the files given by flags or, if not
+// given, to the files named pubName and secName in the key folder. Keys are written either
+// in their native format with storePub and storeSec, or JSON or PEM encoded.
+func storeKeys(ctx *cli.Context, pubName, secName string, passphrase []byte,
+	pubKey, secKey json.Marshaler,
+	storePub func(string) error, storeSec func(string, []byte) error) error {
+	format := ctx.String("format")
+	ext := format
+	if format == "protobuf" {
+		ext = "txt"
+	}
+	pubPath, secPath := keyFilePaths(ctx, pubName+"."+ext, secName+"."+ext)
+
+	var pubErr, secErr error
+	if format == "json" || format == "pem" {
+		pubErr = storeEncoded(pubKey, format, pubPath, false, nil)
+		secErr = storeEncoded(secKey, format, secPath, true, passphrase)
+	} else {
+		pubErr = storePub(pubPath)
+		secErr = storeSec(secPath, passphrase)
+	}
+	if pubErr != nil {
+		return fmt.Errorf("Cannot write public key: %v", pubErr)
+	}
+	if secErr != nil {
+		return fmt.Errorf("Cannot write secret key: %v", secErr)
+	}
+
+	fmt.Printf("Public key written to %v\n", pubPath)
+	fmt.Printf("Secret key written to %v\n", secPath)
+	return nil
+}
+
+// storeEncoded writes the JSON or PEM encoding of v to the file at path. Secrets are
+// written to files readable only by their owner and, if passphrase is given, encrypted.
+func storeEncoded(v json.Marshaler, format, path string, secret bool, passphrase []byte) error {
+	var data []byte
+	var err error
+	if format == "pem" {
+		data, err = common.MarshalPEM(v)
+	} else {
+		data, err = v.MarshalJSON()
+	}
+	if err != nil {
+		return err
+	}
+
+	if secret {
+		return keystore.Store(data, path, passphrase)
+	}
+	return common.Store(data, path)
+}
+
+// checkFormat returns an error if the format flag is neither the native format of
+// the keys being generated nor json or pem.
+func checkFormat(ctx *cli.Context, native string) error {
+	if !ctx.IsSet("format") {
+		return ctx.Set("format", native)
+	}
+	switch format := ctx.String("format"); format {
+	case native, "json", "pem":
+		return nil
+	default:
+		return fmt.Errorf("Unsupported format %q, use %v, json or pem", format, native)
+	}
+}
+
+// keyFilePaths returns the paths of public and secret key files as given by flags
+// or, if not given, the default files in the key folder.
+func keyFilePaths(ctx *cli.Context, defaultPub, defaultSec string) (string, string) {
+	keyDir := config.LoadKeyDirFromConfig()
+	pubPath := ctx.String("pub")
+	if pubPath == "" {
+		pubPath = filepath.Join(keyDir, defaultPub)
+	}
+	secPath := ctx.String("sec")
+	if secPath == "" {
+		secPath = filepath.Join(keyDir, defaultSec)
+	}
+	return pubPath, secPath
+}
+
+// secretKeyPassphrase returns the passphrase for encrypting secret keys if requested
+// with the encrypt flag, or nil otherwise.
+func secretKeyPassphrase(ctx *cli.Context) ([]byte, error) {
+	if !ctx.Bool("encrypt") {
+		return nil, nil
+	}
+	return keystore.NewPassphrase()
+}
@@ -0,0 +1,228 @@
+// Package keystore stores secret key material at rest encrypted with a passphrase.
+//
+// An encrypted file is a JSON document holding the version of the format, the
+// parameters of the key derivation function (scrypt) and the secret encrypted with
+// AES-256-GCM under the key derived from the passphrase. Files are always written
+// atomically and readable only by their owner.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/scrypt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// version is the version of the format of encrypted files.
+const version = 1
+
+const (
+	kdfName    = "scrypt"
+	cipherName = "aes-256-gcm"
+	keyLength  = 32
+	saltLength = 32
+)
+
+// Parameters of scrypt, as recommended for interactive logins in 2017.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Bounds on scrypt parameters of files being decrypted, so that a crafted file cannot
+// make decryption use excessive memory (128 * N * R bytes) or time.
+const (
+	maxScryptN      = 1 << 20
+	maxScryptR      = 32
+	maxScryptP      = 16
+	maxScryptMemory = 1 << 30
+)
+
+// FileMode is the permission of the files written by Store.
+const FileMode = 0600
+
+// ErrWrongPassphrase is returned when decryption fails, either because the
+// passphrase is wrong or because the encrypted data was modified.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+type kdfParams struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+type encrypted struct {
+	Version    int       `json:"version"`
+	KDF        kdfParams `json:"kdf"`
+	Cipher     string    `json:"cipher"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// Encrypt encrypts data with a key derived from passphrase and returns the
+// encoded result, which can be decrypted with Decrypt.
+func Encrypt(data, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	kdf := kdfParams{
+		Name: kdfName,
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+		Salt: salt,
+	}
+
+	aead, err := newAEAD(passphrase, &kdf)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(&encrypted{
+		Version:    version,
+		KDF:        kdf,
+		Cipher:     cipherName,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, data, nil),
+	}, "", "  ")
+}
+
+// Decrypt decrypts data encrypted with Encrypt. It returns ErrWrongPassphrase if
+// the passphrase is wrong.
+func Decrypt(data, passphrase []byte) ([]byte, error) {
+	var e encrypted
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("Invalid encrypted data: %v", err)
+	}
+	if e.Version != version {
+		return nil, fmt.Errorf("Unsupported version of encrypted data: %d", e.Version)
+	}
+	if e.Cipher != cipherName {
+		return nil, fmt.Errorf("Unsupported cipher: %v", e.Cipher)
+	}
+
+	aead, err := newAEAD(passphrase, &e.KDF)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("Invalid nonce length: %d", len(e.Nonce))
+	}
+	plaintext, err := aead.Open(nil, e.Nonce, e.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted returns true if data was produced by Encrypt.
+func IsEncrypted(data []byte) bool {
+	var e encrypted
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	return e.Version > 0 && e.Cipher != "" && e.KDF.Name != ""
+}
+
+// Store writes data to the file at path. If passphrase is not empty, data is
+// encrypted with it first. The file is written atomically, with FileMode permissions.
+func Store(data []byte, path string, passphrase []byte) error {
+	if len(passphrase) > 0 {
+		var err error
+		if data, err = Encrypt(data, passphrase); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Load reads the file at path. If the file is encrypted, it is decrypted with the
+// passphrase obtained by Passphrase.
+func Load(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	passphrase, err := Passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot decrypt %v: %v", path, err)
+	}
+	plaintext, err := Decrypt(data, passphrase)
+	if err == ErrWrongPassphrase {
+		forgetPassphrase()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Cannot decrypt %v: %v", path, err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(passphrase []byte, kdf *kdfParams) (cipher.AEAD, error) {
+	if kdf.Name != kdfName {
+		return nil, fmt.Errorf("Unsupported key derivation function: %v", kdf.Name)
+	}
+	if kdf.N < 2 || kdf.N > maxScryptN || kdf.N&(kdf.N-1) != 0 ||
+		kdf.R < 1 || kdf.R > maxScryptR || kdf.P < 1 || kdf.P > maxScryptP ||
+		128*kdf.N*kdf.R > maxScryptMemory {
+		return nil, fmt.Errorf("Unsupported scrypt parameters N=%d, r=%d, p=%d", kdf.N, kdf.R, kdf.P)
+	}
+	key, err := scrypt.Key(passphrase, kdf.Salt, kdf.N, kdf.R, kdf.P, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("Error deriving key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic writes data to a temporary file in the same folder as path and
+// renames it to path, so that path never holds partially written data.
+func writeFileAtomic(path string, data []byte) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := ioutil.TempFile(dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath) // no-op after successful rename
+
+	if err := f.Chmod(FileMode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
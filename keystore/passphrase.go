@@ -0,0 +1,92 @@
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/term"
+	"os"
+	"sync"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase for encrypted keys.
+const PassphraseEnv = "EMMY_PASSPHRASE"
+
+var (
+	mu     sync.Mutex
+	cached []byte // passphrase entered by the user, so that it is asked for only once
+)
+
+// Passphrase returns the passphrase for decrypting keys. It is taken from the
+// EMMY_PASSPHRASE environment variable or, if that is not set, the user is prompted
+// for it on the terminal.
+func Passphrase() ([]byte, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return []byte(passphrase), nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	passphrase, err := prompt("Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	cached = passphrase
+	return passphrase, nil
+}
+
+// NewPassphrase returns the passphrase for encrypting new keys. It is taken from
+// the EMMY_PASSPHRASE environment variable or, if that is not set, the user is
+// prompted for it twice on the terminal.
+func NewPassphrase() ([]byte, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		if passphrase == "" {
+			return nil, fmt.Errorf("%v is empty", PassphraseEnv)
+		}
+		return []byte(passphrase), nil
+	}
+
+	passphrase, err := prompt("New passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("Passphrase must not be empty")
+	}
+	repeated, err := prompt("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, repeated) {
+		return nil, errors.New("Passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// forgetPassphrase drops the passphrase entered by the user, so that the user is
+// asked again the next time.
+func forgetPassphrase() {
+	mu.Lock()
+	cached = nil
+	mu.Unlock()
+}
+
+// prompt reads a passphrase from the terminal without echoing it.
+func prompt(msg string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("No passphrase: %v is not set and stdin is not a terminal",
+			PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, msg)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading passphrase: %v", err)
+	}
+	return passphrase, nil
+}
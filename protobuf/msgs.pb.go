@@ -21,8 +21,18 @@ It has these top-level messages:
 	SchnorrProofData
 	CSPaillierSecretKey
 	CSPaillierPubKey
+	PaillierPubKey
+	PaillierSecretKey
+	CLPubKey
+	CLSecretKey
 	CSPaillierOpening
 	CSPaillierProofRandomData
+	CSPaillierDecryptionRequest
+	CSPaillierDecryption
+	PaillierDecryptionShareRequest
+	PaillierDecryptionShare
+	ElGamalDecryptionShareRequest
+	ElGamalDecryptionShare
 	CSPaillierProofData
 */
 package protobuf
@@ -51,11 +61,14 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type SchemaType int32
 
 const (
-	SchemaType_PEDERSEN    SchemaType = 0
-	SchemaType_PEDERSEN_EC SchemaType = 1
-	SchemaType_SCHNORR     SchemaType = 2
-	SchemaType_SCHNORR_EC  SchemaType = 3
-	SchemaType_CSPAILLIER  SchemaType = 4
+	SchemaType_PEDERSEN                  SchemaType = 0
+	SchemaType_PEDERSEN_EC               SchemaType = 1
+	SchemaType_SCHNORR                   SchemaType = 2
+	SchemaType_SCHNORR_EC                SchemaType = 3
+	SchemaType_CSPAILLIER                SchemaType = 4
+	SchemaType_CSPAILLIER_DECRYPTION     SchemaType = 5
+	SchemaType_PAILLIER_DECRYPTION_SHARE SchemaType = 6
+	SchemaType_ELGAMAL_DECRYPTION_SHARE  SchemaType = 7
 )
 
 var SchemaType_name = map[int32]string{
@@ -64,13 +77,19 @@ var SchemaType_name = map[int32]string{
 	2: "SCHNORR",
 	3: "SCHNORR_EC",
 	4: "CSPAILLIER",
+	5: "CSPAILLIER_DECRYPTION",
+	6: "PAILLIER_DECRYPTION_SHARE",
+	7: "ELGAMAL_DECRYPTION_SHARE",
 }
 var SchemaType_value = map[string]int32{
-	"PEDERSEN":    0,
-	"PEDERSEN_EC": 1,
-	"SCHNORR":     2,
-	"SCHNORR_EC":  3,
-	"CSPAILLIER":  4,
+	"PEDERSEN":                  0,
+	"PEDERSEN_EC":               1,
+	"SCHNORR":                   2,
+	"SCHNORR_EC":                3,
+	"CSPAILLIER":                4,
+	"CSPAILLIER_DECRYPTION":     5,
+	"PAILLIER_DECRYPTION_SHARE": 6,
+	"ELGAMAL_DECRYPTION_SHARE":  7,
 }
 
 func (x SchemaType) String() string {
@@ -120,8 +139,17 @@ type Message struct {
 	//	*Message_CsPaillierOpening
 	//	*Message_CsPaillierProofData
 	//	*Message_CsPaillierProofRandomData
+	//	*Message_CsPaillierPubKey
+	//	*Message_CsPaillierDecryptionRequest
+	//	*Message_CsPaillierDecryption
+	//	*Message_PaillierDecryptionShareRequest
+	//	*Message_PaillierDecryptionShare
+	//	*Message_ElGamalDecryptionShareRequest
+	//	*Message_ElGamalDecryptionShare
 	Content  isMessage_Content `protobuf_oneof:"content"`
 	ClientId int32             `protobuf:"varint,15,opt,name=clientId" json:"clientId,omitempty"`
+	// ID of the server's key used in the session, empty for the default key
+	KeyId string `protobuf:"bytes,16,opt,name=key_id,json=keyId" json:"key_id,omitempty"`
 }
 
 func (m *Message) Reset()                    { *m = Message{} }
@@ -169,19 +197,47 @@ type Message_CsPaillierProofData struct {
 type Message_CsPaillierProofRandomData struct {
 	CsPaillierProofRandomData *CSPaillierProofRandomData `protobuf:"bytes,14,opt,name=cs_paillier_proof_random_data,json=csPaillierProofRandomData,oneof"`
 }
+type Message_CsPaillierPubKey struct {
+	CsPaillierPubKey *CSPaillierPubKey `protobuf:"bytes,17,opt,name=cs_paillier_pub_key,json=csPaillierPubKey,oneof"`
+}
+type Message_CsPaillierDecryptionRequest struct {
+	CsPaillierDecryptionRequest *CSPaillierDecryptionRequest `protobuf:"bytes,18,opt,name=cs_paillier_decryption_request,json=csPaillierDecryptionRequest,oneof"`
+}
+type Message_CsPaillierDecryption struct {
+	CsPaillierDecryption *CSPaillierDecryption `protobuf:"bytes,19,opt,name=cs_paillier_decryption,json=csPaillierDecryption,oneof"`
+}
+type Message_PaillierDecryptionShareRequest struct {
+	PaillierDecryptionShareRequest *PaillierDecryptionShareRequest `protobuf:"bytes,20,opt,name=paillier_decryption_share_request,json=paillierDecryptionShareRequest,oneof"`
+}
+type Message_PaillierDecryptionShare struct {
+	PaillierDecryptionShare *PaillierDecryptionShare `protobuf:"bytes,21,opt,name=paillier_decryption_share,json=paillierDecryptionShare,oneof"`
+}
+type Message_ElGamalDecryptionShareRequest struct {
+	ElGamalDecryptionShareRequest *ElGamalDecryptionShareRequest `protobuf:"bytes,22,opt,name=el_gamal_decryption_share_request,json=elGamalDecryptionShareRequest,oneof"`
+}
+type Message_ElGamalDecryptionShare struct {
+	ElGamalDecryptionShare *ElGamalDecryptionShare `protobuf:"bytes,23,opt,name=el_gamal_decryption_share,json=elGamalDecryptionShare,oneof"`
+}
 
-func (*Message_Empty) isMessage_Content()                     {}
-func (*Message_Bigint) isMessage_Content()                    {}
-func (*Message_EcGroupElement) isMessage_Content()            {}
-func (*Message_Status) isMessage_Content()                    {}
-func (*Message_PedersenFirst) isMessage_Content()             {}
-func (*Message_PedersenDecommitment) isMessage_Content()      {}
-func (*Message_SchnorrProofData) isMessage_Content()          {}
-func (*Message_SchnorrProofRandomData) isMessage_Content()    {}
-func (*Message_SchnorrEcProofRandomData) isMessage_Content()  {}
-func (*Message_CsPaillierOpening) isMessage_Content()         {}
-func (*Message_CsPaillierProofData) isMessage_Content()       {}
-func (*Message_CsPaillierProofRandomData) isMessage_Content() {}
+func (*Message_Empty) isMessage_Content()                          {}
+func (*Message_Bigint) isMessage_Content()                         {}
+func (*Message_EcGroupElement) isMessage_Content()                 {}
+func (*Message_Status) isMessage_Content()                         {}
+func (*Message_PedersenFirst) isMessage_Content()                  {}
+func (*Message_PedersenDecommitment) isMessage_Content()           {}
+func (*Message_SchnorrProofData) isMessage_Content()               {}
+func (*Message_SchnorrProofRandomData) isMessage_Content()         {}
+func (*Message_SchnorrEcProofRandomData) isMessage_Content()       {}
+func (*Message_CsPaillierOpening) isMessage_Content()              {}
+func (*Message_CsPaillierProofData) isMessage_Content()            {}
+func (*Message_CsPaillierProofRandomData) isMessage_Content()      {}
+func (*Message_CsPaillierPubKey) isMessage_Content()               {}
+func (*Message_CsPaillierDecryptionRequest) isMessage_Content()    {}
+func (*Message_CsPaillierDecryption) isMessage_Content()           {}
+func (*Message_PaillierDecryptionShareRequest) isMessage_Content() {}
+func (*Message_PaillierDecryptionShare) isMessage_Content()        {}
+func (*Message_ElGamalDecryptionShareRequest) isMessage_Content()  {}
+func (*Message_ElGamalDecryptionShare) isMessage_Content()         {}
 
 func (m *Message) GetContent() isMessage_Content {
 	if m != nil {
@@ -288,6 +344,55 @@ func (m *Message) GetCsPaillierProofRandomData() *CSPaillierProofRandomData {
 	return nil
 }
 
+func (m *Message) GetCsPaillierPubKey() *CSPaillierPubKey {
+	if x, ok := m.GetContent().(*Message_CsPaillierPubKey); ok {
+		return x.CsPaillierPubKey
+	}
+	return nil
+}
+
+func (m *Message) GetCsPaillierDecryptionRequest() *CSPaillierDecryptionRequest {
+	if x, ok := m.GetContent().(*Message_CsPaillierDecryptionRequest); ok {
+		return x.CsPaillierDecryptionRequest
+	}
+	return nil
+}
+
+func (m *Message) GetCsPaillierDecryption() *CSPaillierDecryption {
+	if x, ok := m.GetContent().(*Message_CsPaillierDecryption); ok {
+		return x.CsPaillierDecryption
+	}
+	return nil
+}
+
+func (m *Message) GetPaillierDecryptionShareRequest() *PaillierDecryptionShareRequest {
+	if x, ok := m.GetContent().(*Message_PaillierDecryptionShareRequest); ok {
+		return x.PaillierDecryptionShareRequest
+	}
+	return nil
+}
+
+func (m *Message) GetPaillierDecryptionShare() *PaillierDecryptionShare {
+	if x, ok := m.GetContent().(*Message_PaillierDecryptionShare); ok {
+		return x.PaillierDecryptionShare
+	}
+	return nil
+}
+
+func (m *Message) GetElGamalDecryptionShareRequest() *ElGamalDecryptionShareRequest {
+	if x, ok := m.GetContent().(*Message_ElGamalDecryptionShareRequest); ok {
+		return x.ElGamalDecryptionShareRequest
+	}
+	return nil
+}
+
+func (m *Message) GetElGamalDecryptionShare() *ElGamalDecryptionShare {
+	if x, ok := m.GetContent().(*Message_ElGamalDecryptionShare); ok {
+		return x.ElGamalDecryptionShare
+	}
+	return nil
+}
+
 func (m *Message) GetClientId() int32 {
 	if m != nil {
 		return m.ClientId
@@ -295,6 +400,13 @@ func (m *Message) GetClientId() int32 {
 	return 0
 }
 
+func (m *Message) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Message) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Message_OneofMarshaler, _Message_OneofUnmarshaler, _Message_OneofSizer, []interface{}{
@@ -310,6 +422,13 @@ func (*Message) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error
 		(*Message_CsPaillierOpening)(nil),
 		(*Message_CsPaillierProofData)(nil),
 		(*Message_CsPaillierProofRandomData)(nil),
+		(*Message_CsPaillierPubKey)(nil),
+		(*Message_CsPaillierDecryptionRequest)(nil),
+		(*Message_CsPaillierDecryption)(nil),
+		(*Message_PaillierDecryptionShareRequest)(nil),
+		(*Message_PaillierDecryptionShare)(nil),
+		(*Message_ElGamalDecryptionShareRequest)(nil),
+		(*Message_ElGamalDecryptionShare)(nil),
 	}
 }
 
@@ -377,6 +496,41 @@ func _Message_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.CsPaillierProofRandomData); err != nil {
 			return err
 		}
+	case *Message_CsPaillierPubKey:
+		b.EncodeVarint(17<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CsPaillierPubKey); err != nil {
+			return err
+		}
+	case *Message_CsPaillierDecryptionRequest:
+		b.EncodeVarint(18<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CsPaillierDecryptionRequest); err != nil {
+			return err
+		}
+	case *Message_CsPaillierDecryption:
+		b.EncodeVarint(19<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CsPaillierDecryption); err != nil {
+			return err
+		}
+	case *Message_PaillierDecryptionShareRequest:
+		b.EncodeVarint(20<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PaillierDecryptionShareRequest); err != nil {
+			return err
+		}
+	case *Message_PaillierDecryptionShare:
+		b.EncodeVarint(21<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PaillierDecryptionShare); err != nil {
+			return err
+		}
+	case *Message_ElGamalDecryptionShareRequest:
+		b.EncodeVarint(22<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ElGamalDecryptionShareRequest); err != nil {
+			return err
+		}
+	case *Message_ElGamalDecryptionShare:
+		b.EncodeVarint(23<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ElGamalDecryptionShare); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Message.Content has unexpected type %T", x)
@@ -483,6 +637,62 @@ func _Message_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer
 		err := b.DecodeMessage(msg)
 		m.Content = &Message_CsPaillierProofRandomData{msg}
 		return true, err
+	case 17: // content.cs_paillier_pub_key
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(CSPaillierPubKey)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_CsPaillierPubKey{msg}
+		return true, err
+	case 18: // content.cs_paillier_decryption_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(CSPaillierDecryptionRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_CsPaillierDecryptionRequest{msg}
+		return true, err
+	case 19: // content.cs_paillier_decryption
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(CSPaillierDecryption)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_CsPaillierDecryption{msg}
+		return true, err
+	case 20: // content.paillier_decryption_share_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PaillierDecryptionShareRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_PaillierDecryptionShareRequest{msg}
+		return true, err
+	case 21: // content.paillier_decryption_share
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PaillierDecryptionShare)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_PaillierDecryptionShare{msg}
+		return true, err
+	case 22: // content.el_gamal_decryption_share_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ElGamalDecryptionShareRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_ElGamalDecryptionShareRequest{msg}
+		return true, err
+	case 23: // content.el_gamal_decryption_share
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ElGamalDecryptionShare)
+		err := b.DecodeMessage(msg)
+		m.Content = &Message_ElGamalDecryptionShare{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -552,6 +762,41 @@ func _Message_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(14<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Message_CsPaillierPubKey:
+		s := proto.Size(x.CsPaillierPubKey)
+		n += proto.SizeVarint(17<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Message_CsPaillierDecryptionRequest:
+		s := proto.Size(x.CsPaillierDecryptionRequest)
+		n += proto.SizeVarint(18<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Message_CsPaillierDecryption:
+		s := proto.Size(x.CsPaillierDecryption)
+		n += proto.SizeVarint(19<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Message_PaillierDecryptionShareRequest:
+		s := proto.Size(x.PaillierDecryptionShareRequest)
+		n += proto.SizeVarint(20<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Message_PaillierDecryptionShare:
+		s := proto.Size(x.PaillierDecryptionShare)
+		n += proto.SizeVarint(21<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Message_ElGamalDecryptionShareRequest:
+		s := proto.Size(x.ElGamalDecryptionShareRequest)
+		n += proto.SizeVarint(22<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Message_ElGamalDecryptionShare:
+		s := proto.Size(x.ElGamalDecryptionShare)
+		n += proto.SizeVarint(23<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -568,7 +813,8 @@ func (*EmptyMsg) ProtoMessage()               {}
 func (*EmptyMsg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
 type Status struct {
-	Success bool `protobuf:"varint,1,opt,name=Success" json:"Success,omitempty"`
+	Success bool   `protobuf:"varint,1,opt,name=Success" json:"Success,omitempty"`
+	Reason  string `protobuf:"bytes,2,opt,name=Reason" json:"Reason,omitempty"`
 }
 
 func (m *Status) Reset()                    { *m = Status{} }
@@ -583,6 +829,13 @@ func (m *Status) GetSuccess() bool {
 	return false
 }
 
+func (m *Status) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 type BigInt struct {
 	X1 []byte `protobuf:"bytes,1,opt,name=X1,proto3" json:"X1,omitempty"`
 }
@@ -765,6 +1018,8 @@ type CSPaillierSecretKey struct {
 	VerifiableEncGroupH1 []byte `protobuf:"bytes,11,opt,name=VerifiableEncGroupH1,proto3" json:"VerifiableEncGroupH1,omitempty"`
 	K                    int32  `protobuf:"varint,12,opt,name=K" json:"K,omitempty"`
 	K1                   int32  `protobuf:"varint,13,opt,name=K1" json:"K1,omitempty"`
+	P                    []byte `protobuf:"bytes,14,opt,name=P,proto3" json:"P,omitempty"`
+	Q                    []byte `protobuf:"bytes,15,opt,name=Q,proto3" json:"Q,omitempty"`
 }
 
 func (m *CSPaillierSecretKey) Reset()                    { *m = CSPaillierSecretKey{} }
@@ -863,6 +1118,20 @@ func (m *CSPaillierSecretKey) GetK1() int32 {
 	return 0
 }
 
+func (m *CSPaillierSecretKey) GetP() []byte {
+	if m != nil {
+		return m.P
+	}
+	return nil
+}
+
+func (m *CSPaillierSecretKey) GetQ() []byte {
+	if m != nil {
+		return m.Q
+	}
+	return nil
+}
+
 type CSPaillierPubKey struct {
 	N                    []byte `protobuf:"bytes,1,opt,name=N,proto3" json:"N,omitempty"`
 	G                    []byte `protobuf:"bytes,2,opt,name=G,proto3" json:"G,omitempty"`
@@ -975,6 +1244,174 @@ func (m *CSPaillierPubKey) GetK1() int32 {
 	return 0
 }
 
+type PaillierPubKey struct {
+	N []byte `protobuf:"bytes,1,opt,name=N,proto3" json:"N,omitempty"`
+	G []byte `protobuf:"bytes,2,opt,name=G,proto3" json:"G,omitempty"`
+}
+
+func (m *PaillierPubKey) Reset()                    { *m = PaillierPubKey{} }
+func (m *PaillierPubKey) String() string            { return proto.CompactTextString(m) }
+func (*PaillierPubKey) ProtoMessage()               {}
+func (*PaillierPubKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *PaillierPubKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *PaillierPubKey) GetG() []byte {
+	if m != nil {
+		return m.G
+	}
+	return nil
+}
+
+type PaillierSecretKey struct {
+	N      []byte `protobuf:"bytes,1,opt,name=N,proto3" json:"N,omitempty"`
+	G      []byte `protobuf:"bytes,2,opt,name=G,proto3" json:"G,omitempty"`
+	Lambda []byte `protobuf:"bytes,3,opt,name=Lambda,proto3" json:"Lambda,omitempty"`
+	P      []byte `protobuf:"bytes,4,opt,name=P,proto3" json:"P,omitempty"`
+	Q      []byte `protobuf:"bytes,5,opt,name=Q,proto3" json:"Q,omitempty"`
+}
+
+func (m *PaillierSecretKey) Reset()                    { *m = PaillierSecretKey{} }
+func (m *PaillierSecretKey) String() string            { return proto.CompactTextString(m) }
+func (*PaillierSecretKey) ProtoMessage()               {}
+func (*PaillierSecretKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *PaillierSecretKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *PaillierSecretKey) GetG() []byte {
+	if m != nil {
+		return m.G
+	}
+	return nil
+}
+
+func (m *PaillierSecretKey) GetLambda() []byte {
+	if m != nil {
+		return m.Lambda
+	}
+	return nil
+}
+
+func (m *PaillierSecretKey) GetP() []byte {
+	if m != nil {
+		return m.P
+	}
+	return nil
+}
+
+func (m *PaillierSecretKey) GetQ() []byte {
+	if m != nil {
+		return m.Q
+	}
+	return nil
+}
+
+type CLPubKey struct {
+	N []byte   `protobuf:"bytes,1,opt,name=N,proto3" json:"N,omitempty"`
+	A [][]byte `protobuf:"bytes,2,rep,name=A,proto3" json:"A,omitempty"`
+	B []byte   `protobuf:"bytes,3,opt,name=B,proto3" json:"B,omitempty"`
+	C []byte   `protobuf:"bytes,4,opt,name=C,proto3" json:"C,omitempty"`
+}
+
+func (m *CLPubKey) Reset()                    { *m = CLPubKey{} }
+func (m *CLPubKey) String() string            { return proto.CompactTextString(m) }
+func (*CLPubKey) ProtoMessage()               {}
+func (*CLPubKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *CLPubKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *CLPubKey) GetA() [][]byte {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *CLPubKey) GetB() []byte {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+func (m *CLPubKey) GetC() []byte {
+	if m != nil {
+		return m.C
+	}
+	return nil
+}
+
+type CLSecretKey struct {
+	P []byte   `protobuf:"bytes,1,opt,name=P,proto3" json:"P,omitempty"`
+	Q []byte   `protobuf:"bytes,2,opt,name=Q,proto3" json:"Q,omitempty"`
+	N []byte   `protobuf:"bytes,3,opt,name=N,proto3" json:"N,omitempty"`
+	A [][]byte `protobuf:"bytes,4,rep,name=A,proto3" json:"A,omitempty"`
+	B []byte   `protobuf:"bytes,5,opt,name=B,proto3" json:"B,omitempty"`
+	C []byte   `protobuf:"bytes,6,opt,name=C,proto3" json:"C,omitempty"`
+}
+
+func (m *CLSecretKey) Reset()                    { *m = CLSecretKey{} }
+func (m *CLSecretKey) String() string            { return proto.CompactTextString(m) }
+func (*CLSecretKey) ProtoMessage()               {}
+func (*CLSecretKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *CLSecretKey) GetP() []byte {
+	if m != nil {
+		return m.P
+	}
+	return nil
+}
+
+func (m *CLSecretKey) GetQ() []byte {
+	if m != nil {
+		return m.Q
+	}
+	return nil
+}
+
+func (m *CLSecretKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *CLSecretKey) GetA() [][]byte {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *CLSecretKey) GetB() []byte {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+func (m *CLSecretKey) GetC() []byte {
+	if m != nil {
+		return m.C
+	}
+	return nil
+}
+
 type CSPaillierOpening struct {
 	U     []byte `protobuf:"bytes,1,opt,name=U,proto3" json:"U,omitempty"`
 	E     []byte `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
@@ -987,7 +1424,7 @@ type CSPaillierOpening struct {
 func (m *CSPaillierOpening) Reset()                    { *m = CSPaillierOpening{} }
 func (m *CSPaillierOpening) String() string            { return proto.CompactTextString(m) }
 func (*CSPaillierOpening) ProtoMessage()               {}
-func (*CSPaillierOpening) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*CSPaillierOpening) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *CSPaillierOpening) GetU() []byte {
 	if m != nil {
@@ -1042,7 +1479,7 @@ type CSPaillierProofRandomData struct {
 func (m *CSPaillierProofRandomData) Reset()                    { *m = CSPaillierProofRandomData{} }
 func (m *CSPaillierProofRandomData) String() string            { return proto.CompactTextString(m) }
 func (*CSPaillierProofRandomData) ProtoMessage()               {}
-func (*CSPaillierProofRandomData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*CSPaillierProofRandomData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *CSPaillierProofRandomData) GetU1() []byte {
 	if m != nil {
@@ -1079,6 +1516,273 @@ func (m *CSPaillierProofRandomData) GetL1() []byte {
 	return nil
 }
 
+type CSPaillierDecryptionRequest struct {
+	U             []byte `protobuf:"bytes,1,opt,name=U,proto3" json:"U,omitempty"`
+	E             []byte `protobuf:"bytes,2,opt,name=E,proto3" json:"E,omitempty"`
+	V             []byte `protobuf:"bytes,3,opt,name=V,proto3" json:"V,omitempty"`
+	Label         []byte `protobuf:"bytes,4,opt,name=Label,proto3" json:"Label,omitempty"`
+	Justification string `protobuf:"bytes,5,opt,name=Justification" json:"Justification,omitempty"`
+}
+
+func (m *CSPaillierDecryptionRequest) Reset()                    { *m = CSPaillierDecryptionRequest{} }
+func (m *CSPaillierDecryptionRequest) String() string            { return proto.CompactTextString(m) }
+func (*CSPaillierDecryptionRequest) ProtoMessage()               {}
+func (*CSPaillierDecryptionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *CSPaillierDecryptionRequest) GetU() []byte {
+	if m != nil {
+		return m.U
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryptionRequest) GetE() []byte {
+	if m != nil {
+		return m.E
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryptionRequest) GetV() []byte {
+	if m != nil {
+		return m.V
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryptionRequest) GetLabel() []byte {
+	if m != nil {
+		return m.Label
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryptionRequest) GetJustification() string {
+	if m != nil {
+		return m.Justification
+	}
+	return ""
+}
+
+// Plaintext M with a proof of correct decryption (T1, T2, Z)
+type CSPaillierDecryption struct {
+	M  []byte `protobuf:"bytes,1,opt,name=M,proto3" json:"M,omitempty"`
+	T1 []byte `protobuf:"bytes,2,opt,name=T1,proto3" json:"T1,omitempty"`
+	T2 []byte `protobuf:"bytes,3,opt,name=T2,proto3" json:"T2,omitempty"`
+	Z  []byte `protobuf:"bytes,4,opt,name=Z,proto3" json:"Z,omitempty"`
+}
+
+func (m *CSPaillierDecryption) Reset()                    { *m = CSPaillierDecryption{} }
+func (m *CSPaillierDecryption) String() string            { return proto.CompactTextString(m) }
+func (*CSPaillierDecryption) ProtoMessage()               {}
+func (*CSPaillierDecryption) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *CSPaillierDecryption) GetM() []byte {
+	if m != nil {
+		return m.M
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryption) GetT1() []byte {
+	if m != nil {
+		return m.T1
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryption) GetT2() []byte {
+	if m != nil {
+		return m.T2
+	}
+	return nil
+}
+
+func (m *CSPaillierDecryption) GetZ() []byte {
+	if m != nil {
+		return m.Z
+	}
+	return nil
+}
+
+// Request for a decryption share of threshold Paillier ciphertext C, made on behalf of
+// the case with the given label
+type PaillierDecryptionShareRequest struct {
+	C             []byte `protobuf:"bytes,1,opt,name=C,proto3" json:"C,omitempty"`
+	Label         []byte `protobuf:"bytes,2,opt,name=Label,proto3" json:"Label,omitempty"`
+	Justification string `protobuf:"bytes,3,opt,name=Justification" json:"Justification,omitempty"`
+}
+
+func (m *PaillierDecryptionShareRequest) Reset()         { *m = PaillierDecryptionShareRequest{} }
+func (m *PaillierDecryptionShareRequest) String() string { return proto.CompactTextString(m) }
+func (*PaillierDecryptionShareRequest) ProtoMessage()    {}
+func (*PaillierDecryptionShareRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{20}
+}
+
+func (m *PaillierDecryptionShareRequest) GetC() []byte {
+	if m != nil {
+		return m.C
+	}
+	return nil
+}
+
+func (m *PaillierDecryptionShareRequest) GetLabel() []byte {
+	if m != nil {
+		return m.Label
+	}
+	return nil
+}
+
+func (m *PaillierDecryptionShareRequest) GetJustification() string {
+	if m != nil {
+		return m.Justification
+	}
+	return ""
+}
+
+// Decryption share C of the trustee with the given index, with a proof of its
+// correctness (A, B, Z)
+type PaillierDecryptionShare struct {
+	Index int32  `protobuf:"varint,1,opt,name=Index" json:"Index,omitempty"`
+	C     []byte `protobuf:"bytes,2,opt,name=C,proto3" json:"C,omitempty"`
+	A     []byte `protobuf:"bytes,3,opt,name=A,proto3" json:"A,omitempty"`
+	B     []byte `protobuf:"bytes,4,opt,name=B,proto3" json:"B,omitempty"`
+	Z     []byte `protobuf:"bytes,5,opt,name=Z,proto3" json:"Z,omitempty"`
+}
+
+func (m *PaillierDecryptionShare) Reset()                    { *m = PaillierDecryptionShare{} }
+func (m *PaillierDecryptionShare) String() string            { return proto.CompactTextString(m) }
+func (*PaillierDecryptionShare) ProtoMessage()               {}
+func (*PaillierDecryptionShare) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *PaillierDecryptionShare) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *PaillierDecryptionShare) GetC() []byte {
+	if m != nil {
+		return m.C
+	}
+	return nil
+}
+
+func (m *PaillierDecryptionShare) GetA() []byte {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *PaillierDecryptionShare) GetB() []byte {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+func (m *PaillierDecryptionShare) GetZ() []byte {
+	if m != nil {
+		return m.Z
+	}
+	return nil
+}
+
+// Request for a decryption share of threshold ElGamal ciphertext (C1, C2), made on
+// behalf of the case with the given label
+type ElGamalDecryptionShareRequest struct {
+	C1            []byte `protobuf:"bytes,1,opt,name=C1,proto3" json:"C1,omitempty"`
+	C2            []byte `protobuf:"bytes,2,opt,name=C2,proto3" json:"C2,omitempty"`
+	Label         []byte `protobuf:"bytes,3,opt,name=Label,proto3" json:"Label,omitempty"`
+	Justification string `protobuf:"bytes,4,opt,name=Justification" json:"Justification,omitempty"`
+}
+
+func (m *ElGamalDecryptionShareRequest) Reset()                    { *m = ElGamalDecryptionShareRequest{} }
+func (m *ElGamalDecryptionShareRequest) String() string            { return proto.CompactTextString(m) }
+func (*ElGamalDecryptionShareRequest) ProtoMessage()               {}
+func (*ElGamalDecryptionShareRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *ElGamalDecryptionShareRequest) GetC1() []byte {
+	if m != nil {
+		return m.C1
+	}
+	return nil
+}
+
+func (m *ElGamalDecryptionShareRequest) GetC2() []byte {
+	if m != nil {
+		return m.C2
+	}
+	return nil
+}
+
+func (m *ElGamalDecryptionShareRequest) GetLabel() []byte {
+	if m != nil {
+		return m.Label
+	}
+	return nil
+}
+
+func (m *ElGamalDecryptionShareRequest) GetJustification() string {
+	if m != nil {
+		return m.Justification
+	}
+	return ""
+}
+
+// Decryption share D of the trustee with the given index, with a proof of its
+// correctness (X1, X2, Z)
+type ElGamalDecryptionShare struct {
+	Index int32  `protobuf:"varint,1,opt,name=Index" json:"Index,omitempty"`
+	D     []byte `protobuf:"bytes,2,opt,name=D,proto3" json:"D,omitempty"`
+	X1    []byte `protobuf:"bytes,3,opt,name=X1,proto3" json:"X1,omitempty"`
+	X2    []byte `protobuf:"bytes,4,opt,name=X2,proto3" json:"X2,omitempty"`
+	Z     []byte `protobuf:"bytes,5,opt,name=Z,proto3" json:"Z,omitempty"`
+}
+
+func (m *ElGamalDecryptionShare) Reset()                    { *m = ElGamalDecryptionShare{} }
+func (m *ElGamalDecryptionShare) String() string            { return proto.CompactTextString(m) }
+func (*ElGamalDecryptionShare) ProtoMessage()               {}
+func (*ElGamalDecryptionShare) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *ElGamalDecryptionShare) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ElGamalDecryptionShare) GetD() []byte {
+	if m != nil {
+		return m.D
+	}
+	return nil
+}
+
+func (m *ElGamalDecryptionShare) GetX1() []byte {
+	if m != nil {
+		return m.X1
+	}
+	return nil
+}
+
+func (m *ElGamalDecryptionShare) GetX2() []byte {
+	if m != nil {
+		return m.X2
+	}
+	return nil
+}
+
+func (m *ElGamalDecryptionShare) GetZ() []byte {
+	if m != nil {
+		return m.Z
+	}
+	return nil
+}
+
 type CSPaillierProofData struct {
 	RTilde      []byte `protobuf:"bytes,1,opt,name=RTilde,proto3" json:"RTilde,omitempty"`
 	RTildeIsNeg bool   `protobuf:"varint,2,opt,name=RTildeIsNeg" json:"RTildeIsNeg,omitempty"`
@@ -1091,7 +1795,7 @@ type CSPaillierProofData struct {
 func (m *CSPaillierProofData) Reset()                    { *m = CSPaillierProofData{} }
 func (m *CSPaillierProofData) String() string            { return proto.CompactTextString(m) }
 func (*CSPaillierProofData) ProtoMessage()               {}
-func (*CSPaillierProofData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*CSPaillierProofData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *CSPaillierProofData) GetRTilde() []byte {
 	if m != nil {
@@ -1148,8 +1852,18 @@ func init() {
 	proto.RegisterType((*SchnorrProofData)(nil), "protobuf.SchnorrProofData")
 	proto.RegisterType((*CSPaillierSecretKey)(nil), "protobuf.CSPaillierSecretKey")
 	proto.RegisterType((*CSPaillierPubKey)(nil), "protobuf.CSPaillierPubKey")
+	proto.RegisterType((*PaillierPubKey)(nil), "protobuf.PaillierPubKey")
+	proto.RegisterType((*PaillierSecretKey)(nil), "protobuf.PaillierSecretKey")
+	proto.RegisterType((*CLPubKey)(nil), "protobuf.CLPubKey")
+	proto.RegisterType((*CLSecretKey)(nil), "protobuf.CLSecretKey")
 	proto.RegisterType((*CSPaillierOpening)(nil), "protobuf.CSPaillierOpening")
 	proto.RegisterType((*CSPaillierProofRandomData)(nil), "protobuf.CSPaillierProofRandomData")
+	proto.RegisterType((*CSPaillierDecryptionRequest)(nil), "protobuf.CSPaillierDecryptionRequest")
+	proto.RegisterType((*CSPaillierDecryption)(nil), "protobuf.CSPaillierDecryption")
+	proto.RegisterType((*PaillierDecryptionShareRequest)(nil), "protobuf.PaillierDecryptionShareRequest")
+	proto.RegisterType((*PaillierDecryptionShare)(nil), "protobuf.PaillierDecryptionShare")
+	proto.RegisterType((*ElGamalDecryptionShareRequest)(nil), "protobuf.ElGamalDecryptionShareRequest")
+	proto.RegisterType((*ElGamalDecryptionShare)(nil), "protobuf.ElGamalDecryptionShare")
 	proto.RegisterType((*CSPaillierProofData)(nil), "protobuf.CSPaillierProofData")
 	proto.RegisterEnum("protobuf.SchemaType", SchemaType_name, SchemaType_value)
 	proto.RegisterEnum("protobuf.SchemaVariant", SchemaVariant_name, SchemaVariant_value)
@@ -1262,73 +1976,105 @@ var _Protocol_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("msgs.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1079 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe4, 0x56, 0xed, 0x4e, 0xe3, 0x46,
-	0x14, 0xc5, 0x81, 0x84, 0x70, 0x09, 0xd9, 0x30, 0x50, 0x6a, 0x68, 0xa9, 0x90, 0x2b, 0xad, 0x56,
-	0x68, 0x85, 0xd6, 0xe1, 0x6f, 0x5b, 0x95, 0x80, 0x9b, 0xa4, 0xf9, 0x20, 0x3b, 0x21, 0x11, 0x41,
-	0xaa, 0x22, 0xc7, 0x19, 0x5c, 0x4b, 0x89, 0x6d, 0xd9, 0x4e, 0x2b, 0x5e, 0xa3, 0x4f, 0xd0, 0x77,
-	0xe9, 0x4b, 0x54, 0xea, 0xcb, 0x74, 0x66, 0x3c, 0x93, 0x38, 0xb1, 0x41, 0xfd, 0xdf, 0x5f, 0xf8,
-	0xdc, 0x39, 0xe7, 0xdc, 0xc9, 0x19, 0xcf, 0xc5, 0x00, 0xf3, 0xd0, 0x0e, 0xaf, 0xfc, 0xc0, 0x8b,
-	0x3c, 0x54, 0xe4, 0x7f, 0x26, 0x8b, 0x67, 0xed, 0xcf, 0x22, 0xec, 0x76, 0x48, 0x18, 0x9a, 0x36,
-	0x41, 0x1f, 0xa1, 0x10, 0x5a, 0xbf, 0x92, 0xb9, 0xa9, 0x2a, 0x17, 0xca, 0x87, 0x72, 0xf5, 0xf8,
-	0x4a, 0xd2, 0xae, 0xfa, 0xbc, 0xfe, 0xf0, 0xe2, 0x13, 0x2c, 0x38, 0xe8, 0x07, 0x28, 0xc7, 0x4f,
-	0xe3, 0xdf, 0xcc, 0xc0, 0x31, 0xdd, 0x48, 0xcd, 0x71, 0xd5, 0x97, 0x9b, 0xaa, 0x61, 0xbc, 0x8c,
-	0x0f, 0xc2, 0x24, 0x44, 0x97, 0x90, 0x27, 0x73, 0x3f, 0x7a, 0x51, 0xb7, 0xa9, 0x6c, 0xbf, 0x8a,
-	0x56, 0x32, 0x83, 0x95, 0x3b, 0xa1, 0xdd, 0xd8, 0xc2, 0x31, 0x85, 0x72, 0x0b, 0x13, 0xc7, 0x76,
-	0x68, 0x8f, 0x1d, 0x4e, 0xae, 0xac, 0xc8, 0x35, 0xc7, 0x6e, 0xba, 0x11, 0xa5, 0x0a, 0x06, 0xba,
-	0x83, 0x0a, 0xb1, 0xc6, 0x76, 0xe0, 0x2d, 0xfc, 0x31, 0x99, 0x91, 0x39, 0xa1, 0xaa, 0x3c, 0x57,
-	0xa9, 0x89, 0x16, 0xb7, 0x75, 0x46, 0x30, 0xe2, 0x75, 0xaa, 0x2e, 0x13, 0x2b, 0x59, 0x61, 0x1d,
-	0xc3, 0xc8, 0x8c, 0x16, 0xa1, 0x5a, 0xd8, 0xec, 0xd8, 0xe7, 0x75, 0xd6, 0x31, 0x66, 0xa0, 0x1f,
-	0xa1, 0xec, 0x93, 0x29, 0x09, 0x42, 0xe2, 0x8e, 0x9f, 0x9d, 0x20, 0x8c, 0xd4, 0x5d, 0xae, 0x49,
-	0x24, 0xd1, 0x13, 0xeb, 0x3f, 0xb1, 0x65, 0x2a, 0x3d, 0xf0, 0x93, 0x05, 0x34, 0x80, 0x2f, 0x96,
-	0x0e, 0x53, 0x62, 0x79, 0xf3, 0xb9, 0x13, 0xf1, 0x8d, 0x17, 0xb9, 0xd1, 0x37, 0x69, 0xa3, 0xbb,
-	0x04, 0x8b, 0xfa, 0x1d, 0xfb, 0x19, 0x75, 0xf4, 0x33, 0x20, 0x9a, 0xb9, 0xeb, 0x05, 0xc1, 0x98,
-	0x1a, 0x78, 0xcf, 0xe3, 0xa9, 0x19, 0x99, 0xea, 0x1e, 0xf7, 0x3c, 0x5b, 0x3b, 0x26, 0xc6, 0xe9,
-	0x31, 0xca, 0x1d, 0x65, 0x50, 0xbf, 0x4a, 0xb8, 0x51, 0x43, 0xbf, 0xc0, 0xe9, 0xba, 0x57, 0x60,
-	0xba, 0x53, 0x6f, 0x1e, 0x5b, 0x02, 0xb7, 0xbc, 0xc8, 0xb6, 0xc4, 0x9c, 0x28, 0x8c, 0x4f, 0xc2,
-	0xcc, 0x15, 0x34, 0x85, 0xaf, 0xa5, 0x3d, 0x3d, 0xbd, 0x74, 0x87, 0x7d, 0xde, 0x41, 0x4b, 0x75,
-	0x30, 0x6e, 0xd3, 0x3d, 0x54, 0xe1, 0x64, 0x58, 0x9b, 0x5d, 0x3a, 0x70, 0x64, 0x85, 0x63, 0xdf,
-	0x74, 0x66, 0x33, 0x87, 0x04, 0x63, 0xcf, 0x27, 0xae, 0xe3, 0xda, 0x6a, 0x89, 0x9b, 0x7f, 0xb5,
-	0x32, 0xbf, 0xed, 0xf7, 0x04, 0xe7, 0x3e, 0xa6, 0x50, 0xd7, 0x43, 0x2b, 0xdc, 0x28, 0xa2, 0x07,
-	0x38, 0x49, 0xda, 0x25, 0x32, 0x3e, 0xe0, 0x8e, 0xe7, 0x59, 0x8e, 0xc9, 0x98, 0x8f, 0x56, 0x9e,
-	0xab, 0xa4, 0x6d, 0x38, 0x4f, 0xbb, 0x26, 0xb3, 0x28, 0x73, 0xf3, 0x6f, 0x5f, 0x35, 0x5f, 0x0b,
-	0xe3, 0x74, 0xa3, 0x45, 0x22, 0x8d, 0x33, 0x28, 0x5a, 0xb4, 0xee, 0x46, 0xcd, 0xa9, 0xfa, 0x8e,
-	0x7a, 0xe6, 0xf1, 0x12, 0xd7, 0xf6, 0x60, 0xd7, 0xf2, 0xdc, 0x88, 0x02, 0x0d, 0xa0, 0x28, 0x6f,
-	0xa4, 0xa6, 0x41, 0x21, 0x7e, 0xfd, 0x91, 0x0a, 0xbb, 0xfd, 0x85, 0x65, 0xd1, 0xd1, 0xc1, 0xa7,
-	0x45, 0x11, 0x4b, 0xa8, 0xa9, 0x50, 0x88, 0x2f, 0x25, 0x2a, 0x43, 0xee, 0x51, 0xe7, 0xcb, 0x25,
-	0x4c, 0x9f, 0xb4, 0x73, 0x38, 0x58, 0xbb, 0x08, 0xa8, 0x04, 0x4a, 0x43, 0xac, 0x2b, 0x0d, 0xad,
-	0x0a, 0xc7, 0x59, 0xaf, 0x37, 0x63, 0x3d, 0x4a, 0xd6, 0x23, 0x43, 0x98, 0x8f, 0x1a, 0x8a, 0xb0,
-	0xf6, 0x11, 0xca, 0xeb, 0x77, 0x39, 0xcd, 0x1e, 0x49, 0xf6, 0x48, 0xab, 0xc1, 0x49, 0xf6, 0x9b,
-	0x99, 0x56, 0xdd, 0x48, 0xd5, 0x0d, 0x43, 0x35, 0x3e, 0xa5, 0x28, 0xaa, 0x69, 0x7f, 0x28, 0xa0,
-	0xbe, 0xf6, 0xf2, 0xa1, 0xf7, 0xd2, 0xe6, 0x8d, 0x69, 0xc3, 0x1a, 0xbc, 0x97, 0x0d, 0xde, 0xe4,
-	0xdd, 0x30, 0x5e, 0x4d, 0x0c, 0xc8, 0x37, 0x78, 0x35, 0xed, 0x3b, 0xa8, 0x6c, 0xde, 0x62, 0xb6,
-	0xed, 0x27, 0xf9, 0x93, 0x9e, 0xd8, 0x61, 0x3f, 0x04, 0xa6, 0x3f, 0xf5, 0xbc, 0x40, 0xfc, 0xb2,
-	0x25, 0xd6, 0xfe, 0xc9, 0xc1, 0xd1, 0xea, 0x1d, 0xea, 0x13, 0x2b, 0x20, 0x51, 0x8b, 0xbc, 0x30,
-	0x87, 0xae, 0x74, 0xe8, 0x32, 0x54, 0x97, 0xa1, 0xd4, 0xc5, 0xd9, 0x6e, 0xcb, 0xb3, 0xe5, 0xb8,
-	0xca, 0xc7, 0x33, 0xc3, 0x55, 0x8e, 0xaf, 0xf9, 0xe0, 0x65, 0xf8, 0x1a, 0x1d, 0x43, 0xfe, 0xae,
-	0xed, 0xd9, 0x3d, 0x3e, 0x4f, 0x4b, 0x38, 0x06, 0xb2, 0x5a, 0xe7, 0x13, 0x53, 0x54, 0xeb, 0xb2,
-	0xfa, 0x99, 0x8f, 0x3f, 0x51, 0xfd, 0x8c, 0x3e, 0xc1, 0xd1, 0x90, 0x04, 0xce, 0xb3, 0x63, 0x4e,
-	0x66, 0xc4, 0x70, 0xe3, 0x79, 0xdd, 0xe5, 0xe3, 0xac, 0x84, 0xb3, 0x96, 0x10, 0x7d, 0xa1, 0xd2,
-	0xe5, 0xba, 0xce, 0xc7, 0x55, 0x09, 0x67, 0xae, 0x65, 0x6b, 0x1a, 0x3a, 0x1f, 0x40, 0x99, 0x9a,
-	0x86, 0xce, 0x92, 0x69, 0xf1, 0x21, 0x92, 0xc7, 0x4a, 0x8b, 0xfd, 0xf2, 0x96, 0xce, 0x27, 0x40,
-	0x1e, 0xd3, 0x27, 0xed, 0xef, 0x1c, 0x54, 0x12, 0x37, 0x74, 0x31, 0xf9, 0x0f, 0xd1, 0x8e, 0x96,
-	0xd1, 0x8e, 0x78, 0xb4, 0xa3, 0x65, 0xb4, 0x23, 0x1e, 0xed, 0x68, 0x19, 0xed, 0xe8, 0xff, 0x1c,
-	0xed, 0xef, 0x70, 0x98, 0x1a, 0xd5, 0x4c, 0x32, 0x90, 0xd1, 0x0e, 0x18, 0x32, 0x64, 0xb4, 0x06,
-	0x43, 0x43, 0x79, 0x95, 0x87, 0x3c, 0x0c, 0x32, 0xa3, 0x13, 0x75, 0x47, 0x84, 0xc1, 0x00, 0xab,
-	0xb6, 0xcd, 0x09, 0x99, 0x89, 0x84, 0x63, 0xc0, 0x94, 0x6d, 0x11, 0xb0, 0xd2, 0xd6, 0x42, 0x38,
-	0x7d, 0x75, 0xe8, 0xb2, 0x5d, 0x0e, 0x96, 0x63, 0x6f, 0xc0, 0xcf, 0xcf, 0xd0, 0xc5, 0x1e, 0xe8,
-	0x13, 0xc3, 0xc3, 0xe5, 0xf9, 0x0e, 0x75, 0x74, 0x02, 0x05, 0xde, 0x59, 0x17, 0xfb, 0x10, 0x88,
-	0xf1, 0xda, 0xba, 0x3c, 0xe7, 0xb6, 0xae, 0xfd, 0xa5, 0x24, 0xaf, 0xe9, 0xea, 0xa2, 0x53, 0x3d,
-	0x7e, 0x70, 0x66, 0x53, 0x22, 0x7a, 0x0a, 0x84, 0x2e, 0x60, 0x3f, 0x7e, 0x6a, 0x86, 0x5d, 0x62,
-	0xf3, 0x0d, 0x14, 0x71, 0xb2, 0xc4, 0x94, 0xfd, 0x58, 0x19, 0xef, 0x46, 0x20, 0xa6, 0xec, 0x27,
-	0x94, 0x3b, 0xb1, 0xb2, 0xbf, 0xae, 0xec, 0xc4, 0xca, 0x78, 0x7f, 0x02, 0x31, 0x65, 0x27, 0xa1,
-	0x2c, 0xc4, 0xca, 0x44, 0xe9, 0xf2, 0x11, 0x60, 0xf5, 0x35, 0x49, 0x63, 0x2d, 0xf6, 0x8c, 0x3b,
-	0x03, 0xf7, 0x8d, 0x6e, 0x65, 0x0b, 0xbd, 0x83, 0x7d, 0x89, 0xc6, 0xc6, 0x6d, 0x45, 0x41, 0xfb,
-	0xf4, 0xbf, 0xcc, 0x6d, 0xa3, 0x7b, 0x8f, 0x71, 0x25, 0x47, 0xf3, 0x00, 0x01, 0xd8, 0xe2, 0x36,
-	0xc3, 0x34, 0x8e, 0x9b, 0x66, 0xbb, 0xdd, 0x34, 0x70, 0x65, 0xe7, 0xf2, 0x0a, 0x0e, 0xd6, 0xbe,
-	0x38, 0xd1, 0x1e, 0xe4, 0xfb, 0xcd, 0x7a, 0xe7, 0x86, 0x3a, 0xef, 0xc2, 0xf6, 0x53, 0xab, 0x47,
-	0x1d, 0x69, 0x8d, 0x3e, 0xdc, 0xb7, 0x2a, 0xb9, 0xea, 0xf7, 0xb4, 0x37, 0x1b, 0xa9, 0x96, 0x37,
-	0x43, 0x3a, 0x6c, 0xe3, 0x85, 0x8b, 0x0e, 0x57, 0x43, 0x56, 0x7c, 0x15, 0x9f, 0xa5, 0x4b, 0xda,
-	0xd6, 0x07, 0xe5, 0x93, 0x32, 0x29, 0xf0, 0xfa, 0xf5, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xa3,
-	0xe5, 0x74, 0xec, 0x59, 0x0b, 0x00, 0x00,
+	// 1590 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x58, 0xdd, 0x72, 0xe2, 0x46,
+	0x16, 0x46, 0xfc, 0xfb, 0x18, 0x33, 0xb8, 0x6d, 0x63, 0x79, 0x66, 0xec, 0xf2, 0x68, 0x77, 0x67,
+	0x5d, 0xae, 0x29, 0xd7, 0xc0, 0xdc, 0x6d, 0xed, 0x6e, 0x05, 0x0b, 0x05, 0x18, 0xc0, 0xc6, 0x0d,
+	0x76, 0x19, 0x57, 0xa5, 0x54, 0x42, 0xb4, 0x19, 0xcd, 0x80, 0x44, 0x24, 0x91, 0xc4, 0x95, 0xcb,
+	0xbc, 0x41, 0x9e, 0x24, 0xef, 0x90, 0x97, 0xc8, 0x93, 0x24, 0xb7, 0xa9, 0x6e, 0xa9, 0x41, 0x02,
+	0xc1, 0x64, 0xae, 0x73, 0x65, 0x7f, 0xa7, 0xcf, 0xf9, 0xbe, 0x3e, 0xa7, 0x5b, 0xe7, 0x74, 0x01,
+	0x30, 0x71, 0x46, 0xce, 0xc5, 0xd4, 0xb6, 0x5c, 0x0b, 0x65, 0xd9, 0x9f, 0xc1, 0xec, 0x51, 0xfa,
+	0x7d, 0x07, 0x32, 0x6d, 0xe2, 0x38, 0xda, 0x88, 0xa0, 0x37, 0x90, 0x76, 0xf4, 0x0f, 0x64, 0xa2,
+	0x89, 0xc2, 0xa9, 0x70, 0x96, 0x2f, 0xef, 0x5f, 0x70, 0xb7, 0x8b, 0x2e, 0xb3, 0xf7, 0x9e, 0xa6,
+	0x04, 0xfb, 0x3e, 0xe8, 0xff, 0x90, 0xf7, 0xfe, 0x53, 0xbf, 0xd3, 0x6c, 0x43, 0x33, 0x5d, 0x31,
+	0xce, 0xa2, 0x0e, 0x97, 0xa3, 0xee, 0xbc, 0x65, 0xbc, 0xe3, 0x04, 0x21, 0x3a, 0x87, 0x14, 0x99,
+	0x4c, 0xdd, 0x27, 0x31, 0x71, 0x2a, 0x9c, 0x6d, 0x97, 0xd1, 0x22, 0x4c, 0xa1, 0xe6, 0xb6, 0x33,
+	0xaa, 0xc7, 0xb0, 0xe7, 0x82, 0xce, 0x21, 0x3d, 0x30, 0x46, 0x86, 0xe9, 0x8a, 0x49, 0xe6, 0x5c,
+	0x58, 0x38, 0x5f, 0x1a, 0xa3, 0x86, 0xe9, 0xd6, 0x63, 0xd8, 0xf7, 0x40, 0x55, 0x28, 0x10, 0x5d,
+	0x1d, 0xd9, 0xd6, 0x6c, 0xaa, 0x92, 0x31, 0x99, 0x10, 0xd3, 0x15, 0x53, 0x2c, 0x4a, 0x0c, 0x48,
+	0xc8, 0x35, 0xea, 0xa0, 0x78, 0xeb, 0xf5, 0x18, 0xce, 0x13, 0x3d, 0x68, 0xa1, 0x8a, 0x8e, 0xab,
+	0xb9, 0x33, 0x47, 0x4c, 0x2f, 0x2b, 0x76, 0x99, 0x9d, 0x2a, 0x7a, 0x1e, 0xe8, 0x2b, 0xc8, 0x4f,
+	0xc9, 0x90, 0xd8, 0x0e, 0x31, 0xd5, 0x47, 0xc3, 0x76, 0x5c, 0x31, 0xc3, 0x62, 0x02, 0x95, 0xe8,
+	0xf8, 0xeb, 0x5f, 0xd3, 0xe5, 0x7a, 0x0c, 0xef, 0x4c, 0x83, 0x06, 0x74, 0x0b, 0x07, 0x73, 0x86,
+	0x21, 0xd1, 0xad, 0xc9, 0xc4, 0x70, 0xd9, 0xc6, 0xb3, 0x8c, 0xe8, 0x64, 0x95, 0xa8, 0x1a, 0xf0,
+	0xaa, 0xc7, 0xf0, 0xfe, 0x34, 0xc2, 0x8e, 0xde, 0x03, 0x72, 0xf4, 0x0f, 0xa6, 0x65, 0xdb, 0xea,
+	0xd4, 0xb6, 0xac, 0x47, 0x75, 0xa8, 0xb9, 0x9a, 0xb8, 0xc5, 0x38, 0x9f, 0x87, 0x8e, 0x89, 0xfa,
+	0x74, 0xa8, 0x4b, 0x55, 0x73, 0xb5, 0x7a, 0x0c, 0x17, 0x9c, 0x25, 0x1b, 0xfa, 0x06, 0x8e, 0xc2,
+	0x5c, 0xb6, 0x66, 0x0e, 0xad, 0x89, 0x47, 0x09, 0x8c, 0xf2, 0x34, 0x9a, 0x12, 0x33, 0x47, 0x9f,
+	0xb8, 0xe8, 0x44, 0xae, 0xa0, 0x21, 0xbc, 0xe4, 0xf4, 0x44, 0x8f, 0x50, 0xd8, 0x66, 0x0a, 0xd2,
+	0x8a, 0x82, 0x22, 0xaf, 0x6a, 0x88, 0x3e, 0x93, 0xa2, 0x2f, 0xab, 0xb4, 0x61, 0x4f, 0x77, 0xd4,
+	0xa9, 0x66, 0x8c, 0xc7, 0x06, 0xb1, 0x55, 0x6b, 0x4a, 0x4c, 0xc3, 0x1c, 0x89, 0x39, 0x46, 0xfe,
+	0x62, 0x41, 0x2e, 0x77, 0x3b, 0xbe, 0xcf, 0xb5, 0xe7, 0x52, 0x8f, 0xe1, 0x5d, 0xdd, 0x59, 0x32,
+	0xa2, 0x1e, 0x14, 0x83, 0x74, 0x81, 0x1a, 0xef, 0x30, 0xc6, 0xe3, 0x28, 0xc6, 0x60, 0x99, 0xf7,
+	0x74, 0x67, 0xc5, 0x8c, 0x46, 0x70, 0xbc, 0xca, 0x1a, 0xac, 0x45, 0x9e, 0x91, 0xff, 0x63, 0x2d,
+	0x79, 0xa8, 0x18, 0x47, 0xba, 0xb3, 0x66, 0x11, 0x35, 0xc3, 0xd5, 0x98, 0xce, 0x06, 0xea, 0x27,
+	0xf2, 0x24, 0xee, 0x2e, 0xdf, 0x8f, 0x00, 0xfd, 0x6c, 0xd0, 0x24, 0x4f, 0xf4, 0x7e, 0xe8, 0x4e,
+	0xd8, 0x86, 0xc6, 0x70, 0x12, 0x24, 0x1b, 0x12, 0xdd, 0x7e, 0x9a, 0xba, 0x86, 0x65, 0xaa, 0x36,
+	0xf9, 0x76, 0x46, 0x1c, 0x57, 0x44, 0x8c, 0xf7, 0x5f, 0x51, 0xbc, 0xd5, 0xb9, 0x37, 0xf6, 0x9c,
+	0xeb, 0x31, 0xfc, 0x42, 0x77, 0xd6, 0x2e, 0xa3, 0x3b, 0x28, 0x46, 0xab, 0x89, 0x7b, 0xcb, 0x5f,
+	0x4c, 0x94, 0x0a, 0xfd, 0x62, 0xa2, 0xe8, 0xd1, 0x0c, 0x5e, 0x45, 0xa5, 0xe0, 0x7c, 0xd0, 0x6c,
+	0x32, 0x4f, 0x64, 0x9f, 0x49, 0x9c, 0x05, 0x3e, 0xca, 0x15, 0xa2, 0x2e, 0x0d, 0x58, 0xe4, 0x72,
+	0x32, 0xdd, 0xe8, 0x81, 0x54, 0x38, 0x5a, 0x2b, 0x2b, 0x1e, 0x30, 0xb9, 0x57, 0x9f, 0x95, 0xab,
+	0xc7, 0xf0, 0xe1, 0x1a, 0x1d, 0xe4, 0xc0, 0x2b, 0x32, 0x56, 0x47, 0xda, 0x44, 0x1b, 0xaf, 0xcf,
+	0xab, 0xc8, 0x84, 0xfe, 0x1d, 0xe8, 0x92, 0xe3, 0x1a, 0x8d, 0x58, 0x9b, 0xd6, 0x31, 0xd9, 0xe4,
+	0x40, 0x5b, 0xc6, 0x5a, 0x51, 0xf1, 0x70, 0xb9, 0x65, 0x44, 0x8b, 0xd1, 0x96, 0x11, 0xad, 0x82,
+	0x9e, 0x43, 0x56, 0x1f, 0x1b, 0xc4, 0x74, 0x1b, 0x43, 0xf1, 0xd9, 0xa9, 0x70, 0x96, 0xc2, 0x73,
+	0x8c, 0x0e, 0x20, 0xfd, 0x89, 0x3c, 0xa9, 0xc6, 0x50, 0x2c, 0x9c, 0x0a, 0x67, 0x5b, 0x38, 0xf5,
+	0x89, 0x3c, 0x35, 0x86, 0x97, 0x5b, 0x90, 0xd1, 0x2d, 0xd3, 0x25, 0xa6, 0x2b, 0x01, 0x64, 0xf9,
+	0x9c, 0x91, 0xfe, 0x03, 0x69, 0xaf, 0xa9, 0x23, 0x11, 0x32, 0xdd, 0x99, 0xae, 0x13, 0xc7, 0x61,
+	0x33, 0x30, 0x8b, 0x39, 0x44, 0x45, 0x48, 0x63, 0xa2, 0x39, 0x96, 0xc9, 0xc6, 0xdc, 0x16, 0xf6,
+	0x91, 0x24, 0x42, 0xda, 0x1b, 0x41, 0x28, 0x0f, 0xf1, 0xfb, 0x12, 0x0b, 0xcb, 0xe1, 0xf8, 0x7d,
+	0x49, 0x3a, 0x86, 0x9d, 0x50, 0xdb, 0x47, 0x39, 0x10, 0xea, 0xfe, 0xba, 0x50, 0x97, 0xca, 0xb0,
+	0x1f, 0xd5, 0xcc, 0xa9, 0xd7, 0x3d, 0xf7, 0xba, 0xa7, 0x08, 0x33, 0xc5, 0x1c, 0x16, 0xb0, 0xf4,
+	0x06, 0xf2, 0xe1, 0xc9, 0xb5, 0xea, 0xdd, 0xe7, 0xde, 0x7d, 0xe9, 0x12, 0x8a, 0xd1, 0x7d, 0x78,
+	0x35, 0xaa, 0xc2, 0xa3, 0x2a, 0x14, 0x5d, 0xb2, 0x99, 0x9c, 0xc3, 0xc2, 0xa5, 0xf4, 0xb3, 0x00,
+	0xe2, 0xba, 0x56, 0x8b, 0x5e, 0x73, 0x9a, 0x0d, 0xb3, 0x95, 0x0a, 0xbc, 0xe6, 0x02, 0x1b, 0xfd,
+	0x2a, 0xe8, 0x35, 0x97, 0xde, 0xe8, 0x77, 0x29, 0xfd, 0x17, 0x0a, 0xcb, 0x33, 0x8b, 0x6e, 0xfb,
+	0x81, 0xa7, 0xf4, 0x40, 0xef, 0x46, 0xcf, 0xd6, 0xa6, 0x43, 0xcb, 0xb2, 0xfd, 0xcc, 0xe6, 0x58,
+	0xfa, 0x23, 0x0e, 0x7b, 0x8b, 0xa6, 0xd0, 0x25, 0xba, 0x4d, 0x5c, 0xda, 0xc1, 0x72, 0x20, 0x5c,
+	0x71, 0x86, 0x2b, 0x8a, 0x6a, 0xbc, 0x28, 0x35, 0xff, 0x6c, 0x13, 0xfc, 0x6c, 0x19, 0x2e, 0x8b,
+	0x49, 0x1f, 0x97, 0x19, 0x7e, 0x27, 0xa6, 0x7c, 0xfc, 0x0e, 0xed, 0x43, 0xaa, 0xda, 0xb2, 0x46,
+	0x1d, 0xf6, 0x7a, 0xc8, 0x61, 0x0f, 0x70, 0x6b, 0x4d, 0xcc, 0x2c, 0xac, 0x35, 0x6e, 0xbd, 0x11,
+	0xb3, 0x0b, 0xeb, 0x0d, 0x7a, 0x0b, 0x7b, 0x77, 0xc4, 0x36, 0x1e, 0x0d, 0x6d, 0x30, 0x26, 0x8a,
+	0xe9, 0xbd, 0x4e, 0xae, 0xd8, 0xf0, 0xce, 0xe1, 0xa8, 0x25, 0x54, 0x86, 0xfd, 0x55, 0x73, 0xad,
+	0xc4, 0x86, 0x73, 0x0e, 0x47, 0xae, 0x45, 0xc7, 0xd4, 0x4b, 0xe2, 0xf6, 0xba, 0x98, 0x7a, 0x89,
+	0x56, 0xa6, 0xc9, 0x46, 0x66, 0x0a, 0x0b, 0x4d, 0x9a, 0x79, 0xb3, 0xc4, 0xe6, 0x5d, 0x0a, 0xc7,
+	0x9b, 0x6c, 0xb5, 0xc3, 0x26, 0x54, 0x0e, 0x0b, 0x1d, 0x8a, 0x6e, 0xd8, 0xc7, 0x99, 0xc3, 0xc2,
+	0x8d, 0xf4, 0x5b, 0x1c, 0x0a, 0xcb, 0xc3, 0xe4, 0x73, 0x65, 0xef, 0xcf, 0xcb, 0xde, 0x67, 0x65,
+	0xef, 0xcf, 0xcb, 0xde, 0x67, 0x65, 0xef, 0xcf, 0xcb, 0xde, 0xff, 0x1b, 0x97, 0x9d, 0x76, 0x86,
+	0xbf, 0x5e, 0x57, 0x49, 0x87, 0xdd, 0x2f, 0xbb, 0xff, 0x45, 0x48, 0xb7, 0xb4, 0xc9, 0x60, 0xa8,
+	0xf9, 0x87, 0xe1, 0x23, 0xef, 0xb4, 0x93, 0xa1, 0xd3, 0x4e, 0xf1, 0xd3, 0xbe, 0x84, 0xac, 0xdc,
+	0x5a, 0xb7, 0x19, 0xda, 0x0f, 0x12, 0x11, 0x0d, 0x87, 0x22, 0x99, 0x33, 0xca, 0x92, 0x06, 0xdb,
+	0x72, 0x2b, 0xb4, 0xc5, 0x8e, 0x28, 0x84, 0xe4, 0xfc, 0x2d, 0xde, 0x78, 0x12, 0x89, 0x90, 0x44,
+	0x32, 0x24, 0x91, 0x0a, 0x49, 0xa4, 0xb9, 0xc4, 0xf7, 0xb0, 0xbb, 0xf2, 0xdc, 0xa3, 0x2e, 0xb7,
+	0x5c, 0xe8, 0x96, 0x22, 0x85, 0x0b, 0x29, 0x14, 0xdd, 0x71, 0xa1, 0x3b, 0x76, 0x8d, 0xc8, 0xd8,
+	0xd5, 0xfc, 0x3d, 0x7b, 0x80, 0x5a, 0x5b, 0xda, 0x80, 0x8c, 0x7d, 0x51, 0x0f, 0xd0, 0xc8, 0x16,
+	0x17, 0x6e, 0x49, 0x0e, 0x1c, 0xad, 0x7d, 0xb8, 0xd1, 0xf3, 0xbd, 0x9d, 0x0f, 0x93, 0x5b, 0x76,
+	0xf3, 0x95, 0x92, 0xbf, 0x87, 0xb8, 0xc2, 0xf0, 0xdd, 0xfc, 0xcb, 0xb8, 0x2b, 0xd1, 0x03, 0x62,
+	0xca, 0x25, 0x7f, 0x1f, 0x3e, 0xa2, 0x7e, 0xad, 0x12, 0xff, 0x42, 0x5a, 0x25, 0xe9, 0x27, 0x01,
+	0x5e, 0x6c, 0x78, 0x77, 0x7d, 0x59, 0xe2, 0x5e, 0x8a, 0xc9, 0x60, 0x8a, 0xff, 0x84, 0x9d, 0xf7,
+	0x33, 0xc7, 0x35, 0x1e, 0x0d, 0x5d, 0xa3, 0xbc, 0x4c, 0x7a, 0x0b, 0x87, 0x8d, 0x12, 0x86, 0xfd,
+	0xa8, 0x4d, 0x50, 0x85, 0x36, 0x57, 0x6f, 0xd3, 0xbd, 0xf7, 0xe6, 0x39, 0xf7, 0x58, 0x2e, 0xbd,
+	0x32, 0xcf, 0xb9, 0x57, 0xf6, 0x5a, 0xbe, 0x7f, 0x55, 0x1e, 0xa4, 0x8f, 0x70, 0xb2, 0xf9, 0x1d,
+	0xe6, 0x9d, 0xbb, 0xcf, 0x2e, 0x2f, 0xf6, 0x1f, 0xdf, 0xb8, 0xff, 0x44, 0xd4, 0xfe, 0x47, 0x70,
+	0xb8, 0x46, 0x8b, 0xd2, 0x36, 0xcc, 0x21, 0xf9, 0x81, 0x09, 0xa5, 0xb0, 0x07, 0x3c, 0xe9, 0x38,
+	0x97, 0x66, 0x97, 0x33, 0x11, 0x1a, 0xb8, 0xc9, 0xc0, 0xe5, 0x7c, 0xe0, 0x57, 0xf5, 0x41, 0xfa,
+	0x11, 0x8e, 0x37, 0x3e, 0xc2, 0x68, 0x4d, 0xe4, 0xf9, 0x3d, 0x91, 0x59, 0x8d, 0xe4, 0x32, 0xaf,
+	0x99, 0x5c, 0x5e, 0x64, 0x99, 0xd8, 0x98, 0x65, 0x32, 0x2a, 0xcb, 0x8f, 0x50, 0x8c, 0x16, 0x5f,
+	0x9f, 0x64, 0x95, 0x27, 0x59, 0xfd, 0xec, 0xc8, 0x0c, 0x27, 0xfa, 0xab, 0x00, 0x7b, 0x4b, 0x5f,
+	0x03, 0xfb, 0x0e, 0xe8, 0xb3, 0xab, 0x67, 0x8c, 0x87, 0xc4, 0xcf, 0xd1, 0x47, 0xe8, 0x14, 0xb6,
+	0xbd, 0xff, 0x1a, 0xce, 0x15, 0x19, 0x31, 0xd5, 0x2c, 0x0e, 0x9a, 0x68, 0x64, 0xd7, 0x8b, 0xf4,
+	0x5b, 0x56, 0x77, 0x1e, 0xd9, 0x0d, 0x44, 0x26, 0xbd, 0xc8, 0x6e, 0x38, 0xb2, 0xed, 0x45, 0x7a,
+	0xdb, 0x4b, 0xb7, 0xe7, 0x91, 0xed, 0x40, 0x64, 0xda, 0x8b, 0x0c, 0x98, 0xce, 0x7f, 0x11, 0x00,
+	0x16, 0x3f, 0x95, 0xa0, 0x1c, 0x64, 0x3b, 0x4a, 0x55, 0xc1, 0x5d, 0xe5, 0xaa, 0x10, 0x43, 0xcf,
+	0x60, 0x9b, 0x23, 0x55, 0x91, 0x0b, 0x02, 0xda, 0x86, 0x4c, 0x57, 0xae, 0x5f, 0x5d, 0x63, 0x5c,
+	0x88, 0xa3, 0x3c, 0x80, 0x0f, 0xe8, 0x62, 0x82, 0x62, 0xb9, 0xdb, 0xa9, 0x34, 0x5a, 0xad, 0x86,
+	0x82, 0x0b, 0x49, 0x74, 0x04, 0x07, 0x0b, 0xac, 0x56, 0x15, 0x19, 0xf7, 0x3b, 0xbd, 0xc6, 0xf5,
+	0x55, 0x21, 0x85, 0x8e, 0xe1, 0x28, 0x62, 0x41, 0xed, 0xd6, 0x2b, 0x58, 0x29, 0xa4, 0xd1, 0x4b,
+	0x10, 0x95, 0x56, 0xad, 0xd2, 0xae, 0xb4, 0x56, 0x57, 0x33, 0xe7, 0x17, 0xb0, 0x13, 0xfa, 0x99,
+	0x06, 0x6d, 0x41, 0xaa, 0xdb, 0xa8, 0xb5, 0x2b, 0x85, 0x18, 0xca, 0x40, 0xe2, 0xa1, 0xd9, 0x29,
+	0x08, 0xd4, 0xf6, 0xd0, 0xec, 0x5c, 0x37, 0x0b, 0xf1, 0xf2, 0xff, 0x20, 0xdb, 0xa1, 0x2f, 0x33,
+	0xdd, 0x1a, 0xa3, 0x12, 0x24, 0xf0, 0xcc, 0x44, 0xbb, 0x8b, 0xb7, 0x9a, 0xff, 0x53, 0xd2, 0xf3,
+	0x55, 0x93, 0x14, 0x3b, 0x13, 0xde, 0x0a, 0x83, 0x34, 0xb3, 0xbf, 0xfb, 0x73, 0x00, 0x01, 0x94,
+	0x9b, 0x58, 0x8e, 0x12, 0x00, 0x00,
 }
@@ -21,10 +21,19 @@ type CA struct {
 	privateKey      *ecdsa.PrivateKey
 }
 
-func NewCA(caName string) *CA {
-	dlog := config.LoadDLog("pseudonymsys")
-	x, y := config.LoadPseudonymsysCAPubKey(caName)
-	d := config.LoadPseudonymsysCASecret(caName)
+func NewCA(caName string) (*CA, error) {
+	dlog, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		return nil, err
+	}
+	x, y, err := config.LoadPseudonymsysCAPubKey(caName)
+	if err != nil {
+		return nil, err
+	}
+	d, err := config.LoadPseudonymsysCASecret(caName)
+	if err != nil {
+		return nil, err
+	}
 
 	c := elliptic.P256()
 	pubKey := ecdsa.PublicKey{Curve: c, X: x, Y: y}
@@ -38,7 +47,7 @@ func NewCA(caName string) *CA {
 		privateKey:      &privateKey,
 	}
 
-	return &ca
+	return &ca, nil
 }
 
 func (ca *CA) GetChallenge(a, b, x *big.Int) *big.Int {
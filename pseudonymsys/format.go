@@ -0,0 +1,170 @@
+package pseudonymsys
+
+import (
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+)
+
+// Types of encoded keys, pseudonyms and credentials (see common.MarshalVersioned).
+const (
+	OrgPubKeysType          = "pseudonymsys-org-public-key"
+	OrgSecretKeysType       = "pseudonymsys-org-secret-key"
+	CAPubKeyType            = "pseudonymsys-ca-public-key"
+	CASecretKeyType         = "pseudonymsys-ca-secret-key"
+	PseudonymType           = "pseudonymsys-pseudonym"
+	PseudonymCredentialType = "pseudonymsys-credential"
+)
+
+// CAPubKey is the public ECDSA key (a point on P-256 curve) of CA.
+type CAPubKey struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// CASecretKey is the secret ECDSA key of CA.
+type CASecretKey struct {
+	D *big.Int
+}
+
+type orgPubKeysJSON struct {
+	H1 *common.Int `json:"h1"`
+	H2 *common.Int `json:"h2"`
+}
+
+func (keys *OrgPubKeys) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(OrgPubKeysType, &orgPubKeysJSON{
+		H1: common.NewInt(keys.H1),
+		H2: common.NewInt(keys.H2),
+	})
+}
+
+func (keys *OrgPubKeys) UnmarshalJSON(data []byte) error {
+	var v orgPubKeysJSON
+	if err := common.UnmarshalVersioned(data, OrgPubKeysType, &v); err != nil {
+		return err
+	}
+	keys.H1 = v.H1.BigInt()
+	keys.H2 = v.H2.BigInt()
+	return nil
+}
+
+type orgSecretKeysJSON struct {
+	S1 *common.Int `json:"s1"`
+	S2 *common.Int `json:"s2"`
+}
+
+func (keys *OrgSecretKeys) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(OrgSecretKeysType, &orgSecretKeysJSON{
+		S1: common.NewInt(keys.S1),
+		S2: common.NewInt(keys.S2),
+	})
+}
+
+func (keys *OrgSecretKeys) UnmarshalJSON(data []byte) error {
+	var v orgSecretKeysJSON
+	if err := common.UnmarshalVersioned(data, OrgSecretKeysType, &v); err != nil {
+		return err
+	}
+	keys.S1 = v.S1.BigInt()
+	keys.S2 = v.S2.BigInt()
+	return nil
+}
+
+type caPubKeyJSON struct {
+	X *common.Int `json:"x"`
+	Y *common.Int `json:"y"`
+}
+
+func (key *CAPubKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(CAPubKeyType, &caPubKeyJSON{
+		X: common.NewInt(key.X),
+		Y: common.NewInt(key.Y),
+	})
+}
+
+func (key *CAPubKey) UnmarshalJSON(data []byte) error {
+	var v caPubKeyJSON
+	if err := common.UnmarshalVersioned(data, CAPubKeyType, &v); err != nil {
+		return err
+	}
+	key.X = v.X.BigInt()
+	key.Y = v.Y.BigInt()
+	return nil
+}
+
+type caSecretKeyJSON struct {
+	D *common.Int `json:"d"`
+}
+
+func (key *CASecretKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(CASecretKeyType, &caSecretKeyJSON{
+		D: common.NewInt(key.D),
+	})
+}
+
+func (key *CASecretKey) UnmarshalJSON(data []byte) error {
+	var v caSecretKeyJSON
+	if err := common.UnmarshalVersioned(data, CASecretKeyType, &v); err != nil {
+		return err
+	}
+	key.D = v.D.BigInt()
+	return nil
+}
+
+type pseudonymJSON struct {
+	A *common.Int `json:"a"`
+	B *common.Int `json:"b"`
+}
+
+func (nym *Pseudonym) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(PseudonymType, &pseudonymJSON{
+		A: common.NewInt(nym.A),
+		B: common.NewInt(nym.B),
+	})
+}
+
+func (nym *Pseudonym) UnmarshalJSON(data []byte) error {
+	var v pseudonymJSON
+	if err := common.UnmarshalVersioned(data, PseudonymType, &v); err != nil {
+		return err
+	}
+	nym.A = v.A.BigInt()
+	nym.B = v.B.BigInt()
+	return nil
+}
+
+type pseudonymCredentialJSON struct {
+	SmallAToGamma *common.Int   `json:"small_a_to_gamma"`
+	SmallBToGamma *common.Int   `json:"small_b_to_gamma"`
+	AToGamma      *common.Int   `json:"a_to_gamma"`
+	BToGamma      *common.Int   `json:"b_to_gamma"`
+	T1            []*common.Int `json:"t1"`
+	T2            []*common.Int `json:"t2"`
+}
+
+func (credential *PseudonymCredential) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(PseudonymCredentialType, &pseudonymCredentialJSON{
+		SmallAToGamma: common.NewInt(credential.SmallAToGamma),
+		SmallBToGamma: common.NewInt(credential.SmallBToGamma),
+		AToGamma:      common.NewInt(credential.AToGamma),
+		BToGamma:      common.NewInt(credential.BToGamma),
+		T1:            common.NewInts(credential.T1),
+		T2:            common.NewInts(credential.T2),
+	})
+}
+
+func (credential *PseudonymCredential) UnmarshalJSON(data []byte) error {
+	var v pseudonymCredentialJSON
+	if err := common.UnmarshalVersioned(data, PseudonymCredentialType, &v); err != nil {
+		return err
+	}
+	*credential = PseudonymCredential{
+		SmallAToGamma: v.SmallAToGamma.BigInt(),
+		SmallBToGamma: v.SmallBToGamma.BigInt(),
+		AToGamma:      v.AToGamma.BigInt(),
+		BToGamma:      v.BToGamma.BigInt(),
+		T1:            common.BigInts(v.T1),
+		T2:            common.BigInts(v.T2),
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package pseudonymsys
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"github.com/xlab-si/emmy/dlog"
+	"math/big"
+)
+
+// OrgSecretKeys are the secret keys of an organization, matching its OrgPubKeys
+// (h1 = g^s1, h2 = g^s2).
+type OrgSecretKeys struct {
+	S1 *big.Int
+	S2 *big.Int
+}
+
+// GenerateOrgKeys generates fresh secret keys s1, s2 of an organization and the
+// corresponding public keys h1, h2 in the given group.
+func GenerateOrgKeys(dlog *dlog.ZpDLog) (*OrgSecretKeys, *OrgPubKeys, error) {
+	s1, err := randomExponent(dlog)
+	if err != nil {
+		return nil, nil, err
+	}
+	s2, err := randomExponent(dlog)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h1, _ := dlog.ExponentiateBaseG(s1)
+	h2, _ := dlog.ExponentiateBaseG(s2)
+	return &OrgSecretKeys{S1: s1, S2: s2}, &OrgPubKeys{H1: h1, H2: h2}, nil
+}
+
+// GenerateCAKey generates a fresh ECDSA key (on P-256 curve) that CA uses to sign
+// the blinded master pseudonyms of users.
+func GenerateCAKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// randomExponent returns a random integer from [1, q), where q is the order of the group.
+func randomExponent(dlog *dlog.ZpDLog) (*big.Int, error) {
+	one := big.NewInt(1)
+	x, err := rand.Int(rand.Reader, new(big.Int).Sub(dlog.OrderOfSubgroup, one))
+	if err != nil {
+		return nil, err
+	}
+	return x.Add(x, one), nil
+}
@@ -22,9 +22,15 @@ type OrgCredentialIssuer struct {
 	b               *big.Int
 }
 
-func NewOrgCredentialIssuer(orgName string) *OrgCredentialIssuer {
-	dlog := config.LoadDLog("pseudonymsys")
-	s1, s2 := config.LoadPseudonymsysOrgSecrets(orgName)
+func NewOrgCredentialIssuer(orgName string) (*OrgCredentialIssuer, error) {
+	dlog, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		return nil, err
+	}
+	s1, s2, err := config.LoadPseudonymsysOrgSecrets(orgName)
+	if err != nil {
+		return nil, err
+	}
 
 	// g1 = a_tilde, t1 = b_tilde,
 	// g2 = a, t2 = b
@@ -40,7 +46,7 @@ func NewOrgCredentialIssuer(orgName string) *OrgCredentialIssuer {
 		EqualityProver2: equalityProver2,
 	}
 
-	return &org
+	return &org, nil
 }
 
 func (org *OrgCredentialIssuer) GetAuthenticationChallenge(a, b, x *big.Int) *big.Int {
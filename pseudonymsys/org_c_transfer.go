@@ -17,9 +17,15 @@ type OrgCredentialVerifier struct {
 	b                *big.Int
 }
 
-func NewOrgCredentialVerifier(orgName string) *OrgCredentialVerifier {
-	dlog := config.LoadDLog("pseudonymsys")
-	s1, s2 := config.LoadPseudonymsysOrgSecrets(orgName)
+func NewOrgCredentialVerifier(orgName string) (*OrgCredentialVerifier, error) {
+	dlog, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		return nil, err
+	}
+	s1, s2, err := config.LoadPseudonymsysOrgSecrets(orgName)
+	if err != nil {
+		return nil, err
+	}
 
 	equalityVerifier := dlogproofs.NewDLogEqualityVerifier(dlog)
 	org := OrgCredentialVerifier{
@@ -29,7 +35,7 @@ func NewOrgCredentialVerifier(orgName string) *OrgCredentialVerifier {
 		EqualityVerifier: equalityVerifier,
 	}
 
-	return &org
+	return &org, nil
 }
 
 func (org *OrgCredentialVerifier) GetAuthenticationChallenge(a, b, a1, b1, x1, x2 *big.Int) *big.Int {
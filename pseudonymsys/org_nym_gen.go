@@ -17,8 +17,11 @@ type OrgNymGen struct {
 	b_tilde          *big.Int
 }
 
-func NewOrgNymGen(orgName string) *OrgNymGen {
-	dlog := config.LoadDLog("pseudonymsys")
+func NewOrgNymGen(orgName string) (*OrgNymGen, error) {
+	dlog, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		return nil, err
+	}
 
 	// g1 = a_tilde, t1 = b_tilde,
 	// g2 = a, t2 = b
@@ -28,7 +31,7 @@ func NewOrgNymGen(orgName string) *OrgNymGen {
 		EqualityVerifier: verifier,
 	}
 
-	return &org
+	return &org, nil
 }
 
 func (org *OrgNymGen) GetFirstReply(a_tilde, b_tilde *big.Int) *big.Int {
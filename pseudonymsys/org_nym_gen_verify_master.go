@@ -16,19 +16,25 @@ type OrgNymGenMasterVerifier struct {
 	EqualityVerifier *dlogproofs.DLogEqualityVerifier
 }
 
-func NewOrgNymGenMasterVerifier(orgName string) *OrgNymGenMasterVerifier {
-	dlog := config.LoadDLog("pseudonymsys")
+func NewOrgNymGenMasterVerifier(orgName string) (*OrgNymGenMasterVerifier, error) {
+	dlog, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		return nil, err
+	}
 	verifier := dlogproofs.NewDLogEqualityVerifier(dlog)
 	org := OrgNymGenMasterVerifier{
 		DLog:             dlog,
 		EqualityVerifier: verifier,
 	}
-	return &org
+	return &org, nil
 }
 
 func (org *OrgNymGenMasterVerifier) GetChallenge(nymA, blindedA, nymB, blindedB,
 	x1, x2, r, s *big.Int, caName string) (*big.Int, error) {
-	x, y := config.LoadPseudonymsysCAPubKey(caName)
+	x, y, err := config.LoadPseudonymsysCAPubKey(caName)
+	if err != nil {
+		return nil, err
+	}
 	c := elliptic.P256()
 	pubKey := ecdsa.PublicKey{Curve: c, X: x, Y: y}
 
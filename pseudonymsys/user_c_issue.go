@@ -27,7 +27,10 @@ func IssueCredential(userSecret *big.Int, nym *Pseudonym,
 	gamma := common.GetRandomInt(dlog.GetOrderOfSubgroup())
 	equalityVerifier1 := dlogproofs.NewDLogEqualityBTranscriptVerifier(dlog, gamma)
 	equalityVerifier2 := dlogproofs.NewDLogEqualityBTranscriptVerifier(dlog, gamma)
-	org := NewOrgCredentialIssuer(orgName)
+	org, err := NewOrgCredentialIssuer(orgName)
+	if err != nil {
+		return nil, err
+	}
 
 	// First we need to authenticate - prove that we know dlog_a(b) where (a, b) is a nym registered
 	// with this organization. Authentication is done via Schnorr.
@@ -9,7 +9,10 @@ import (
 
 func TransferCredential(userSecret *big.Int, credential *PseudonymCredential, nym *Pseudonym,
 	orgName string, orgPubKeys *OrgPubKeys, dlog *dlog.ZpDLog) (bool, error) {
-	org := NewOrgCredentialVerifier(orgName)
+	org, err := NewOrgCredentialVerifier(orgName)
+	if err != nil {
+		return false, err
+	}
 
 	// First we need to authenticate - prove that we know dlog_a(b) where (a, b) is a nym registered
 	// with this organization. But we need also to prove that dlog_a(b) = dlog_a2(b2), where
@@ -13,7 +13,10 @@ func RegisterWithCA(caName string, userSecret *big.Int, nym Pseudonym, dlog *dlo
 	schnorrProver := dlogproofs.NewSchnorrProver(dlog, common.Sigma)
 	x := schnorrProver.GetProofRandomData(userSecret, nym.A)
 
-	ca := NewCA(caName)
+	ca, err := NewCA(caName)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
 	challenge := ca.GetChallenge(nym.A, nym.B, x)
 	z, _ := schnorrProver.GetProofData(challenge)
 
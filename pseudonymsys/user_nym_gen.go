@@ -13,11 +13,14 @@ type Pseudonym struct {
 	B *big.Int
 }
 
-func GenerateNym(userSecret *big.Int, orgName string, dlog *dlog.ZpDLog) *Pseudonym {
+func GenerateNym(userSecret *big.Int, orgName string, dlog *dlog.ZpDLog) (*Pseudonym, error) {
 	prover := dlogproofs.NewDLogEqualityProver(dlog)
 	// g1 = a_tilde, t1 = b_tilde,
 	// g2 = a, t2 = b
-	org := NewOrgNymGen(orgName)
+	org, err := NewOrgNymGen(orgName)
+	if err != nil {
+		return nil, err
+	}
 
 	gamma := common.GetRandomInt(dlog.GetOrderOfSubgroup())
 	a_tilde, _ := dlog.ExponentiateBaseG(gamma)
@@ -35,16 +38,20 @@ func GenerateNym(userSecret *big.Int, orgName string, dlog *dlog.ZpDLog) *Pseudo
 
 	if verified {
 		// todo: store in some DB: (orgName, nymA, nymB)
-		return &Pseudonym{A: a, B: b}
+		return &Pseudonym{A: a, B: b}, nil
 	} else {
-		return nil
+		err := errors.New("The proof for nym registration failed.")
+		return nil, err
 	}
 }
 
 func GenerateNymVerifyMaster(userSecret, blindedA, blindedB, r, s *big.Int,
 	orgName, caName string, dlog *dlog.ZpDLog) (*Pseudonym, error) {
 	prover := dlogproofs.NewDLogEqualityProver(dlog)
-	org := NewOrgNymGenMasterVerifier(orgName)
+	org, err := NewOrgNymGenMasterVerifier(orgName)
+	if err != nil {
+		return nil, err
+	}
 
 	gamma := common.GetRandomInt(dlog.GetOrderOfSubgroup())
 	nymA, _ := dlog.ExponentiateBaseG(gamma)
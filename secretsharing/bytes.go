@@ -0,0 +1,135 @@
+package secretsharing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// Sharing of byte secrets of any length. Unlike SplitSecret, which shares the secret as
+// a single number modulo a prime chosen just above it (revealing the length of the secret
+// and failing for short secrets), SplitBytes shares the secret in chunks of chunkSize
+// bytes modulo a fixed prime. The secret is padded first, so the shares reveal only the
+// number of chunks.
+
+// SchemeShamir256 is the ID of the scheme used by SplitBytes: Shamir's scheme modulo
+// the prime 2^256 + 297, applied to 32-byte chunks of the secret padded with 0x80 and
+// zero bytes to a multiple of 32 bytes.
+const SchemeShamir256 = "shamir-256"
+
+// chunkSize is the number of bytes of the secret shared as one number.
+const chunkSize = 32
+
+// shamir256Prime is 2^256 + 297, the smallest prime larger than 2^256.
+var shamir256Prime = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(297))
+
+// ByteShare is a share of a byte secret (see SplitBytes).
+type ByteShare struct {
+	Scheme string
+	// Id is a random ID shared by all shares of the same secret, so that shares of
+	// different secrets are not combined by mistake.
+	Id        string
+	Index     int
+	Threshold int
+	Values    []*big.Int // one value per chunk of the secret
+}
+
+// SplitBytes splits secret into numberOfShares shares with indices 1, ..., numberOfShares,
+// any threshold of which determine the secret (see CombineBytes). The shares can be
+// stored or sent in their self-describing JSON or PEM encoding (see common.MarshalPEM).
+func (dealer *Dealer) SplitBytes(secret []byte, threshold, numberOfShares int) ([]*ByteShare, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	// pad with 0x80 and zero bytes to a multiple of chunkSize
+	padded := make([]byte, (len(secret)/chunkSize+1)*chunkSize)
+	copy(padded, secret)
+	padded[len(secret)] = 0x80
+
+	shares := make([]*ByteShare, numberOfShares)
+	for i := range shares {
+		shares[i] = &ByteShare{
+			Scheme:    SchemeShamir256,
+			Id:        hex.EncodeToString(id),
+			Index:     i + 1,
+			Threshold: threshold,
+			Values:    make([]*big.Int, len(padded)/chunkSize),
+		}
+	}
+	for c := 0; c < len(padded)/chunkSize; c++ {
+		chunk := new(big.Int).SetBytes(padded[c*chunkSize : (c+1)*chunkSize])
+		chunkShares, err := dealer.SplitInt(chunk, shamir256Prime, threshold, numberOfShares)
+		if err != nil {
+			return nil, err
+		}
+		for i, share := range chunkShares {
+			shares[i].Values[c] = share.Value
+		}
+	}
+	return shares, nil
+}
+
+// CombineBytes returns the secret from its shares, together with the (sorted) indices of
+// faulty shares. As in RecoverInt, given more than threshold shares, up to
+// (len(shares) - threshold) / 2 faulty shares are corrected. An error is returned if
+// the shares are not shares of the same secret or the secret cannot be recovered.
+func (dealer *Dealer) CombineBytes(shares []*ByteShare) ([]byte, []int, error) {
+	if len(shares) == 0 || shares[0] == nil {
+		return nil, nil, errors.New("no shares")
+	}
+	first := shares[0]
+	for _, share := range shares {
+		if share == nil || share.Scheme != SchemeShamir256 {
+			return nil, nil, errors.New("unsupported secret sharing scheme")
+		}
+		if share.Id != first.Id || share.Threshold != first.Threshold ||
+			len(share.Values) != len(first.Values) || len(share.Values) == 0 {
+			return nil, nil, errors.New("shares are not shares of the same secret")
+		}
+	}
+
+	padded := make([]byte, 0, len(first.Values)*chunkSize)
+	faultyIndices := make(map[int]bool)
+	for c := range first.Values {
+		chunkShares := make([]*Share, len(shares))
+		for i, share := range shares {
+			chunkShares[i] = &Share{
+				Index: share.Index,
+				Value: share.Values[c],
+			}
+		}
+		chunk, faulty, err := dealer.RecoverInt(chunkShares, shamir256Prime, first.Threshold)
+		if err != nil {
+			return nil, nil, err
+		}
+		if chunk.BitLen() > chunkSize*8 {
+			return nil, nil, errors.New("invalid shares, the secret cannot be recovered")
+		}
+		for _, index := range faulty {
+			faultyIndices[index] = true
+		}
+		b := chunk.Bytes()
+		padded = append(padded, make([]byte, chunkSize-len(b))...)
+		padded = append(padded, b...)
+	}
+
+	// remove padding
+	end := len(padded) - 1
+	for end >= 0 && padded[end] == 0 {
+		end--
+	}
+	if end < 0 || padded[end] != 0x80 || len(padded)-end > chunkSize {
+		return nil, nil, errors.New("invalid padding, the secret cannot be recovered")
+	}
+
+	var faulty []int
+	for index := range faultyIndices {
+		faulty = append(faulty, index)
+	}
+	sort.Ints(faulty)
+	return padded[:end], faulty, nil
+}
@@ -17,6 +17,9 @@ func NewDealer() (*Dealer, error) {
 	return &dealer, nil
 }
 
+// SplitSecret splits secret into numberOfShares points, any threshold of which determine
+// the secret (see RecoverSecret). The prime is chosen just above the secret, so it reveals
+// the length of the secret; SplitBytes should be preferred.
 func (dealer *Dealer) SplitSecret(secret string, threshold int,
 	numberOfShares int) (map[*big.Int]*big.Int, *big.Int, error) {
 	if threshold < 2 {
@@ -59,10 +62,70 @@ func (dealer *Dealer) SplitSecret(secret string, threshold int,
 	return points, prime, nil
 }
 
-// It takes threshold
+// It takes threshold points. Faulty points are not detected and yield a wrong secret;
+// see RecoverInt for reconstruction that corrects them.
 func (dealer *Dealer) RecoverSecret(points map[*big.Int]*big.Int, prime *big.Int) string {
 	secretNum := common.LagrangeInterpolation(big.NewInt(0), points, prime)
 
 	b := secretNum.Bytes()
 	return string(b)
 }
+
+// Share is a share of a secret, the value of the sharing polynomial at Index. Indices
+// start at 1, as the value at 0 is the secret itself.
+type Share struct {
+	Index int
+	Value *big.Int
+}
+
+// SplitInt splits secret from Z_modulus into numberOfShares shares with indices
+// 1, ..., numberOfShares, any threshold of which determine the secret. Unlike in
+// SplitSecret, modulus is given and need not be prime, as threshold cryptosystems
+// require sharing modulo a composite (see for example encryption.NewThresholdPaillierKeys).
+func (dealer *Dealer) SplitInt(secret, modulus *big.Int, threshold,
+	numberOfShares int) ([]*Share, error) {
+	polynomial, err := newSharingPolynomial(secret, modulus, threshold, numberOfShares)
+	if err != nil {
+		return nil, err
+	}
+	return getShares(polynomial, numberOfShares), nil
+}
+
+// newSharingPolynomial returns a random polynomial of degree threshold - 1 over Z_modulus
+// with secret as the constant coefficient.
+func newSharingPolynomial(secret, modulus *big.Int, threshold,
+	numberOfShares int) (*common.Polynomial, error) {
+	if threshold < 2 {
+		err := errors.New("the threshold should be at least 2")
+		return nil, err
+	}
+	if threshold > numberOfShares {
+		err := errors.New("the threshold should be smaller than the number of shares")
+		return nil, err
+	}
+	if modulus.Cmp(big.NewInt(int64(numberOfShares))) <= 0 {
+		err := errors.New("the number of shares (participants) is too high")
+		return nil, err
+	}
+	if secret.Sign() < 0 || secret.Cmp(modulus) >= 0 {
+		err := errors.New("the secret is not from Z_modulus")
+		return nil, err
+	}
+
+	polynomial, _ := common.NewRandomPolynomial(threshold-1, modulus)
+	polynomial.SetCoefficient(0, secret)
+	return polynomial, nil
+}
+
+// getShares returns the values of polynomial at 1, ..., numberOfShares.
+func getShares(polynomial *common.Polynomial, numberOfShares int) []*Share {
+	shares := make([]*Share, numberOfShares)
+	for i := range shares {
+		index := i + 1
+		shares[i] = &Share{
+			Index: index,
+			Value: polynomial.GetValue(big.NewInt(int64(index))),
+		}
+	}
+	return shares
+}
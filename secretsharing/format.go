@@ -0,0 +1,125 @@
+package secretsharing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+)
+
+// Types of encoded commitments (see common.MarshalVersioned).
+const (
+	FeldmanCommitmentsType  = "feldman-commitments"
+	PedersenCommitmentsType = "pedersen-vss-commitments"
+	ByteShareType           = "secret-share"
+)
+
+type feldmanCommitmentsJSON struct {
+	Group  *dlog.ZpDLog  `json:"group"`
+	Values []*common.Int `json:"commitments"`
+}
+
+func (c *FeldmanCommitments) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(FeldmanCommitmentsType, &feldmanCommitmentsJSON{
+		Group:  c.DLog,
+		Values: common.NewInts(c.Values),
+	})
+}
+
+func (c *FeldmanCommitments) UnmarshalJSON(data []byte) error {
+	var v feldmanCommitmentsJSON
+	if err := common.UnmarshalVersioned(data, FeldmanCommitmentsType, &v); err != nil {
+		return err
+	}
+	*c = FeldmanCommitments{
+		DLog:   v.Group,
+		Values: common.BigInts(v.Values),
+	}
+	return nil
+}
+
+type pedersenCommitmentsJSON struct {
+	Group  *dlog.ZpDLog  `json:"group"`
+	H      *common.Int   `json:"h"`
+	Values []*common.Int `json:"commitments"`
+}
+
+func (c *PedersenCommitments) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(PedersenCommitmentsType, &pedersenCommitmentsJSON{
+		Group:  c.DLog,
+		H:      common.NewInt(c.H),
+		Values: common.NewInts(c.Values),
+	})
+}
+
+func (c *PedersenCommitments) UnmarshalJSON(data []byte) error {
+	var v pedersenCommitmentsJSON
+	if err := common.UnmarshalVersioned(data, PedersenCommitmentsType, &v); err != nil {
+		return err
+	}
+	*c = PedersenCommitments{
+		DLog:   v.Group,
+		H:      v.H.BigInt(),
+		Values: common.BigInts(v.Values),
+	}
+	return nil
+}
+
+type byteShareJSON struct {
+	Scheme    string        `json:"scheme"`
+	Id        string        `json:"id"`
+	Index     int           `json:"index"`
+	Threshold int           `json:"threshold"`
+	Values    []*common.Int `json:"values"`
+	// Checksum is the hex encoded SHA-256 hash of the share, computed with Checksum left
+	// empty. It detects accidental corruption of stored shares.
+	Checksum string `json:"checksum"`
+}
+
+// computeChecksum returns the checksum of the share, computed with Checksum left empty.
+func (v byteShareJSON) computeChecksum() (string, error) {
+	v.Checksum = ""
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (share *ByteShare) MarshalJSON() ([]byte, error) {
+	v := &byteShareJSON{
+		Scheme:    share.Scheme,
+		Id:        share.Id,
+		Index:     share.Index,
+		Threshold: share.Threshold,
+		Values:    common.NewInts(share.Values),
+	}
+	checksum, err := v.computeChecksum()
+	if err != nil {
+		return nil, err
+	}
+	v.Checksum = checksum
+	return common.MarshalVersioned(ByteShareType, v)
+}
+
+func (share *ByteShare) UnmarshalJSON(data []byte) error {
+	var v byteShareJSON
+	if err := common.UnmarshalVersioned(data, ByteShareType, &v); err != nil {
+		return err
+	}
+	checksum, err := v.computeChecksum()
+	if err != nil || checksum != v.Checksum {
+		return errors.New("Invalid encoding of secret-share: checksum mismatch")
+	}
+	*share = ByteShare{
+		Scheme:    v.Scheme,
+		Id:        v.Id,
+		Index:     v.Index,
+		Threshold: v.Threshold,
+		Values:    common.BigInts(v.Values),
+	}
+	return nil
+}
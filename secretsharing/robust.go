@@ -0,0 +1,249 @@
+package secretsharing
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xlab-si/emmy/common"
+	"math/big"
+	"sort"
+)
+
+// Robust reconstruction of secrets. Interpolation (as in RecoverSecret) from shares one
+// of which is corrupted silently yields a wrong secret. Given more than threshold shares,
+// RecoverInt decodes the sharing polynomial with the Berlekamp-Welch algorithm, which
+// corrects up to (len(shares) - threshold) / 2 faulty shares, and reports which shares
+// were faulty. With verifiable secret sharing, faulty shares are instead detected by
+// checking them against the dealer's commitments (see FeldmanCommitments.Recover and
+// PedersenCommitments.Recover), and only threshold valid shares are needed.
+
+// RecoverInt returns the secret from Z_prime shared with threshold (see SplitInt, which
+// must have used a prime modulus), together with the (sorted) indices of faulty shares.
+// Up to (len(shares) - threshold) / 2 faulty shares are corrected. An error is returned
+// if there are more, as far as it can be detected, or if shares are invalid.
+func (dealer *Dealer) RecoverInt(shares []*Share, prime *big.Int, threshold int) (*big.Int,
+	[]int, error) {
+	if threshold < 1 {
+		return nil, nil, errors.New("the threshold should be at least 1")
+	}
+	if len(shares) < threshold {
+		return nil, nil, fmt.Errorf("%d shares, %d needed", len(shares), threshold)
+	}
+	seen := make(map[int]bool)
+	for _, share := range shares {
+		if share == nil || share.Value == nil || share.Index < 1 {
+			return nil, nil, errors.New("invalid share")
+		}
+		if seen[share.Index] {
+			return nil, nil, fmt.Errorf("repeated share with index %d", share.Index)
+		}
+		seen[share.Index] = true
+	}
+
+	maxErrors := (len(shares) - threshold) / 2
+	polynomial, err := berlekampWelch(shares, prime, threshold, maxErrors)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var faulty []int
+	for _, share := range shares {
+		if evaluate(polynomial, big.NewInt(int64(share.Index)), prime).Cmp(
+			new(big.Int).Mod(share.Value, prime)) != 0 {
+			faulty = append(faulty, share.Index)
+		}
+	}
+	if len(faulty) > maxErrors {
+		return nil, nil, fmt.Errorf("too many faulty shares, at most %d can be corrected", maxErrors)
+	}
+	sort.Ints(faulty)
+	return polynomial[0], faulty, nil
+}
+
+// Recover returns the secret from shares, together with the (sorted) indices of shares
+// that are not consistent with the commitments. An error is returned if there are less
+// than threshold valid shares from distinct shareholders.
+func (c *FeldmanCommitments) Recover(shares []*Share) (*big.Int, []int, error) {
+	valid, faulty := filterShares(shares, c.Verify)
+	return recoverValid(valid, faulty, c.DLog.GetOrderOfSubgroup(), c.Threshold())
+}
+
+// Recover returns the secret from shares, together with the (sorted) indices of shares
+// that are not consistent with the commitments. An error is returned if there are less
+// than threshold valid shares from distinct shareholders.
+func (c *PedersenCommitments) Recover(shares []*PedersenShare) (*big.Int, []int, error) {
+	plain := make([]*Share, len(shares))
+	byIndex := make(map[*Share]*PedersenShare, len(shares))
+	for i, share := range shares {
+		if share != nil {
+			plain[i] = &Share{Index: share.Index, Value: share.Value}
+			byIndex[plain[i]] = share
+		}
+	}
+	valid, faulty := filterShares(plain, func(share *Share) bool {
+		return c.Verify(byIndex[share])
+	})
+	return recoverValid(valid, faulty, c.DLog.GetOrderOfSubgroup(), c.Threshold())
+}
+
+// filterShares returns the shares that pass verify, and the (sorted) indices of the ones
+// that don't. Of valid shares with the same index only the first is returned, while an
+// invalid share does not hide a valid share with the same index.
+func filterShares(shares []*Share, verify func(*Share) bool) ([]*Share, []int) {
+	var valid []*Share
+	var faulty []int
+	accepted := make(map[int]bool)
+	reported := make(map[int]bool)
+	for _, share := range shares {
+		if share == nil || accepted[share.Index] {
+			continue
+		}
+		if verify(share) {
+			accepted[share.Index] = true
+			valid = append(valid, share)
+		} else if !reported[share.Index] {
+			reported[share.Index] = true
+			faulty = append(faulty, share.Index)
+		}
+	}
+	sort.Ints(faulty)
+	return valid, faulty
+}
+
+// recoverValid interpolates the secret from threshold valid shares.
+func recoverValid(valid []*Share, faulty []int, prime *big.Int, threshold int) (*big.Int,
+	[]int, error) {
+	if len(valid) < threshold {
+		return nil, faulty, fmt.Errorf("%d valid shares, %d needed", len(valid), threshold)
+	}
+	points := make(map[*big.Int]*big.Int, threshold)
+	for _, share := range valid[:threshold] {
+		points[big.NewInt(int64(share.Index))] = share.Value
+	}
+	return common.LagrangeInterpolation(big.NewInt(0), points, prime), faulty, nil
+}
+
+// berlekampWelch returns the coefficients of the polynomial of degree less than threshold
+// that agrees with all but at most maxErrors shares. It finds the error locator E (monic,
+// of degree maxErrors) and Q = P * E of degree less than maxErrors + threshold from the
+// linear equations Q(x_i) = y_i * E(x_i), and returns P = Q / E.
+func berlekampWelch(shares []*Share, prime *big.Int, threshold, maxErrors int) ([]*big.Int, error) {
+	qLen := maxErrors + threshold
+	columns := qLen + maxErrors
+	matrix := make([][]*big.Int, len(shares))
+	for i, share := range shares {
+		x := big.NewInt(int64(share.Index))
+		y := new(big.Int).Mod(share.Value, prime)
+		row := make([]*big.Int, columns+1)
+		power := big.NewInt(1) // x^j
+		for j := 0; j < qLen; j++ {
+			row[j] = new(big.Int).Set(power)
+			if j < maxErrors {
+				t := new(big.Int).Mul(y, power)
+				row[qLen+j] = t.Neg(t).Mod(t, prime)
+			}
+			if j == maxErrors { // y_i * x_i^maxErrors, as E is monic
+				row[columns] = new(big.Int).Mul(y, power)
+				row[columns].Mod(row[columns], prime)
+			}
+			power = new(big.Int).Mul(power, x)
+			power.Mod(power, prime)
+		}
+		matrix[i] = row
+	}
+
+	solution, err := solveLinearSystem(matrix, columns, prime)
+	if err != nil {
+		return nil, errors.New("too many faulty shares, the secret cannot be recovered")
+	}
+	q := solution[:qLen]
+	e := append(solution[qLen:], big.NewInt(1))
+
+	p, remainder := dividePolynomials(q, e, prime)
+	for _, coefficient := range remainder {
+		if coefficient.Sign() != 0 {
+			return nil, errors.New("too many faulty shares, the secret cannot be recovered")
+		}
+	}
+	for len(p) < threshold {
+		p = append(p, big.NewInt(0))
+	}
+	return p[:threshold], nil
+}
+
+// solveLinearSystem returns a solution of the linear system over Z_prime with the given
+// augmented matrix (columns unknowns, the last column holding the right-hand side). Free
+// unknowns are set to 0. An error is returned if the system has no solution.
+func solveLinearSystem(matrix [][]*big.Int, columns int, prime *big.Int) ([]*big.Int, error) {
+	pivotColumns := make([]int, 0, columns)
+	row := 0
+	for col := 0; col < columns && row < len(matrix); col++ {
+		pivot := -1
+		for i := row; i < len(matrix); i++ {
+			if matrix[i][col].Sign() != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		matrix[row], matrix[pivot] = matrix[pivot], matrix[row]
+		inv := new(big.Int).ModInverse(matrix[row][col], prime)
+		for j := col; j <= columns; j++ {
+			matrix[row][j].Mul(matrix[row][j], inv).Mod(matrix[row][j], prime)
+		}
+		for i := range matrix {
+			if i == row || matrix[i][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Int).Set(matrix[i][col])
+			for j := col; j <= columns; j++ {
+				t := new(big.Int).Mul(factor, matrix[row][j])
+				matrix[i][j].Sub(matrix[i][j], t).Mod(matrix[i][j], prime)
+			}
+		}
+		pivotColumns = append(pivotColumns, col)
+		row++
+	}
+	for i := row; i < len(matrix); i++ {
+		if matrix[i][columns].Sign() != 0 {
+			return nil, errors.New("the system has no solution")
+		}
+	}
+
+	solution := make([]*big.Int, columns)
+	for j := range solution {
+		solution[j] = big.NewInt(0)
+	}
+	for i, col := range pivotColumns {
+		solution[col] = matrix[i][columns]
+	}
+	return solution, nil
+}
+
+// dividePolynomials returns the quotient and the remainder of a divided by monic b, with
+// coefficients from Z_prime given from the lowest degree on.
+func dividePolynomials(a, b []*big.Int, prime *big.Int) ([]*big.Int, []*big.Int) {
+	remainder := make([]*big.Int, len(a))
+	for i, c := range a {
+		remainder[i] = new(big.Int).Set(c)
+	}
+	degB := len(b) - 1
+	if len(a) <= degB {
+		return nil, remainder
+	}
+	quotient := make([]*big.Int, len(a)-degB)
+	for i := len(quotient) - 1; i >= 0; i-- {
+		quotient[i] = new(big.Int).Set(remainder[i+degB])
+		for j, c := range b {
+			t := new(big.Int).Mul(quotient[i], c)
+			remainder[i+j].Sub(remainder[i+j], t).Mod(remainder[i+j], prime)
+		}
+	}
+	return quotient, remainder[:degB]
+}
+
+// evaluate returns the value of the polynomial with the given coefficients at x.
+func evaluate(coefficients []*big.Int, x, prime *big.Int) *big.Int {
+	return common.NewPolynomial(coefficients, prime).GetValue(x)
+}
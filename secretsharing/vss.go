@@ -0,0 +1,188 @@
+package secretsharing
+
+import (
+	"github.com/xlab-si/emmy/commitments"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"math/big"
+)
+
+// Verifiable secret sharing. With plain Shamir shares (see SplitInt), shareholders cannot
+// check that their shares are consistent, so a cheating dealer goes undetected. In
+// verifiable schemes, the dealer also broadcasts commitments to the coefficients of the
+// sharing polynomial, and each shareholder checks its share against them. The secret is
+// from Z_q, where q is the order of the group the commitments are computed in.
+//
+// Feldman's scheme (A Practical Scheme for Non-interactive Verifiable Secret Sharing,
+// FOCS 1987) commits to coefficient a_j as g^a_j, which reveals g^secret. Pedersen's
+// scheme (Non-Interactive and Information-Theoretic Secure Verifiable Secret Sharing,
+// CRYPTO 1991) uses Pedersen commitments g^a_j * h^b_j instead, so the commitments
+// reveal nothing about the secret.
+
+// FeldmanCommitments are commitments g^a_j to the coefficients a_0, ..., a_{threshold-1}
+// of the sharing polynomial.
+type FeldmanCommitments struct {
+	DLog   *dlog.ZpDLog
+	Values []*big.Int
+}
+
+// PedersenCommitments are Pedersen commitments g^a_j * h^b_j to the coefficients
+// a_0, ..., a_{threshold-1} of the sharing polynomial, where b_j are the coefficients
+// of the blinding polynomial.
+type PedersenCommitments struct {
+	DLog   *dlog.ZpDLog
+	H      *big.Int
+	Values []*big.Int
+}
+
+// PedersenShare is a share of a secret shared with Pedersen's scheme: the values of the
+// sharing and of the blinding polynomial at Index.
+type PedersenShare struct {
+	Index    int
+	Value    *big.Int
+	Blinding *big.Int
+}
+
+// SplitFeldman splits secret from Z_q, where q is the order of group, into
+// numberOfShares shares with indices 1, ..., numberOfShares, any threshold of which
+// determine the secret, and returns them together with the commitments that the dealer
+// should broadcast to all shareholders.
+func (dealer *Dealer) SplitFeldman(secret *big.Int, group *dlog.ZpDLog, threshold,
+	numberOfShares int) ([]*Share, *FeldmanCommitments, error) {
+	q := group.GetOrderOfSubgroup()
+	polynomial, err := newSharingPolynomial(secret, q, threshold, numberOfShares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]*big.Int, threshold)
+	for j := range values {
+		values[j], _ = group.ExponentiateBaseG(polynomial.GetCoefficient(j))
+	}
+	return getShares(polynomial, numberOfShares), &FeldmanCommitments{
+		DLog:   group,
+		Values: values,
+	}, nil
+}
+
+// SplitPedersen splits secret from Z_q, where q is the order of group, into
+// numberOfShares shares with indices 1, ..., numberOfShares, any threshold of which
+// determine the secret, and returns them together with the commitments that the dealer
+// should broadcast to all shareholders. Nobody, in particular not the dealer, may know
+// log_g(h), as whoever knows it can open the commitments to other polynomials. For
+// example, h can be generated by a shareholder with commitments.NewPedersenReceiver.
+func (dealer *Dealer) SplitPedersen(secret *big.Int, group *dlog.ZpDLog, h *big.Int, threshold,
+	numberOfShares int) ([]*PedersenShare, *PedersenCommitments, error) {
+	q := group.GetOrderOfSubgroup()
+	polynomial, err := newSharingPolynomial(secret, q, threshold, numberOfShares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// commit to the coefficients, which chooses the coefficients of the blinding polynomial
+	committer := commitments.NewPedersenCommitter(group)
+	committer.SetH(h)
+	values := make([]*big.Int, threshold)
+	blindingCoefficients := make([]*big.Int, threshold)
+	for j := range values {
+		c, err := committer.GetCommitMsg(polynomial.GetCoefficient(j))
+		if err != nil {
+			return nil, nil, err
+		}
+		values[j] = c
+		_, blindingCoefficients[j] = committer.GetDecommitMsg()
+	}
+	blinding := common.NewPolynomial(blindingCoefficients, q)
+
+	shares := make([]*PedersenShare, numberOfShares)
+	for i, share := range getShares(polynomial, numberOfShares) {
+		shares[i] = &PedersenShare{
+			Index:    share.Index,
+			Value:    share.Value,
+			Blinding: blinding.GetValue(big.NewInt(int64(share.Index))),
+		}
+	}
+	return shares, &PedersenCommitments{
+		DLog:   group,
+		H:      h,
+		Values: values,
+	}, nil
+}
+
+// Verify returns true if share is consistent with the commitments, that is if
+// g^share = prod (g^a_j)^(index^j).
+func (c *FeldmanCommitments) Verify(share *Share) bool {
+	if share == nil || share.Index < 1 || share.Value == nil || !c.isValid() ||
+		!isShareValue(share.Value, c.DLog.GetOrderOfSubgroup()) {
+		return false
+	}
+	left, _ := c.DLog.ExponentiateBaseG(share.Value)
+	right := evaluateInExponent(c.DLog, c.Values, share.Index)
+	return left.Cmp(right) == 0
+}
+
+// Verify returns true if share is consistent with the commitments, that is if
+// g^share * h^blinding = prod (g^a_j * h^b_j)^(index^j).
+func (c *PedersenCommitments) Verify(share *PedersenShare) bool {
+	if share == nil || share.Index < 1 || share.Value == nil || share.Blinding == nil || !c.isValid() ||
+		!isShareValue(share.Value, c.DLog.GetOrderOfSubgroup()) ||
+		!isShareValue(share.Blinding, c.DLog.GetOrderOfSubgroup()) {
+		return false
+	}
+	t1, _ := c.DLog.ExponentiateBaseG(share.Value)
+	t2, _ := c.DLog.Exponentiate(c.H, share.Blinding)
+	left, _ := c.DLog.Multiply(t1, t2)
+	right := evaluateInExponent(c.DLog, c.Values, share.Index)
+	return left.Cmp(right) == 0
+}
+
+// Threshold returns the number of shares needed to determine the secret.
+func (c *FeldmanCommitments) Threshold() int {
+	return len(c.Values)
+}
+
+// Threshold returns the number of shares needed to determine the secret.
+func (c *PedersenCommitments) Threshold() int {
+	return len(c.Values)
+}
+
+// isValid returns true if the commitments are elements of the group.
+func (c *FeldmanCommitments) isValid() bool {
+	return c.DLog != nil && len(c.Values) > 0 && areElements(c.DLog, c.Values...)
+}
+
+// isValid returns true if h and the commitments are elements of the group.
+func (c *PedersenCommitments) isValid() bool {
+	return c.DLog != nil && len(c.Values) > 0 && c.H != nil && areElements(c.DLog, c.H) &&
+		areElements(c.DLog, c.Values...)
+}
+
+// evaluateInExponent returns prod values[j]^(index^j), computed with Horner's rule.
+func evaluateInExponent(group *dlog.ZpDLog, values []*big.Int, index int) *big.Int {
+	x := big.NewInt(int64(index))
+	result := big.NewInt(1)
+	for j := len(values) - 1; j >= 0; j-- {
+		result, _ = group.Exponentiate(result, x)
+		result, _ = group.Multiply(result, values[j])
+	}
+	return result
+}
+
+// areElements returns true if all xs are elements of the subgroup of order q.
+func areElements(group *dlog.ZpDLog, xs ...*big.Int) bool {
+	for _, x := range xs {
+		if x == nil || x.Sign() <= 0 || x.Cmp(group.P) >= 0 {
+			return false
+		}
+		t, _ := group.Exponentiate(x, group.GetOrderOfSubgroup())
+		if t.Cmp(big.NewInt(1)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isShareValue returns true if x is from Z_q.
+func isShareValue(x, q *big.Int) bool {
+	return x.Sign() >= 0 && x.Cmp(q) < 0
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net/http"
+	"strings"
+)
+
+// AuthorizationKey is the gRPC metadata key (and HTTP header) where clients present
+// the token of their tenant, as "Bearer <token>".
+const AuthorizationKey = "authorization"
+
+const bearerPrefix = "Bearer "
+
+// ErrInvalidToken is returned when a client presents a token of no configured tenant.
+var ErrInvalidToken = errors.New("Invalid tenant token")
+
+// authenticateGRPC returns the tenant of the client of a gRPC stream with context ctx.
+func (s *Server) authenticateGRPC(ctx context.Context) (*Tenant, error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[AuthorizationKey]) > 0 {
+		authorization = md[AuthorizationKey][0]
+	}
+
+	var tlsState *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			tlsState = &tlsInfo.State
+		}
+	}
+
+	return s.authenticate(authorization, tlsState)
+}
+
+// AuthenticateHTTP returns the tenant of the client that sent the HTTP request r.
+func (s *Server) AuthenticateHTTP(r *http.Request) (*Tenant, error) {
+	return s.authenticate(r.Header.Get(AuthorizationKey), r.TLS)
+}
+
+// authenticate returns the tenant of a client, which presented the given authorization
+// (possibly empty) and connected over a TLS connection with the given state (nil if the
+// connection is not secured with TLS).
+//
+// A client presenting a token is authenticated as the tenant that the token belongs to,
+// or rejected if the token is invalid. Otherwise a client with a verified TLS certificate
+// is authenticated as the tenant that the name in the certificate belongs to. Remaining
+// clients are served by the default tenant.
+func (s *Server) authenticate(authorization string, tlsState *tls.ConnectionState) (*Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if authorization != "" {
+		if !strings.HasPrefix(authorization, bearerPrefix) {
+			return nil, ErrInvalidToken
+		}
+		hash := sha256.Sum256([]byte(strings.TrimPrefix(authorization, bearerPrefix)))
+		tokenHash := hex.EncodeToString(hash[:])
+		for _, tenant := range s.tenants {
+			if tenant.tokenHashes[tokenHash] {
+				return tenant, nil
+			}
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if tlsState != nil && len(tlsState.VerifiedChains) > 0 && len(tlsState.VerifiedChains[0]) > 0 {
+		cert := tlsState.VerifiedChains[0][0]
+		names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+		for _, tenant := range s.tenants {
+			for _, name := range names {
+				if tenant.tlsNames[strings.ToLower(name)] {
+					return tenant, nil
+				}
+			}
+		}
+	}
+
+	return s.tenants[DefaultTenantId], nil
+}
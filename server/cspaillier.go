@@ -1,18 +1,47 @@
 package server
 
 import (
+	"errors"
 	"github.com/xlab-si/emmy/encryption"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"io"
 	"math/big"
 )
 
-func (s *Server) CSPaillier(req *pb.Message, secKeyPath string, stream pb.Protocol_RunServer) error {
-	decryptor, err := encryption.NewCSPaillierFromSecKey(secKeyPath)
-	if err != nil {
-		return err
+// CSPaillier verifies the client's proof that it encrypted a discrete logarithm under
+// the given public key. Only the public key is needed, so the server can run the
+// protocol without having access to the secret key.
+func (s *Server) CSPaillier(req *pb.Message, pubKey *encryption.CSPaillierPubKey,
+	t transport.Transport) error {
+	// Clients that don't have the public key yet first ask for it with an empty message.
+	if req.GetEmpty() != nil {
+		resp := &pb.Message{
+			KeyId: req.GetKeyId(),
+			Content: &pb.Message_CsPaillierPubKey{
+				encryption.ToPbCSPaillierPubKey(pubKey),
+			},
+		}
+		if err := s.send(resp, t); err != nil {
+			return err
+		}
+
+		var err error
+		req, err = s.receive(t)
+		if err == io.EOF {
+			// the client only fetched the public key
+			return nil
+		} else if err != nil {
+			return err
+		}
 	}
 
+	verifier := encryption.NewCSPaillierFromPubKey(pubKey)
+
 	opening := req.GetCsPaillierOpening()
+	if opening == nil {
+		return errors.New("Expected CSPaillier opening message")
+	}
 
 	u := new(big.Int).SetBytes(opening.U)
 	e := new(big.Int).SetBytes(opening.E)
@@ -21,17 +50,17 @@ func (s *Server) CSPaillier(req *pb.Message, secKeyPath string, stream pb.Protoc
 	label := new(big.Int).SetBytes(opening.Label)
 	l := new(big.Int).SetBytes(opening.L)
 
-	decryptor.SetVerifierEncData(u, e, v, delta, label, l)
+	verifier.SetVerifierEncData(u, e, v, delta, label, l)
 
 	resp := &pb.Message{
 		Content: &pb.Message_Empty{&pb.EmptyMsg{}},
 	}
 
-	if err = s.send(resp, stream); err != nil {
+	if err := s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err = s.receive(stream)
+	req, err := s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -44,8 +73,8 @@ func (s *Server) CSPaillier(req *pb.Message, secKeyPath string, stream pb.Protoc
 	delta1 := new(big.Int).SetBytes(pRandData.Delta1)
 	l1 := new(big.Int).SetBytes(pRandData.L1)
 
-	c := decryptor.GetChallenge()
-	decryptor.SetProofRandomData(u1, e1, v1, delta1, l1, c)
+	c := verifier.GetChallenge()
+	verifier.SetProofRandomData(u1, e1, v1, delta1, l1, c)
 
 	challenge := pb.BigInt{
 		X1: c.Bytes(),
@@ -54,11 +83,11 @@ func (s *Server) CSPaillier(req *pb.Message, secKeyPath string, stream pb.Protoc
 		Content: &pb.Message_Bigint{&challenge},
 	}
 
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err = s.receive(stream)
+	req, err = s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -80,16 +109,10 @@ func (s *Server) CSPaillier(req *pb.Message, secKeyPath string, stream pb.Protoc
 		mTilde = new(big.Int).Neg(mTilde)
 	}
 
-	isOk := decryptor.Verify(rTilde, sTilde, mTilde)
-	status := pb.Status{
-		Success: isOk,
-	}
-
-	resp = &pb.Message{
-		Content: &pb.Message_Status{&status},
-	}
+	isOk := verifier.Verify(rTilde, sTilde, mTilde)
+	resp = newStatusMsg(isOk, "Verifiable encryption proof is not valid")
 
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
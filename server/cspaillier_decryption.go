@@ -0,0 +1,47 @@
+package server
+
+import (
+	"errors"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"github.com/xlab-si/emmy/trustee"
+	"math/big"
+)
+
+// CSPaillierDecryption decrypts the CSPaillier ciphertext sent by the client on behalf
+// of the given requester, if the trustee's policy allows it, and sends the plaintext
+// together with a proof of correct decryption. If the request is not granted, the client
+// receives a status message with the reason.
+func (s *Server) CSPaillierDecryption(req *pb.Message, tr *trustee.Trustee, requester string,
+	t transport.Transport) error {
+	r := req.GetCsPaillierDecryptionRequest()
+	if r == nil {
+		return errors.New("Expected CSPaillier decryption request")
+	}
+
+	m, proof, err := tr.Decrypt(&trustee.Request{
+		Requester:     requester,
+		KeyId:         req.GetKeyId(),
+		U:             new(big.Int).SetBytes(r.U),
+		E:             new(big.Int).SetBytes(r.E),
+		V:             new(big.Int).SetBytes(r.V),
+		Label:         new(big.Int).SetBytes(r.Label),
+		Justification: r.Justification,
+	})
+	if err != nil {
+		logger.Noticef("Decryption request of %q was not granted: %v", requester, err)
+		return s.send(newStatusMsg(false, err.Error()), t)
+	}
+
+	resp := &pb.Message{
+		Content: &pb.Message_CsPaillierDecryption{
+			&pb.CSPaillierDecryption{
+				M:  m.Bytes(),
+				T1: proof.T1.Bytes(),
+				T2: proof.T2.Bytes(),
+				Z:  proof.Z.Bytes(),
+			},
+		},
+	}
+	return s.send(resp, t)
+}
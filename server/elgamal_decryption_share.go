@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"github.com/xlab-si/emmy/trustee"
+	"math/big"
+)
+
+// ElGamalDecryptionShare sends the trustee's decryption share of the threshold ElGamal
+// ciphertext sent by the client on behalf of the given requester, if the trustee's
+// policy allows it, together with a proof of its correctness. If the request is not
+// granted, the client receives a status message with the reason.
+func (s *Server) ElGamalDecryptionShare(req *pb.Message, tr *trustee.Trustee, requester string,
+	t transport.Transport) error {
+	r := req.GetElGamalDecryptionShareRequest()
+	if r == nil {
+		return errors.New("Expected ElGamal decryption share request")
+	}
+
+	share, err := tr.DecryptElGamalShare(&trustee.ElGamalShareRequest{
+		Requester: requester,
+		C: &encryption.ElGamalCiphertext{
+			C1: new(big.Int).SetBytes(r.C1),
+			C2: new(big.Int).SetBytes(r.C2),
+		},
+		Label:         new(big.Int).SetBytes(r.Label),
+		Justification: r.Justification,
+	})
+	if err != nil {
+		logger.Noticef("ElGamal decryption share request of %q was not granted: %v", requester, err)
+		return s.send(newStatusMsg(false, err.Error()), t)
+	}
+
+	resp := &pb.Message{
+		Content: &pb.Message_ElGamalDecryptionShare{
+			&pb.ElGamalDecryptionShare{
+				Index: int32(share.Index),
+				D:     share.D.Bytes(),
+				X1:    share.Proof.X1.Bytes(),
+				X2:    share.Proof.X2.Bytes(),
+				Z:     share.Proof.Z.Bytes(),
+			},
+		},
+	}
+	return s.send(resp, t)
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"time"
+)
+
+// ProtocolServiceName is the name under which the serving status of emmy's protocol
+// service is reported by the health service.
+const ProtocolServiceName = "protobuf.Protocol"
+
+// NewHealthServer returns a gRPC health server (grpc.health.v1) with the serving status
+// set according to the current readiness of emmy server s.
+func NewHealthServer(s *Server) *health.Server {
+	healthServer := health.NewServer()
+	UpdateHealthStatus(healthServer, s)
+	return healthServer
+}
+
+// RegisterHealthAndReflection registers the standard health service, reporting the
+// readiness of emmy server s, and server reflection on grpcServer. It returns the health
+// server, so that its status can be kept up to date with MonitorHealth.
+func RegisterHealthAndReflection(grpcServer *grpc.Server, s *Server) *health.Server {
+	healthServer := NewHealthServer(s)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+	return healthServer
+}
+
+// UpdateHealthStatus checks whether emmy server s is ready to serve clients and sets the
+// serving status of the overall server and of the protocol service accordingly.
+func UpdateHealthStatus(healthServer *health.Server, s *Server) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if err := s.CheckReadiness(); err != nil {
+		logger.Warningf("Emmy server is not ready: %v", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	healthServer.SetServingStatus("", status)
+	healthServer.SetServingStatus(ProtocolServiceName, status)
+}
+
+// MonitorHealth periodically re-evaluates readiness of emmy server s, so that the health
+// service reflects changes of the configuration and reloaded keys without restarting the server.
+func MonitorHealth(healthServer *health.Server, s *Server, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		UpdateHealthStatus(healthServer, s)
+	}
+}
+
+// CheckReadiness returns an error if, for any of the tenants, the group parameters needed
+// for running the protocols allowed to the tenant cannot be loaded, or if the tenant is
+// allowed to run CSPaillier but holds no CSPaillier keys.
+func (s *Server) CheckReadiness() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, tenant := range s.tenants {
+		if err := checkTenant(tenant); err != nil {
+			if tenant.Id == DefaultTenantId {
+				return err
+			}
+			return fmt.Errorf("tenant %q: %v", tenant.Id, err)
+		}
+	}
+	return nil
+}
+
+// checkTenant verifies that the group parameters and keys of the given tenant are present.
+func checkTenant(tenant *Tenant) error {
+	schemes := map[string]pb.SchemaType{
+		"pedersen": pb.SchemaType_PEDERSEN,
+		"schnorr":  pb.SchemaType_SCHNORR,
+	}
+	for scheme, schema := range schemes {
+		if !tenant.Allows(schema) {
+			continue
+		}
+		if _, err := tenant.DLog(scheme); err != nil {
+			return fmt.Errorf("cannot load %v group parameters: %v", scheme, err)
+		}
+	}
+
+	if tenant.Allows(pb.SchemaType_CSPAILLIER) && len(tenant.keys.KeyIds()) == 0 {
+		return fmt.Errorf("no CSPaillier keys loaded from %v", tenant.keys.Dir())
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/encryption"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Keys are stored in the key folder in files named cspaillierseckey-<key ID> (secret keys)
+// and cspaillierpubkey-<key ID> (public keys) with extension .txt, .json or .pem (see
+// encryption.NewCSPaillierFromSecKey and encryption.NewCSPaillierFromPubKeyFile). The key
+// in cspaillierseckey.txt (or cspaillierpubkey.txt) has an empty key ID and is used for
+// clients that don't state the ID of the key. A key is retired by appending .retired to
+// the name of its file.
+const (
+	cspaillierSecKeyPrefix = "cspaillierseckey"
+	cspaillierPubKeyPrefix = "cspaillierpubkey"
+	retiredSuffix          = ".retired"
+)
+
+var keyFileExtensions = []string{".txt", ".json", ".pem"}
+
+// KeyStore holds CSPaillier keys of emmy server, identified by key IDs. Several keys can
+// be active at once, so that keys can be rotated without interrupting clients still using
+// the old ones. Keys are read from the key folder by Reload, which can be called at any
+// time to pick up added, removed or retired keys.
+//
+// Verifying proofs of verifiable encryption needs only public keys, so a server that is
+// not supposed to decrypt can be given public keys alone. Secret keys are loaded only
+// if they are present in the key folder.
+type KeyStore struct {
+	mu         sync.RWMutex
+	dir        string
+	pubKeys    map[string]*encryption.CSPaillierPubKey
+	secretKeys map[string]*encryption.CSPaillierSecretKey
+	retired    map[string]bool
+}
+
+// NewKeyStore returns an empty KeyStore for keys in the given folder. Keys are loaded
+// with Reload.
+func NewKeyStore(keyDir string) *KeyStore {
+	return &KeyStore{
+		dir:        keyDir,
+		pubKeys:    map[string]*encryption.CSPaillierPubKey{},
+		secretKeys: map[string]*encryption.CSPaillierSecretKey{},
+		retired:    map[string]bool{},
+	}
+}
+
+// Dir returns the folder holding the keys.
+func (ks *KeyStore) Dir() string {
+	return ks.dir
+}
+
+// Reload replaces the keys held by the key store with the keys currently in the key
+// folder. Keys that fail to load are left out and reported in the returned error,
+// while the rest of the keys are loaded anyway.
+func (ks *KeyStore) Reload() error {
+	keyDir := ks.dir
+	files, err := ioutil.ReadDir(keyDir)
+	if err != nil {
+		return fmt.Errorf("Cannot read key folder %v: %v", keyDir, err)
+	}
+	// secret keys are loaded first, so that public keys derived from them take
+	// precedence over the ones stored separately
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.HasPrefix(files[i].Name(), cspaillierSecKeyPrefix) &&
+			!strings.HasPrefix(files[j].Name(), cspaillierSecKeyPrefix)
+	})
+
+	pubKeys := map[string]*encryption.CSPaillierPubKey{}
+	secretKeys := map[string]*encryption.CSPaillierSecretKey{}
+	retired := map[string]bool{}
+	var problems []string
+	for _, file := range files {
+		keyId, secret, isRetired, ok := parseKeyFileName(file.Name())
+		if !ok || file.IsDir() {
+			continue
+		}
+		if isRetired {
+			retired[keyId] = true
+			continue
+		}
+
+		path := filepath.Join(keyDir, file.Name())
+		if !secret {
+			if _, exists := pubKeys[keyId]; exists {
+				continue
+			}
+			cspaillier, err := encryption.NewCSPaillierFromPubKeyFile(path)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%v: %v", path, err))
+				continue
+			}
+			pubKeys[keyId] = cspaillier.PubKey
+			continue
+		}
+
+		cspaillier, err := encryption.NewCSPaillierFromSecKey(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", path, err))
+			continue
+		}
+		if _, exists := secretKeys[keyId]; exists {
+			problems = append(problems, fmt.Sprintf("%v: duplicate key ID %q", path, keyId))
+			continue
+		}
+		secretKeys[keyId] = cspaillier.SecretKey
+		pubKeys[keyId] = cspaillier.PubKey
+	}
+	// retiring either part of a key retires the whole key
+	for keyId := range retired {
+		delete(pubKeys, keyId)
+		delete(secretKeys, keyId)
+	}
+
+	ks.mu.Lock()
+	ks.pubKeys = pubKeys
+	ks.secretKeys = secretKeys
+	ks.retired = retired
+	ks.mu.Unlock()
+
+	logger.Infof("Loaded CSPaillier keys %q from %v", ks.KeyIds(), keyDir)
+	if len(problems) > 0 {
+		return fmt.Errorf("Cannot load CSPaillier keys: %v", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Generate generates a new key with the given ID and the given security parameters,
+// stores its secret and public part in the key folder (as cspaillierseckey-<key ID>.txt
+// and cspaillierpubkey-<key ID>.txt, or without the key ID for the default key) and
+// makes it active.
+func (ks *KeyStore) Generate(keyId string, secParams *encryption.CSPaillierSecParams) error {
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return fmt.Errorf("Cannot create key folder: %v", err)
+	}
+
+	suffix := ".txt"
+	if keyId != "" {
+		suffix = "-" + keyId + suffix
+	}
+	cspaillier := encryption.NewCSPaillier(secParams)
+	if err := cspaillier.StoreSecKey(filepath.Join(ks.dir, cspaillierSecKeyPrefix+suffix)); err != nil {
+		return fmt.Errorf("Cannot store CSPaillier secret key: %v", err)
+	}
+	if err := cspaillier.StorePubKey(filepath.Join(ks.dir, cspaillierPubKeyPrefix+suffix)); err != nil {
+		return fmt.Errorf("Cannot store CSPaillier public key: %v", err)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.pubKeys[keyId] = cspaillier.PubKey
+	ks.secretKeys[keyId] = cspaillier.SecretKey
+	delete(ks.retired, keyId)
+	return nil
+}
+
+// Get returns the secret part of the active key with the given ID.
+func (ks *KeyStore) Get(keyId string) (*encryption.CSPaillierSecretKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if key, ok := ks.secretKeys[keyId]; ok {
+		return key, nil
+	}
+	if _, ok := ks.pubKeys[keyId]; ok {
+		return nil, fmt.Errorf("No secret key for CSPaillier key %q", keyId)
+	}
+	return nil, ks.missingKeyErr(keyId)
+}
+
+// GetPubKey returns the public part of the active key with the given ID.
+func (ks *KeyStore) GetPubKey(keyId string) (*encryption.CSPaillierPubKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if key, ok := ks.pubKeys[keyId]; ok {
+		return key, nil
+	}
+	return nil, ks.missingKeyErr(keyId)
+}
+
+// missingKeyErr returns the error reporting that no key with the given ID is active.
+func (ks *KeyStore) missingKeyErr(keyId string) error {
+	if ks.retired[keyId] {
+		return fmt.Errorf("CSPaillier key %q is retired", keyId)
+	}
+	return fmt.Errorf("Unknown CSPaillier key %q", keyId)
+}
+
+// Retire retires the key with the given ID until the next Reload. Sessions using the
+// key are no longer accepted.
+func (ks *KeyStore) Retire(keyId string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	delete(ks.pubKeys, keyId)
+	delete(ks.secretKeys, keyId)
+	ks.retired[keyId] = true
+}
+
+// KeyIds returns the sorted IDs of active keys.
+func (ks *KeyStore) KeyIds() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	ids := make([]string, 0, len(ks.pubKeys))
+	for id := range ks.pubKeys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// parseKeyFileName returns the key ID of the key stored in the file with the given name,
+// whether the file holds the secret part of the key and whether the key is retired.
+// ok is false if the file doesn't hold a key.
+func parseKeyFileName(name string) (keyId string, secret, retired, ok bool) {
+	if strings.HasSuffix(name, retiredSuffix) {
+		name = strings.TrimSuffix(name, retiredSuffix)
+		retired = true
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	validExt := false
+	for _, e := range keyFileExtensions {
+		validExt = validExt || ext == e
+	}
+	if !validExt {
+		return "", false, false, false
+	}
+
+	for _, prefix := range []string{cspaillierSecKeyPrefix, cspaillierPubKeyPrefix} {
+		secret = prefix == cspaillierSecKeyPrefix
+		if base == prefix {
+			return "", secret, retired, true
+		}
+		if strings.HasPrefix(base, prefix+"-") {
+			keyId = strings.TrimPrefix(base, prefix+"-")
+			return keyId, secret, retired, keyId != ""
+		}
+	}
+	return "", false, false, false
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"errors"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"github.com/xlab-si/emmy/trustee"
+	"math/big"
+)
+
+// PaillierDecryptionShare sends the trustee's decryption share of the threshold Paillier
+// ciphertext sent by the client on behalf of the given requester, if the trustee's
+// policy allows it, together with a proof of its correctness. If the request is not
+// granted, the client receives a status message with the reason.
+func (s *Server) PaillierDecryptionShare(req *pb.Message, tr *trustee.Trustee, requester string,
+	t transport.Transport) error {
+	r := req.GetPaillierDecryptionShareRequest()
+	if r == nil {
+		return errors.New("Expected Paillier decryption share request")
+	}
+
+	share, err := tr.DecryptShare(&trustee.ShareRequest{
+		Requester:     requester,
+		C:             new(big.Int).SetBytes(r.C),
+		Label:         new(big.Int).SetBytes(r.Label),
+		Justification: r.Justification,
+	})
+	if err != nil {
+		logger.Noticef("Decryption share request of %q was not granted: %v", requester, err)
+		return s.send(newStatusMsg(false, err.Error()), t)
+	}
+
+	resp := &pb.Message{
+		Content: &pb.Message_PaillierDecryptionShare{
+			&pb.PaillierDecryptionShare{
+				Index: int32(share.Index),
+				C:     share.C.Bytes(),
+				A:     share.A.Bytes(),
+				B:     share.B.Bytes(),
+				Z:     share.Z.Bytes(),
+			},
+		},
+	}
+	return s.send(resp, t)
+}
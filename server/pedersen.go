@@ -4,10 +4,11 @@ import (
 	"github.com/xlab-si/emmy/commitments"
 	"github.com/xlab-si/emmy/dlog"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
 	"math/big"
 )
 
-func (s *Server) Pedersen(dlog *dlog.ZpDLog, stream pb.Protocol_RunServer) error {
+func (s *Server) Pedersen(dlog *dlog.ZpDLog, t transport.Transport) error {
 	pedersenReceiver := commitments.NewPedersenReceiver(dlog)
 
 	h := pedersenReceiver.GetH()
@@ -17,11 +18,11 @@ func (s *Server) Pedersen(dlog *dlog.ZpDLog, stream pb.Protocol_RunServer) error
 	}
 	resp := &pb.Message{Content: &pb.Message_PedersenFirst{&pedersenFirst}}
 
-	if err := s.send(resp, stream); err != nil {
+	if err := s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err := s.receive(stream)
+	req, err := s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -30,11 +31,11 @@ func (s *Server) Pedersen(dlog *dlog.ZpDLog, stream pb.Protocol_RunServer) error
 	el := new(big.Int).SetBytes(bigint.X1)
 	pedersenReceiver.SetCommitment(el)
 	resp = &pb.Message{Content: &pb.Message_Empty{&pb.EmptyMsg{}}}
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err = s.receive(stream)
+	req, err = s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -46,11 +47,9 @@ func (s *Server) Pedersen(dlog *dlog.ZpDLog, stream pb.Protocol_RunServer) error
 
 	logger.Noticef("Commitment scheme success: **%v**", valid)
 
-	resp = &pb.Message{
-		Content: &pb.Message_Status{&pb.Status{Success: valid}},
-	}
+	resp = newStatusMsg(valid, "Decommitment does not match the commitment")
 
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
@@ -4,10 +4,11 @@ import (
 	"github.com/xlab-si/emmy/commitments"
 	"github.com/xlab-si/emmy/common"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
 	"math/big"
 )
 
-func (s *Server) PedersenEC(stream pb.Protocol_RunServer) error {
+func (s *Server) PedersenEC(t transport.Transport) error {
 	pedersenECReceiver := commitments.NewPedersenECReceiver()
 
 	h := pedersenECReceiver.GetH()
@@ -17,11 +18,11 @@ func (s *Server) PedersenEC(stream pb.Protocol_RunServer) error {
 	}
 	resp := &pb.Message{Content: &pb.Message_EcGroupElement{&ecge}}
 
-	if err := s.send(resp, stream); err != nil {
+	if err := s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err := s.receive(stream)
+	req, err := s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -35,11 +36,11 @@ func (s *Server) PedersenEC(stream pb.Protocol_RunServer) error {
 	el := common.ToECGroupElement(ecgrop)
 	pedersenECReceiver.SetCommitment(el)
 	resp = &pb.Message{Content: &pb.Message_Empty{&pb.EmptyMsg{}}}
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err = s.receive(stream)
+	req, err = s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -51,11 +52,9 @@ func (s *Server) PedersenEC(stream pb.Protocol_RunServer) error {
 
 	logger.Noticef("Commitment scheme success: **%v**", valid)
 
-	resp = &pb.Message{
-		Content: &pb.Message_Status{&pb.Status{Success: valid}},
-	}
+	resp = newStatusMsg(valid, "Decommitment does not match the commitment")
 
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
@@ -5,11 +5,12 @@ import (
 	"github.com/xlab-si/emmy/dlog"
 	"github.com/xlab-si/emmy/dlogproofs"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
 	"math/big"
 )
 
 func (s *Server) Schnorr(req *pb.Message, dlog *dlog.ZpDLog,
-	protocolType common.ProtocolType, stream pb.Protocol_RunServer) error {
+	protocolType common.ProtocolType, t transport.Transport) error {
 	verifier := dlogproofs.NewSchnorrVerifier(dlog, protocolType)
 	var err error
 
@@ -25,11 +26,11 @@ func (s *Server) Schnorr(req *pb.Message, dlog *dlog.ZpDLog,
 			},
 		}
 
-		if err = s.send(resp, stream); err != nil {
+		if err = s.send(resp, t); err != nil {
 			return err
 		}
 
-		req, err = s.receive(stream)
+		req, err = s.receive(t)
 		if err != nil {
 			return err
 		}
@@ -57,11 +58,11 @@ func (s *Server) Schnorr(req *pb.Message, dlog *dlog.ZpDLog,
 		},
 	}
 
-	if err := s.send(resp, stream); err != nil {
+	if err := s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err = s.receive(stream)
+	req, err = s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -71,11 +72,9 @@ func (s *Server) Schnorr(req *pb.Message, dlog *dlog.ZpDLog,
 	trapdoor := new(big.Int).SetBytes(sProofData.Trapdoor)
 	valid := verifier.Verify(z, trapdoor)
 
-	resp = &pb.Message{
-		Content: &pb.Message_Status{&pb.Status{Success: valid}},
-	}
+	resp = newStatusMsg(valid, "Proof of knowledge of discrete logarithm is not valid")
 
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
@@ -4,10 +4,11 @@ import (
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/dlogproofs"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
 	"math/big"
 )
 
-func (s *Server) SchnorrEC(req *pb.Message, protocolType common.ProtocolType, stream pb.Protocol_RunServer) error {
+func (s *Server) SchnorrEC(req *pb.Message, protocolType common.ProtocolType, t transport.Transport) error {
 	verifier := dlogproofs.NewSchnorrECVerifier(protocolType)
 	var err error
 
@@ -24,11 +25,11 @@ func (s *Server) SchnorrEC(req *pb.Message, protocolType common.ProtocolType, st
 			},
 		}
 
-		if err := s.send(resp, stream); err != nil {
+		if err := s.send(resp, t); err != nil {
 			return err
 		}
 
-		req, err = s.receive(stream)
+		req, err = s.receive(t)
 		if err != nil {
 			return err
 		}
@@ -56,11 +57,11 @@ func (s *Server) SchnorrEC(req *pb.Message, protocolType common.ProtocolType, st
 		},
 	}
 
-	if err := s.send(resp, stream); err != nil {
+	if err := s.send(resp, t); err != nil {
 		return err
 	}
 
-	req, err = s.receive(stream)
+	req, err = s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -70,11 +71,9 @@ func (s *Server) SchnorrEC(req *pb.Message, protocolType common.ProtocolType, st
 	trapdoor := new(big.Int).SetBytes(sProofData.Trapdoor)
 	valid := verifier.Verify(z, trapdoor)
 
-	resp = &pb.Message{
-		Content: &pb.Message_Status{&pb.Status{Success: valid}},
-	}
+	resp = newStatusMsg(valid, "Proof of knowledge of discrete logarithm is not valid")
 
-	if err = s.send(resp, stream); err != nil {
+	if err = s.send(resp, t); err != nil {
 		return err
 	}
 
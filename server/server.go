@@ -1,52 +1,153 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/encryption"
 	"github.com/xlab-si/emmy/log"
 	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/transport"
+	"github.com/xlab-si/emmy/trustee"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"io"
-	"path/filepath"
+	"strings"
+	"sync"
 )
 
 var _ pb.ProtocolServer = (*Server)(nil)
 
-type Server struct{}
+type Server struct {
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+	trustee *trustee.Trustee // global trustee, nil if ciphertexts under global keys cannot be decrypted
+}
 
 var logger = log.ServerLogger
 
 func NewProtocolServer() *Server {
 	logger.Info("Instantiating new protocol server")
-	// At the time of instantiation, we don't yet know which handler or stream to use,
-	// therefore just return a reference to the empty struct
-	return &Server{}
+	// At the time of instantiation, we don't yet know which handler or transport to use,
+	// therefore just set up the tenants and load their keys
+	s := &Server{
+		tenants: map[string]*Tenant{
+			DefaultTenantId: newDefaultTenant(),
+		},
+	}
+	for _, id := range config.LoadTenantIds() {
+		tenant, err := newTenant(id)
+		if err != nil {
+			logger.Errorf("Tenant %q will not be served: %v", id, err)
+			continue
+		}
+		s.tenants[id] = tenant
+	}
+	s.trustee = newGlobalTrustee(s.tenants[DefaultTenantId].keys)
+	if err := s.ReloadKeys(); err != nil {
+		logger.Warning(err)
+	}
+	return s
+}
+
+// Tenant returns the tenant with the given ID.
+func (s *Server) Tenant(id string) (*Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenant, ok := s.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("Unknown tenant %q", id)
+	}
+	return tenant, nil
+}
+
+// GenerateMissingKeys generates a default CSPaillier key with the given security parameters
+// for each tenant that is allowed to run CSPaillier but holds no CSPaillier keys, so
+// that verifiable encryption works without generating keys upfront.
+func (s *Server) GenerateMissingKeys(secParams *encryption.CSPaillierSecParams) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, tenant := range s.tenants {
+		if !tenant.Allows(pb.SchemaType_CSPAILLIER) || len(tenant.keys.KeyIds()) > 0 {
+			continue
+		}
+		logger.Noticef("Generating CSPaillier key of tenant %q in %v", tenant.Id, tenant.keys.Dir())
+		if err := tenant.keys.Generate("", secParams); err != nil {
+			return fmt.Errorf("Cannot generate key of tenant %q: %v", tenant.Id, err)
+		}
+	}
+	return nil
+}
+
+// ReloadKeys reloads keys of all tenants from their key folders, so that keys
+// can be added, rotated or retired without restarting the server. The rest of the
+// configuration, such as trustee labels and their ciphertexts, is not reloaded.
+func (s *Server) ReloadKeys() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var problems []string
+	for _, tenant := range s.tenants {
+		if err := tenant.keys.Reload(); err != nil {
+			problems = append(problems, fmt.Sprintf("tenant %q: %v", tenant.Id, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("Cannot reload keys: %v", strings.Join(problems, "; "))
+	}
+	return nil
 }
 
-func (s *Server) send(msg *pb.Message, stream pb.Protocol_RunServer) error {
-	if err := stream.Send(msg); err != nil {
-		return fmt.Errorf("Error sending message:", err)
+func (s *Server) send(msg *pb.Message, t transport.Transport) error {
+	if err := t.Send(context.Background(), msg); err != nil {
+		return fmt.Errorf("Error sending message: %v", err)
 	}
 	logger.Info("Successfully sent response:", msg)
 
 	return nil
 }
 
-func (s *Server) receive(stream pb.Protocol_RunServer) (*pb.Message, error) {
-	resp, err := stream.Recv()
+func (s *Server) receive(t transport.Transport) (*pb.Message, error) {
+	resp, err := t.Receive(context.Background())
 	if err == io.EOF {
 		return nil, err
 	} else if err != nil {
 		return nil, fmt.Errorf("An error ocurred: %v", err)
 	}
-	logger.Info("Received request from the stream", resp)
+	logger.Info("Received request from the transport", resp)
 	return resp, nil
 }
 
+// Run runs the protocol requested by the client over a gRPC stream, for the tenant
+// that the client authenticated as.
 func (s *Server) Run(stream pb.Protocol_RunServer) error {
-	logger.Info("Starting new RPC")
+	tenant, err := s.authenticateGRPC(stream.Context())
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return s.ServeTenant(transport.NewGRPCServerTransport(stream), tenant)
+}
+
+// Serve runs the protocol requested by the client over the given transport, for the
+// default tenant.
+func (s *Server) Serve(t transport.Transport) error {
+	tenant, err := s.Tenant(DefaultTenantId)
+	if err != nil {
+		return err
+	}
+	return s.ServeTenant(t, tenant)
+}
+
+// ServeTenant runs the protocol requested by the client over the given transport,
+// using the group parameters and keys of the given tenant.
+func (s *Server) ServeTenant(t transport.Transport, tenant *Tenant) error {
+	logger.Infof("Starting new RPC for tenant %q", tenant.Id)
 
-	req, err := s.receive(stream)
+	req, err := s.receive(t)
 	if err != nil {
 		return err
 	}
@@ -58,7 +159,7 @@ func (s *Server) Run(stream pb.Protocol_RunServer) error {
 	// Check whether the client requested a valid schema
 	reqSchemaTypeStr, schemaValid := pb.SchemaType_name[int32(reqSchemaType)]
 	if !schemaValid {
-		return fmt.Errorf("Client [", reqClientId, "] requested invalid schema: %v", reqSchemaType)
+		return fmt.Errorf("Client [ %v ] requested invalid schema: %v", reqClientId, reqSchemaType)
 	}
 
 	// Check whether the client requested a valid schema variant
@@ -67,26 +168,64 @@ func (s *Server) Run(stream pb.Protocol_RunServer) error {
 		return fmt.Errorf("Client [ %v ] requested invalid schema variant: %v", reqClientId, reqSchemaVariant)
 	}
 
-	logger.Noticef("Client [ %v ] requested schema %v, variant %v", reqClientId, reqSchemaTypeStr, reqSchemaVariantStr)
+	logger.Noticef("Client [ %v ] of tenant %q requested schema %v, variant %v",
+		reqClientId, tenant.Id, reqSchemaTypeStr, reqSchemaVariantStr)
+
+	if !tenant.Allows(reqSchemaType) {
+		return fmt.Errorf("Client [ %v ] of tenant %q is not allowed to run %v",
+			reqClientId, tenant.Id, reqSchemaTypeStr)
+	}
 
 	// Convert Sigma, ZKP or ZKPOK protocol type to a common type
 	protocolType := common.ToProtocolType(reqSchemaVariant)
 
 	switch reqSchemaType {
 	case pb.SchemaType_PEDERSEN_EC:
-		err = s.PedersenEC(stream)
+		err = s.PedersenEC(t)
 	case pb.SchemaType_PEDERSEN:
-		dlog := config.LoadDLog("pedersen")
-		err = s.Pedersen(dlog, stream)
+		dlog, loadErr := tenant.DLog("pedersen")
+		if loadErr != nil {
+			err = loadErr
+			break
+		}
+		err = s.Pedersen(dlog, t)
 	case pb.SchemaType_SCHNORR:
-		dlog := config.LoadDLog("schnorr")
-		err = s.Schnorr(req, dlog, protocolType, stream)
+		dlog, loadErr := tenant.DLog("schnorr")
+		if loadErr != nil {
+			err = loadErr
+			break
+		}
+		err = s.Schnorr(req, dlog, protocolType, t)
 	case pb.SchemaType_SCHNORR_EC:
-		err = s.SchnorrEC(req, protocolType, stream)
+		err = s.SchnorrEC(req, protocolType, t)
 	case pb.SchemaType_CSPAILLIER:
-		keyDir := config.LoadKeyDirFromConfig()
-		secKeyPath := filepath.Join(keyDir, "cspaillierseckey.txt")
-		err = s.CSPaillier(req, secKeyPath, stream)
+		pubKey, keyErr := tenant.keys.GetPubKey(req.GetKeyId())
+		if keyErr != nil {
+			err = keyErr
+			break
+		}
+		err = s.CSPaillier(req, pubKey, t)
+	case pb.SchemaType_CSPAILLIER_DECRYPTION:
+		tr, trErr := s.trusteeFor(tenant)
+		if trErr != nil {
+			err = trErr
+			break
+		}
+		err = s.CSPaillierDecryption(req, tr, tenant.Id, t)
+	case pb.SchemaType_PAILLIER_DECRYPTION_SHARE:
+		tr, trErr := s.trusteeFor(tenant)
+		if trErr != nil {
+			err = trErr
+			break
+		}
+		err = s.PaillierDecryptionShare(req, tr, tenant.Id, t)
+	case pb.SchemaType_ELGAMAL_DECRYPTION_SHARE:
+		tr, trErr := s.trusteeFor(tenant)
+		if trErr != nil {
+			err = trErr
+			break
+		}
+		err = s.ElGamalDecryptionShare(req, tr, tenant.Id, t)
 	}
 
 	if err != nil {
@@ -97,3 +236,31 @@ func (s *Server) Run(stream pb.Protocol_RunServer) error {
 	logger.Info("RPC finished successfully")
 	return nil
 }
+
+// trusteeFor returns the trustee that decrypts ciphertexts on request of tenant: its own
+// trustee, if decryption is configured for the tenant, or else the global trustee. Clients
+// of the default tenant are not authenticated, so they cannot request decryption.
+func (s *Server) trusteeFor(tenant *Tenant) (*trustee.Trustee, error) {
+	if tenant.Id == DefaultTenantId {
+		return nil, errors.New("Decryption may only be requested by authenticated tenants")
+	}
+	if tenant.trustee != nil {
+		return tenant.trustee, nil
+	}
+	if s.trustee == nil {
+		return nil, fmt.Errorf("Decryption is not enabled for tenant %q", tenant.Id)
+	}
+	return s.trustee, nil
+}
+
+// newStatusMsg returns the final message of a protocol, reporting whether the client's
+// proof was verified. failureReason is reported to the client only if it was not.
+func newStatusMsg(success bool, failureReason string) *pb.Message {
+	status := &pb.Status{Success: success}
+	if !success {
+		status.Reason = failureReason
+	}
+	return &pb.Message{
+		Content: &pb.Message_Status{status},
+	}
+}
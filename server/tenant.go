@@ -0,0 +1,159 @@
+package server
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/trustee"
+	"os"
+	"strings"
+)
+
+// DefaultTenantId is the ID of the default tenant, which uses the global configuration
+// and serves clients that don't authenticate as any of the configured tenants.
+const DefaultTenantId = ""
+
+// Tenant is a party served by emmy server, such as a business unit, with its own group
+// parameters, keys and policy of allowed protocols (see config.Tenant). Each session is
+// run for the tenant that the client authenticated as.
+type Tenant struct {
+	Id          string
+	config      *config.Tenant // nil for the default tenant
+	keys        *KeyStore
+	protocols   map[pb.SchemaType]bool // nil if all protocols are allowed
+	tokenHashes map[string]bool
+	tlsNames    map[string]bool
+	trustee     *trustee.Trustee // nil if the tenant's ciphertexts cannot be decrypted
+}
+
+// newDefaultTenant returns the default tenant. Its clients are not authenticated, so it
+// has no trustee; ciphertexts under the global keys are decrypted by the global trustee
+// (see newGlobalTrustee) on request of other tenants.
+func newDefaultTenant() *Tenant {
+	return &Tenant{
+		Id:   DefaultTenantId,
+		keys: NewKeyStore(config.LoadKeyDirFromConfig()),
+	}
+}
+
+// newTenant returns the tenant with the given ID, as configured in the tenants section.
+func newTenant(id string) (*Tenant, error) {
+	tenantConfig, err := config.LoadTenant(id)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tenant{
+		Id:          id,
+		config:      tenantConfig,
+		keys:        NewKeyStore(tenantConfig.LoadKeyDir()),
+		tokenHashes: toSet(tenantConfig.LoadTokenHashes()),
+		tlsNames:    toSet(tenantConfig.LoadTLSNames()),
+	}
+	if names := tenantConfig.LoadProtocols(); len(names) > 0 {
+		t.protocols = map[pb.SchemaType]bool{}
+		for _, name := range names {
+			schema, ok := pb.SchemaType_value[strings.ToUpper(name)]
+			if !ok {
+				return nil, fmt.Errorf("Tenant %q: unknown protocol %v", id, name)
+			}
+			t.protocols[pb.SchemaType(schema)] = true
+		}
+	}
+	labels, err := tenantConfig.LoadTrusteeLabels()
+	if err != nil {
+		return nil, fmt.Errorf("Tenant %q: %v", id, err)
+	}
+	t.trustee = newTrustee(fmt.Sprintf("tenant %q", id), t.keys, labels,
+		tenantConfig.LoadTrusteeAuditLog(), tenantConfig.LoadTrusteeKeyShare(),
+		tenantConfig.LoadTrusteeElGamalKeyShare())
+	return t, nil
+}
+
+// newGlobalTrustee returns the trustee that decrypts ciphertexts under the global keys
+// on request of the tenants listed with their labels in the trustee section, or nil if
+// no labels are configured or they are invalid.
+func newGlobalTrustee(keys *KeyStore) *trustee.Trustee {
+	labels, err := config.LoadTrusteeLabels()
+	if err != nil {
+		logger.Errorf("Decryption is disabled for global keys: %v", err)
+		return nil
+	}
+	return newTrustee("global keys", keys, labels,
+		config.LoadTrusteeAuditLog(), config.LoadTrusteeKeyShare(), config.LoadTrusteeElGamalKeyShare())
+}
+
+// newTrustee returns the trustee that decrypts ciphertexts with the given labels using
+// keys, on request of the requesters listed with each label, and records decryption
+// requests in the audit log at auditLogPath. If the threshold Paillier key share at
+// keySharePath or the threshold ElGamal key share at elGamalKeySharePath exists, the
+// trustee also issues decryption shares of the ciphertexts registered with each label.
+// Without authorized labels, or if the audit log cannot be opened, nil is returned and
+// ciphertexts cannot be decrypted. name describes whose ciphertexts the trustee decrypts
+// in log messages.
+func newTrustee(name string, keys *KeyStore, labels []config.TrusteeLabel,
+	auditLogPath, keySharePath, elGamalKeySharePath string) *trustee.Trustee {
+	if len(labels) == 0 {
+		return nil
+	}
+	requesters := make(map[string][]string, len(labels))
+	shareCiphertexts := make(map[string][]string, len(labels))
+	for _, label := range labels {
+		requesters[label.Label] = append(requesters[label.Label], label.Requesters...)
+		shareCiphertexts[label.Label] = append(shareCiphertexts[label.Label], label.Ciphertexts...)
+	}
+	auditLog, err := trustee.OpenAuditLog(auditLogPath)
+	if err != nil {
+		logger.Errorf("Decryption is disabled for %v: %v", name, err)
+		return nil
+	}
+	tr := trustee.New(keys, trustee.NewLabelPolicy(requesters), auditLog)
+	tr.SetShareCiphertexts(shareCiphertexts)
+
+	if _, err := os.Stat(keySharePath); err == nil {
+		keyShare, err := encryption.NewThresholdPaillierKeyShareFromFile(keySharePath)
+		if err != nil {
+			logger.Errorf("Decryption shares are disabled for %v: %v", name, err)
+		} else {
+			tr.SetKeyShare(keyShare)
+		}
+	}
+	if _, err := os.Stat(elGamalKeySharePath); err == nil {
+		keyShare, err := encryption.NewThresholdElGamalKeyShareFromFile(elGamalKeySharePath)
+		if err != nil {
+			logger.Errorf("ElGamal decryption shares are disabled for %v: %v", name, err)
+		} else {
+			tr.SetElGamalKeyShare(keyShare)
+		}
+	}
+	return tr
+}
+
+// DLog returns the group parameters of the tenant for the given scheme.
+func (t *Tenant) DLog(scheme string) (*dlog.ZpDLog, error) {
+	if t.config == nil {
+		return config.LoadDLog(scheme)
+	}
+	return t.config.LoadDLog(scheme)
+}
+
+// Keys returns the store of tenant's keys.
+func (t *Tenant) Keys() *KeyStore {
+	return t.keys
+}
+
+// Allows returns true if the tenant's policy allows running the given protocol.
+func (t *Tenant) Allows(schema pb.SchemaType) bool {
+	return t.protocols == nil || t.protocols[schema]
+}
+
+// toSet returns a set holding the (lower-cased) elements of the given slice.
+func toSet(elements []string) map[string]bool {
+	set := make(map[string]bool, len(elements))
+	for _, e := range elements {
+		set[strings.ToLower(e)] = true
+	}
+	return set
+}
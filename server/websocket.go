@@ -0,0 +1,26 @@
+package server
+
+import (
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/websocket"
+	"net/http"
+)
+
+// WebSocketHandler returns a http.Handler that runs protocols with clients connecting
+// over WebSockets. Each WebSocket connection carries a single protocol execution, for
+// the tenant that the client authenticated as when opening the connection.
+func (s *Server) WebSocketHandler() http.Handler {
+	return websocket.Handler(func(conn *websocket.Conn) {
+		t := transport.NewWebSocketTransport(conn)
+		defer t.Close()
+
+		tenant, err := s.AuthenticateHTTP(conn.Request())
+		if err != nil {
+			logger.Noticef("WebSocket client not authenticated: %v", err)
+			return
+		}
+		if err := s.ServeTenant(t, tenant); err != nil {
+			logger.Errorf("WebSocket protocol execution failed: %v", err)
+		}
+	})
+}
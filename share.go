@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"github.com/urfave/cli"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/keystore"
+	"github.com/xlab-si/emmy/secretsharing"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// shareCommand returns the command for splitting secrets (such as root keys) into
+// shares with Shamir's secret sharing, and for combining the shares back.
+func shareCommand() cli.Command {
+	return cli.Command{
+		Name:  "share",
+		Usage: "Splits secrets into shares and combines them",
+		Subcommands: []cli.Command{
+			{
+				Name:      "split",
+				Usage:     "Splits a secret into shares, any threshold of which determine the secret",
+				ArgsUsage: "[secret file, stdin if not given or -]",
+				Flags: []cli.Flag{
+					cli.IntFlag{Name: "threshold, t", Usage: "Number of shares needed to combine the secret (at least 2)"},
+					cli.IntFlag{Name: "shares, n", Usage: "Number of shares"},
+					cli.StringFlag{Name: "out", Value: ".", Usage: "Folder where share files are written"},
+					cli.StringFlag{Name: "prefix", Value: "share", Usage: "Prefix of share file names"},
+					cli.StringFlag{Name: "format", Value: "pem", Usage: "Format of share files: json or pem"},
+				},
+				Action: shareSplit,
+			},
+			{
+				Name:      "combine",
+				Usage:     "Combines the secret from its shares",
+				ArgsUsage: "<share file>...",
+				Flags: []cli.Flag{
+					cli.StringFlag{Name: "out", Usage: "Path to the file where the secret is written, stdout if not given"},
+				},
+				Action: shareCombine,
+			},
+		},
+	}
+}
+
+// shareSplit writes one file with a share of the secret for each shareholder. Share
+// files are readable only by their owner.
+func shareSplit(ctx *cli.Context) error {
+	threshold, n := ctx.Int("threshold"), ctx.Int("shares")
+	if threshold < 2 || n < threshold {
+		return fmt.Errorf("Invalid threshold %d and number of shares %d, "+
+			"need 2 <= threshold <= shares", threshold, n)
+	}
+	format := ctx.String("format")
+	if format != "json" && format != "pem" {
+		return fmt.Errorf("Unsupported format %q, use json or pem", format)
+	}
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("Expected a single secret file, got %d arguments", ctx.NArg())
+	}
+
+	var secret []byte
+	var err error
+	if path := ctx.Args().First(); path == "" || path == "-" {
+		secret, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		secret, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("Cannot read secret: %v", err)
+	}
+	if len(secret) == 0 {
+		return fmt.Errorf("Secret is empty")
+	}
+
+	dealer, _ := secretsharing.NewDealer()
+	shares, err := dealer.SplitBytes(secret, threshold, n)
+	if err != nil {
+		return fmt.Errorf("Cannot split secret: %v", err)
+	}
+	for _, share := range shares {
+		path := filepath.Join(ctx.String("out"),
+			fmt.Sprintf("%s-%d.%s", ctx.String("prefix"), share.Index, format))
+		if err := storeEncoded(share, format, path, true, nil); err != nil {
+			return fmt.Errorf("Cannot write share %d: %v", share.Index, err)
+		}
+		fmt.Printf("Share %d of %d written to %v\n", share.Index, n, path)
+	}
+	return nil
+}
+
+// shareCombine combines the secret from the share files given as arguments and writes
+// it to stdout or, if given, to a file readable only by its owner. Faulty shares, which
+// are corrected if enough shares are given, are reported.
+func shareCombine(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return fmt.Errorf("No share files given")
+	}
+	shares := make([]*secretsharing.ByteShare, ctx.NArg())
+	for i, path := range ctx.Args() {
+		data, err := keystore.Load(path)
+		if err != nil {
+			return err
+		}
+		shares[i] = new(secretsharing.ByteShare)
+		if err := common.UnmarshalJSONOrPEM(data, shares[i]); err != nil {
+			return fmt.Errorf("Cannot read share from %v: %v", path, err)
+		}
+	}
+
+	dealer, _ := secretsharing.NewDealer()
+	secret, faulty, err := dealer.CombineBytes(shares)
+	if err != nil {
+		return fmt.Errorf("Cannot combine shares: %v", err)
+	}
+	if len(faulty) > 0 {
+		fmt.Fprintf(os.Stderr, "Faulty shares (corrected): %v\n", faulty)
+	}
+
+	if path := ctx.String("out"); path != "" {
+		if err := keystore.Store(secret, path, nil); err != nil {
+			return fmt.Errorf("Cannot write secret: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Secret written to %v\n", path)
+		return nil
+	}
+	_, err = os.Stdout.Write(secret)
+	return err
+}
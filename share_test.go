@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/secretsharing"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// runShare runs emmy share with the given arguments and returns what it wrote to stdout.
+func runShare(t *testing.T, args ...string) ([]byte, error) {
+	out, err := ioutil.TempFile("", "emmy-stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+	stdout := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = stdout }()
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{shareCommand()}
+	err = app.Run(append([]string{"emmy", "share"}, args...))
+
+	data, readErr := ioutil.ReadFile(out.Name())
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	return data, err
+}
+
+func TestShare(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secret := []byte("secret root key, which must be recovered exactly")
+	secretPath := filepath.Join(dir, "secret")
+	assert.Nil(t, ioutil.WriteFile(secretPath, secret, 0600))
+
+	_, err = runShare(t, "split", "-t", "1", "-n", "4", "--out", dir, secretPath)
+	assert.NotNil(t, err, "threshold 1 should be rejected")
+	_, err = runShare(t, "split", "-t", "2", "-n", "4", "--out", dir, secretPath)
+	assert.Nil(t, err, "splitting the secret failed")
+
+	shares := make([]string, 4)
+	for i := range shares {
+		shares[i] = filepath.Join(dir, fmt.Sprintf("share-%d.pem", i+1))
+		info, err := os.Stat(shares[i])
+		if assert.Nil(t, err, "share file %v should exist", shares[i]) {
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm(),
+				"share file should be readable only by its owner")
+		}
+	}
+
+	// any threshold of shares determine the secret, written to stdout or to a file
+	out, err := runShare(t, "combine", shares[0], shares[2])
+	assert.Nil(t, err, "combining shares failed")
+	assert.Equal(t, secret, out)
+
+	secretOut := filepath.Join(dir, "secret-out")
+	out, err = runShare(t, "combine", "--out", secretOut, shares[1], shares[3])
+	assert.Nil(t, err, "combining shares failed")
+	assert.Empty(t, out, "nothing should be written to stdout")
+	combined, err := ioutil.ReadFile(secretOut)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, combined)
+	if info, err := os.Stat(secretOut); assert.Nil(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(),
+			"secret file should be readable only by its owner")
+	}
+
+	// a corrupted share is corrected given more than threshold shares
+	data, err := ioutil.ReadFile(shares[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	share := new(secretsharing.ByteShare)
+	assert.Nil(t, common.UnmarshalJSONOrPEM(data, share))
+	share.Values[0] = new(big.Int).Add(share.Values[0], big.NewInt(1))
+	assert.Nil(t, storeEncoded(share, "pem", shares[3], true, nil))
+	out, err = runShare(t, "combine", shares[0], shares[1], shares[2], shares[3])
+	assert.Nil(t, err, "the corrupted share should be corrected")
+	assert.Equal(t, secret, out)
+
+	// shares of different secrets, or files that are not shares, are not combined
+	otherDir := filepath.Join(dir, "other")
+	assert.Nil(t, os.Mkdir(otherDir, 0700))
+	_, err = runShare(t, "split", "-t", "2", "-n", "2", "--out", otherDir, secretPath)
+	assert.Nil(t, err, "splitting the secret failed")
+	_, err = runShare(t, "combine", shares[0], filepath.Join(otherDir, "share-2.pem"))
+	assert.NotNil(t, err, "combining shares of different splits should fail")
+	_, err = runShare(t, "combine", shares[0], secretPath)
+	assert.NotNil(t, err, "combining with a file that is not a share should fail")
+}
@@ -3,7 +3,10 @@ package signatures
 import (
 	"crypto/rand"
 	"errors"
+	"github.com/golang/protobuf/proto"
 	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/keystore"
+	pb "github.com/xlab-si/emmy/protobuf"
 	"log"
 	"math/big"
 )
@@ -36,34 +39,161 @@ type CLPubKey struct {
 	c   *big.Int
 }
 
-func NewCL(numOfBlocks int) *CL {
-	config := CLConfig{
-		l_n: 1024,
-		l_m: 160,
-		l:   160,
-	}
+// CLSecretKey consists of safe primes p, q (n = p * q) and the public key.
+type CLSecretKey struct {
+	P      *big.Int
+	Q      *big.Int
+	PubKey *CLPubKey
+}
 
+func NewCL(numOfBlocks int) *CL {
 	cl := CL{
 		numOfBlocks: numOfBlocks,
-		config:      &config,
+		config:      newCLConfig(),
 	}
 	cl.generateKey()
 	return &cl
 }
 
 func NewPubCL(pubKey *CLPubKey) *CL {
-	config := CLConfig{
+	cl := CL{
+		config: newCLConfig(),
+		pubKey: pubKey,
+	}
+
+	return &cl
+}
+
+// NewCLFromSecKey returns CL with the secret key stored at path, either protobuf
+// (see StoreSecKey), JSON or PEM encoded. If the key is encrypted, the passphrase is obtained with keystore.Passphrase.
+func NewCLFromSecKey(path string) (*CL, error) {
+	bytes, err := keystore.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if common.IsJSONOrPEM(bytes) {
+		var secKey CLSecretKey
+		if err := common.UnmarshalJSONOrPEM(bytes, &secKey); err != nil {
+			return nil, err
+		}
+		return NewCLFromSecretKey(&secKey), nil
+	}
+	sKey := &pb.CLSecretKey{}
+	err = proto.Unmarshal(bytes, sKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secKey := CLSecretKey{
+		P:      new(big.Int).SetBytes(sKey.P),
+		Q:      new(big.Int).SetBytes(sKey.Q),
+		PubKey: newCLPubKey(sKey.N, sKey.A, sKey.B, sKey.C),
+	}
+	return NewCLFromSecretKey(&secKey), nil
+}
+
+// NewCLFromSecretKey returns CL with the given secret key.
+func NewCLFromSecretKey(secKey *CLSecretKey) *CL {
+	cl := CL{
+		numOfBlocks: len(secKey.PubKey.a_L),
+		config:      newCLConfig(),
+		p:           secKey.P,
+		q:           secKey.Q,
+		pubKey:      secKey.PubKey,
+	}
+
+	return &cl
+}
+
+// NewCLFromPubKeyFile returns CL with the public key stored at path, either protobuf
+// (see StorePubKey), JSON or PEM encoded. It can only be used for verification of signatures.
+func NewCLFromPubKeyFile(path string) (*CL, error) {
+	bytes, err := common.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if common.IsJSONOrPEM(bytes) {
+		var pubKey CLPubKey
+		if err := common.UnmarshalJSONOrPEM(bytes, &pubKey); err != nil {
+			return nil, err
+		}
+		return NewPubCL(&pubKey), nil
+	}
+	pKey := &pb.CLPubKey{}
+	err = proto.Unmarshal(bytes, pKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPubCL(newCLPubKey(pKey.N, pKey.A, pKey.B, pKey.C)), nil
+}
+
+func newCLConfig() *CLConfig {
+	return &CLConfig{
 		l_n: 1024,
 		l_m: 160,
 		l:   160,
 	}
+}
 
-	cl := CL{
-		config: &config,
-		pubKey: pubKey,
+func newCLPubKey(n []byte, a_L [][]byte, b, c []byte) *CLPubKey {
+	pubKey := CLPubKey{
+		n: new(big.Int).SetBytes(n),
+		b: new(big.Int).SetBytes(b),
+		c: new(big.Int).SetBytes(c),
+	}
+	for _, a := range a_L {
+		pubKey.a_L = append(pubKey.a_L, new(big.Int).SetBytes(a))
 	}
+	return &pubKey
+}
 
-	return &cl
+// StoreSecKey writes the secret key (unencrypted) to the file at path, readable only by its owner.
+func (cl *CL) StoreSecKey(path string) error {
+	return cl.storeSecKey(path, nil)
+}
+
+// StoreEncryptedSecKey writes the secret key encrypted with passphrase to the file at path.
+func (cl *CL) StoreEncryptedSecKey(path string, passphrase []byte) error {
+	return cl.storeSecKey(path, passphrase)
+}
+
+func (cl *CL) storeSecKey(path string, passphrase []byte) error {
+	secKey := &pb.CLSecretKey{
+		P: cl.p.Bytes(),
+		Q: cl.q.Bytes(),
+		N: cl.pubKey.n.Bytes(),
+		A: intsToBytes(cl.pubKey.a_L),
+		B: cl.pubKey.b.Bytes(),
+		C: cl.pubKey.c.Bytes(),
+	}
+	data, err := proto.Marshal(secKey)
+	if err != nil {
+		return err
+	}
+	return keystore.Store(data, path, passphrase)
+}
+
+func (cl *CL) StorePubKey(path string) error {
+	pubKey := &pb.CLPubKey{
+		N: cl.pubKey.n.Bytes(),
+		A: intsToBytes(cl.pubKey.a_L),
+		B: cl.pubKey.b.Bytes(),
+		C: cl.pubKey.c.Bytes(),
+	}
+	data, err := proto.Marshal(pubKey)
+	if err != nil {
+		return err
+	}
+	return common.Store(data, path)
+}
+
+func intsToBytes(ints []*big.Int) [][]byte {
+	bytes := make([][]byte, len(ints))
+	for i, x := range ints {
+		bytes[i] = x.Bytes()
+	}
+	return bytes
 }
 
 func (cl *CL) getQuadraticResidues(n *big.Int) ([]*big.Int, *big.Int, *big.Int) {
@@ -190,10 +320,21 @@ func (cl *CL) GetPubKey() *CLPubKey {
 	return cl.pubKey
 }
 
+func (cl *CL) GetSecretKey() *CLSecretKey {
+	return &CLSecretKey{
+		P:      cl.p,
+		Q:      cl.q,
+		PubKey: cl.pubKey,
+	}
+}
+
 func (cl *CL) generateKey() (err error) {
-	// generate two primes of length 512
-	p, err := common.GetSafePrime(512)
-	q, err := common.GetSafePrime(512)
+	// generate two safe primes of length l_n/2
+	p, err := common.GetSafePrime(cl.config.l_n / 2)
+	if err != nil {
+		return err
+	}
+	q, err := common.GetSafePrime(cl.config.l_n / 2)
 	if err != nil {
 		return err
 	}
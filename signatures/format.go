@@ -0,0 +1,101 @@
+package signatures
+
+import (
+	"github.com/xlab-si/emmy/common"
+)
+
+// Types of encoded keys and signatures (see common.MarshalVersioned).
+const (
+	CLPubKeyType    = "cl-public-key"
+	CLSecretKeyType = "cl-secret-key"
+	CLSignatureType = "cl-signature"
+)
+
+type clPubKeyJSON struct {
+	N *common.Int   `json:"n"`
+	A []*common.Int `json:"a"`
+	B *common.Int   `json:"b"`
+	C *common.Int   `json:"c"`
+}
+
+func newCLPubKeyJSON(pubKey *CLPubKey) clPubKeyJSON {
+	return clPubKeyJSON{
+		N: common.NewInt(pubKey.n),
+		A: common.NewInts(pubKey.a_L),
+		B: common.NewInt(pubKey.b),
+		C: common.NewInt(pubKey.c),
+	}
+}
+
+func (v *clPubKeyJSON) pubKey() *CLPubKey {
+	return &CLPubKey{
+		n:   v.N.BigInt(),
+		a_L: common.BigInts(v.A),
+		b:   v.B.BigInt(),
+		c:   v.C.BigInt(),
+	}
+}
+
+func (pubKey *CLPubKey) MarshalJSON() ([]byte, error) {
+	v := newCLPubKeyJSON(pubKey)
+	return common.MarshalVersioned(CLPubKeyType, &v)
+}
+
+func (pubKey *CLPubKey) UnmarshalJSON(data []byte) error {
+	var v clPubKeyJSON
+	if err := common.UnmarshalVersioned(data, CLPubKeyType, &v); err != nil {
+		return err
+	}
+	*pubKey = *v.pubKey()
+	return nil
+}
+
+type clSecretKeyJSON struct {
+	P *common.Int `json:"p"`
+	Q *common.Int `json:"q"`
+	clPubKeyJSON
+}
+
+func (secKey *CLSecretKey) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(CLSecretKeyType, &clSecretKeyJSON{
+		P:            common.NewInt(secKey.P),
+		Q:            common.NewInt(secKey.Q),
+		clPubKeyJSON: newCLPubKeyJSON(secKey.PubKey),
+	})
+}
+
+func (secKey *CLSecretKey) UnmarshalJSON(data []byte) error {
+	var v clSecretKeyJSON
+	if err := common.UnmarshalVersioned(data, CLSecretKeyType, &v); err != nil {
+		return err
+	}
+	secKey.P = v.P.BigInt()
+	secKey.Q = v.Q.BigInt()
+	secKey.PubKey = v.pubKey()
+	return nil
+}
+
+type clSignatureJSON struct {
+	E *common.Int `json:"e"`
+	S *common.Int `json:"s"`
+	V *common.Int `json:"v"`
+}
+
+func (signature *CLSignature) MarshalJSON() ([]byte, error) {
+	return common.MarshalVersioned(CLSignatureType, &clSignatureJSON{
+		E: common.NewInt(signature.e),
+		S: common.NewInt(signature.s),
+		V: common.NewInt(signature.v),
+	})
+}
+
+func (signature *CLSignature) UnmarshalJSON(data []byte) error {
+	var v clSignatureJSON
+	if err := common.UnmarshalVersioned(data, CLSignatureType, &v); err != nil {
+		return err
+	}
+	signature.e = v.E.BigInt()
+	signature.s = v.S.BigInt()
+	signature.v = v.V.BigInt()
+	return nil
+}
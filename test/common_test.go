@@ -22,6 +22,15 @@ func TestGetGermainPrime(t *testing.T) {
 
 	assert.Equal(t, p.ProbablyPrime(20), true, "p should be prime")
 	assert.Equal(t, p1.ProbablyPrime(20), true, "p1 should be prime")
+	assert.Equal(t, 512, p.BitLen(), "p should have the requested length")
+
+	for _, bits := range []int{8, 16, 24} {
+		p := common.GetGermainPrime(bits)
+		p1 := new(big.Int).Add(p, p)
+		p1.Add(p1, big.NewInt(1))
+		assert.Equal(t, bits, p.BitLen(), "p should have the requested length")
+		assert.True(t, p.ProbablyPrime(20) && p1.ProbablyPrime(20), "p should be a Sophie Germain prime")
+	}
 }
 
 func TestGetSafePrime(t *testing.T) {
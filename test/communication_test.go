@@ -1,19 +1,25 @@
 package tests
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/xlab-si/emmy/client"
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/config"
 	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/encryption"
 	pb "github.com/xlab-si/emmy/protobuf"
 	"github.com/xlab-si/emmy/server"
+	"github.com/xlab-si/emmy/transport"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"io/ioutil"
 	"log"
 	"math"
 	"math/big"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -27,87 +33,223 @@ func setupTestGrpcServer() *grpc.Server {
 	testGrpcServer := grpc.NewServer(
 		grpc.MaxConcurrentStreams(math.MaxUint32),
 	)
-	pb.RegisterProtocolServer(testGrpcServer, server.NewProtocolServer())
+	protocolServer := server.NewProtocolServer()
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	if err := protocolServer.GenerateMissingKeys(&secParams); err != nil {
+		log.Fatal(err)
+	}
+	pb.RegisterProtocolServer(testGrpcServer, protocolServer)
 	go testGrpcServer.Serve(lis)
 	return testGrpcServer
 }
 
+// transportFactory creates a new transport to the test server for each protocol execution.
+type transportFactory func() (transport.Transport, error)
+
+// newGrpcTransport connects to the test gRPC server.
+func newGrpcTransport() (transport.Transport, error) {
+	return transport.DialGRPC(testGrpcServerEndpont)
+}
+
 func teardownTestGrpcServer(server *grpc.Server) {
 	server.GracefulStop()
 }
 
 func TestMain(m *testing.M) {
+	// keys of the test server are kept in a fresh key folder
+	keyDir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		log.Fatal(err)
+	}
+	config.Set("key_folder", keyDir)
+
 	server := setupTestGrpcServer()
 	returnCode := m.Run()
 	teardownTestGrpcServer(server)
+	os.RemoveAll(keyDir)
 	os.Exit(returnCode)
 }
 
-func testPedersen(n *big.Int) error {
-	dlog := config.LoadDLog("pedersen")
-	c, err := client.NewPedersenClient(testGrpcServerEndpont, pb.SchemaVariant_SIGMA, dlog, n)
+func testPedersen(newTransport transportFactory, n *big.Int) error {
+	t, err := newTransport()
+	if err != nil {
+		return err
+	}
+	dlog, err := config.LoadDLog("pedersen")
+	if err != nil {
+		return err
+	}
+	c, err := client.NewPedersenClient(t, pb.SchemaVariant_SIGMA, dlog, n)
 	if err != nil {
 		return err
 	}
 	return c.Run()
 }
 
-func testPedersenEC(n *big.Int) error {
-	c, err := client.NewPedersenECClient(testGrpcServerEndpont, n)
+func testPedersenEC(newTransport transportFactory, n *big.Int) error {
+	t, err := newTransport()
+	if err != nil {
+		return err
+	}
+	c, err := client.NewPedersenECClient(t, n)
 	if err != nil {
 		return err
 	}
 	return c.Run()
 }
 
-func testSchnorr(n *big.Int, variant pb.SchemaVariant) error {
-	dlog := config.LoadDLog("schnorr")
-	c, err := client.NewSchnorrClient(testGrpcServerEndpont, variant, dlog, n)
+func testSchnorr(newTransport transportFactory, n *big.Int, variant pb.SchemaVariant) error {
+	t, err := newTransport()
+	if err != nil {
+		return err
+	}
+	dlog, err := config.LoadDLog("schnorr")
+	if err != nil {
+		return err
+	}
+	c, err := client.NewSchnorrClient(t, variant, dlog, n)
 	if err != nil {
 		return err
 	}
 	return c.Run()
 }
 
-func testSchnorrEC(n *big.Int, variant pb.SchemaVariant) error {
+func testSchnorrEC(newTransport transportFactory, n *big.Int, variant pb.SchemaVariant) error {
+	t, err := newTransport()
+	if err != nil {
+		return err
+	}
 	ec_dlog := dlog.NewECDLog()
-	c, err := client.NewSchnorrECClient(testGrpcServerEndpont, variant, ec_dlog, n)
+	c, err := client.NewSchnorrECClient(t, variant, ec_dlog, n)
 	if err != nil {
 		return err
 	}
 	return c.Run()
 }
 
-func testCSPaillier(m, l *big.Int, pubKeyPath string) error {
-	c, err := client.NewCSPaillierClient(testGrpcServerEndpont, pubKeyPath, m, l)
+func testCSPaillier(newTransport transportFactory, m, l *big.Int, pubKeyPath string) error {
+	t, err := newTransport()
 	if err != nil {
 		return err
 	}
-	return c.Run()
+	c, err := client.NewCSPaillierClient(t, pubKeyPath, m, l)
+	if err != nil {
+		return err
+	}
+	res, err := c.RunWithContext(context.Background())
+	if err != nil {
+		return err
+	}
+	if !res.Verified {
+		return fmt.Errorf("Proof not verified: %v", res.FailureReason)
+	}
+	return nil
 }
 
 func TestGRPC_Commitments(t *testing.T) {
 	commitVal := big.NewInt(121212121)
 
-	assert.Nil(t, testPedersen(commitVal), "should finish without errors")
-	assert.Nil(t, testPedersenEC(commitVal), "should finish without errors")
+	assert.Nil(t, testPedersen(newGrpcTransport, commitVal), "should finish without errors")
+	assert.Nil(t, testPedersenEC(newGrpcTransport, commitVal), "should finish without errors")
 }
 
 func TestGRPC_Dlogproofs(t *testing.T) {
 	n := big.NewInt(345345345334)
 	desc := "should finish without errors"
 
-	assert.Nil(t, testSchnorr(n, pb.SchemaVariant_SIGMA), desc)
-	assert.Nil(t, testSchnorr(n, pb.SchemaVariant_ZKP), desc)
-	assert.Nil(t, testSchnorr(n, pb.SchemaVariant_ZKPOK), desc)
-	assert.Nil(t, testSchnorrEC(n, pb.SchemaVariant_SIGMA), desc)
-	assert.Nil(t, testSchnorrEC(n, pb.SchemaVariant_ZKP), desc)
-	assert.Nil(t, testSchnorrEC(n, pb.SchemaVariant_ZKPOK), desc)
+	assert.Nil(t, testSchnorr(newGrpcTransport, n, pb.SchemaVariant_SIGMA), desc)
+	assert.Nil(t, testSchnorr(newGrpcTransport, n, pb.SchemaVariant_ZKP), desc)
+	assert.Nil(t, testSchnorr(newGrpcTransport, n, pb.SchemaVariant_ZKPOK), desc)
+	assert.Nil(t, testSchnorrEC(newGrpcTransport, n, pb.SchemaVariant_SIGMA), desc)
+	assert.Nil(t, testSchnorrEC(newGrpcTransport, n, pb.SchemaVariant_ZKP), desc)
+	assert.Nil(t, testSchnorrEC(newGrpcTransport, n, pb.SchemaVariant_ZKPOK), desc)
+}
+
+func TestGRPC_SharedConnection(t *testing.T) {
+	conn, err := client.NewConnection(testGrpcServerEndpont)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	n := big.NewInt(345345345334)
+	errors := make(chan error, 5)
+	for i := 0; i < cap(errors); i++ {
+		go func() {
+			errors <- testSchnorr(conn.NewTransport, n, pb.SchemaVariant_ZKPOK)
+		}()
+	}
+	for i := 0; i < cap(errors); i++ {
+		assert.Nil(t, <-errors, "should finish without errors")
+	}
+}
+
+func TestGRPC_RunWithContext(t *testing.T) {
+	dlog, err := config.LoadDLog("schnorr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := big.NewInt(345345345334)
+
+	tr, err := newGrpcTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := client.NewSchnorrClient(tr, pb.SchemaVariant_ZKPOK, dlog, n)
+	c.RecordTranscript(true)
+	res, err := c.RunWithContext(context.Background())
+
+	assert.Nil(t, err, "should finish without errors")
+	assert.True(t, res.Verified, "proof should be verified")
+	assert.Equal(t, 3, len(res.Timings))
+	assert.Equal(t, "SchnorrProofData", res.Timings[2].Step)
+	assert.Equal(t, 6, len(res.Transcript))
+
+	// protocol should not proceed with a cancelled context
+	tr, err = newGrpcTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ = client.NewSchnorrClient(tr, pb.SchemaVariant_SIGMA, dlog, n)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err = c.RunWithContext(ctx)
+
+	assert.NotNil(t, err, "should finish with error")
+	assert.False(t, res.Verified, "proof should not be verified")
+	assert.NotEmpty(t, res.FailureReason)
 }
 
 func TestGRPC_Encryption(t *testing.T) {
 	m := common.GetRandomInt(big.NewInt(8685849))
 	l := common.GetRandomInt(big.NewInt(340002223232))
 
-	assert.NotNil(t, testCSPaillier(m, l, "testdata/cspaillierpubkey.txt"), "should finish with error")
+	// the client fetches server's public key
+	assert.Nil(t, testCSPaillier(newGrpcTransport, m, l, ""), "should finish without errors")
+
+	// the public key can also be distributed upfront
+	tr, err := newGrpcTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := client.FetchCSPaillierPubKey(context.Background(), tr, "")
+	assert.Nil(t, err, "fetching public key failed")
+	pubKeyPath := filepath.Join(config.LoadKeyDirFromConfig(), "fetchedpubkey.txt")
+	assert.Nil(t, encryption.NewCSPaillierFromPubKey(pubKey).StorePubKey(pubKeyPath))
+	assert.Nil(t, testCSPaillier(newGrpcTransport, m, l, pubKeyPath), "should finish without errors")
+
+	// an unrelated public key is not accepted
+	assert.NotNil(t, testCSPaillier(newGrpcTransport, m, l, "testdata/cspaillierpubkey.txt"), "should finish with error")
+
+	tr, err = newGrpcTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.FetchCSPaillierPubKey(context.Background(), tr, "unknown")
+	assert.NotNil(t, err, "fetching unknown key should fail")
 }
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/keystore"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	assert.Empty(t, config.Validate(), "default configuration should be valid")
+
+	dlog, err := config.LoadDLog("schnorr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1, _, err := config.LoadPseudonymsysOrgSecrets("org1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set("schnorr.q", dlog.OrderOfSubgroup.String())
+	defer config.Set("pseudonymsys.org1.s1", s1.String())
+
+	config.Set("schnorr.q", "98208916160055856584884864196345443685461747768186057136819930381973920107592")
+	config.Set("pseudonymsys.org1.s1", "12345")
+
+	problems := config.Validate()
+	assert.Len(t, problems, 4)
+	for _, problem := range problems {
+		t.Log(problem)
+	}
+}
+
+func TestValidateConfig_Tenants(t *testing.T) {
+	dlog, err := config.LoadDLog("schnorr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h1, h2, err := config.LoadPseudonymsysOrgPubKeys("org1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Set("tenants", map[string]interface{}{
+		"unit1": map[string]interface{}{
+			"schnorr": map[string]interface{}{
+				"p": dlog.P.String(),
+				"g": dlog.G.String(),
+				"q": "98208916160055856584884864196345443685461747768186057136819930381973920107592",
+			},
+			"pseudonymsys": map[string]interface{}{
+				"org1": map[string]interface{}{
+					"h1": h1.String(),
+					"h2": h2.String(),
+					"s1": "12345",
+					"s2": "12345",
+				},
+			},
+		},
+	})
+	defer config.Set("tenants", map[string]interface{}{})
+
+	tenant, err := config.LoadTenant("unit1")
+	assert.Nil(t, err)
+	tenantDLog, err := tenant.LoadDLog("pedersen")
+	assert.Nil(t, err)
+	assert.Equal(t, dlog.P, tenantDLog.P, "tenant should use global group parameters if it has none")
+	_, _, err = tenant.LoadPseudonymsysOrgPubKeys("org2")
+	assert.NotNil(t, err, "tenant should not see organizations of other tenants")
+
+	problems := config.Validate()
+	assert.Len(t, problems, 5)
+	for _, problem := range problems {
+		assert.Contains(t, problem.Error(), "tenants.unit1.")
+	}
+	config.Set("tenants", map[string]interface{}{"": map[string]interface{}{}})
+	assert.Len(t, config.Validate(), 1, "tenant with an empty ID should be reported")
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	assert.NotNil(t, config.LoadConfigFile(filepath.Join(dir, "emmy.yml")),
+		"loading a missing config file should fail")
+
+	// values are taken from flags (set with config.Set), environment variables, config
+	// files and defaults, in this order of precedence
+	assert.Equal(t, 8080, config.LoadGatewayPort(), "default value should be used")
+	path := filepath.Join(dir, "emmy.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("gateway_port: 8081\n"), 0600))
+	assert.Nil(t, config.LoadConfigFile(path), "loading config file failed")
+	assert.Equal(t, 8081, config.LoadGatewayPort(), "value from config file should override default")
+	os.Setenv("EMMY_GATEWAY_PORT", "8082")
+	defer os.Unsetenv("EMMY_GATEWAY_PORT")
+	assert.Equal(t, 8082, config.LoadGatewayPort(), "environment variable should override config file")
+	config.Set("gateway_port", 8083)
+	assert.Equal(t, 8083, config.LoadGatewayPort(), "flag should override environment variable")
+	config.Set("gateway_port", 8080)
+
+	// nested keys are overridden with their path joined by underscores
+	os.Setenv("EMMY_TRUSTEE_AUDIT_LOG", "/var/emmy/audit.log")
+	assert.Equal(t, "/var/emmy/audit.log", config.LoadTrusteeAuditLog())
+	os.Unsetenv("EMMY_TRUSTEE_AUDIT_LOG")
+	assert.NotEqual(t, "/var/emmy/audit.log", config.LoadTrusteeAuditLog())
+}
+
+func TestLoadConfigFromSearchPaths(t *testing.T) {
+	// emmy.yml in the current folder is found first, and decrypted transparently
+	err := keystore.Store([]byte("timeout: 7\n"), "emmy.yml", []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove("emmy.yml")
+	os.Setenv(keystore.PassphraseEnv, "passphrase")
+	defer os.Unsetenv(keystore.PassphraseEnv)
+
+	assert.Nil(t, config.LoadConfigFromSearchPaths(), "loading encrypted config file failed")
+	assert.Equal(t, 7.0, config.LoadTimeout())
+	config.Set("timeout", 5)
+}
+
+func TestLoadTrusteeLabels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// labels keep their case, unlike keys of config sections
+	path := filepath.Join(dir, "emmy.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`
+trustee:
+  labels:
+    - label: Case-2017-42
+      requesters: [unit1, unit2]
+      ciphertexts: [3a5f]
+`), 0600))
+	assert.Nil(t, config.LoadConfigFile(path), "loading config file failed")
+	defer config.Set("trustee.labels", []interface{}{})
+	config.Set("tenants", map[string]interface{}{
+		"unit1": map[string]interface{}{
+			"trustee": map[string]interface{}{
+				"labels": []interface{}{"Unit1-Case", map[interface{}]interface{}{
+					"label":       "Unit1-Case-2",
+					"ciphertexts": []interface{}{"3a5f"},
+				}},
+			},
+		},
+	})
+	defer config.Set("tenants", map[string]interface{}{})
+
+	labels, err := config.LoadTrusteeLabels()
+	assert.Nil(t, err, "loading trustee labels failed")
+	assert.Equal(t, []config.TrusteeLabel{
+		{Label: "Case-2017-42", Requesters: []string{"unit1", "unit2"}, Ciphertexts: []string{"3a5f"}},
+	}, labels)
+
+	tenant, err := config.LoadTenant("unit1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels, err = tenant.LoadTrusteeLabels()
+	assert.Nil(t, err, "loading tenant's trustee labels failed")
+	assert.Equal(t, []config.TrusteeLabel{
+		{Label: "Unit1-Case", Requesters: []string{"unit1"}},
+		{Label: "Unit1-Case-2", Requesters: []string{"unit1"}, Ciphertexts: []string{"3a5f"}},
+	}, labels)
+	assert.Empty(t, config.Validate())
+
+	// global labels need requesters, while tenants cannot grant decryption to others
+	config.Set("trustee.labels", []interface{}{"Case-2017-42"})
+	_, err = config.LoadTrusteeLabels()
+	assert.NotNil(t, err, "global label without requesters should be rejected")
+	config.Set("trustee.labels", map[string][]string{"case-2017-42": {"unit1"}})
+	_, err = config.LoadTrusteeLabels()
+	assert.NotNil(t, err, "labels should be listed, not given as a map")
+	config.Set("tenants.unit1.trustee.labels", []interface{}{
+		map[string]interface{}{"label": "Unit1-Case", "requesters": []string{"unit2"}},
+	})
+	_, err = tenant.LoadTrusteeLabels()
+	assert.NotNil(t, err, "tenant's label with requesters should be rejected")
+	assert.Len(t, config.Validate(), 2)
+}
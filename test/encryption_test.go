@@ -4,8 +4,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/dlog"
 	"github.com/xlab-si/emmy/encryption"
+	"github.com/xlab-si/emmy/keystore"
+	"io/ioutil"
 	"math/big"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -22,6 +26,90 @@ func TestPaillier(t *testing.T) {
 	assert.Equal(t, m, p, "Paillier encryption/decryption does not work correctly")
 }
 
+func TestPaillier_Homomorphic(t *testing.T) {
+	paillier := encryption.NewPaillier(512)
+	pubPaillier := encryption.NewPubPaillier(encryption.NewPaillierPubKey(
+		paillier.GetPubKey().GetN(), paillier.GetPubKey().GetG()))
+
+	m1 := common.GetRandomInt(big.NewInt(123412341234123))
+	m2 := common.GetRandomInt(big.NewInt(123412341234123))
+	c1, _ := pubPaillier.Encrypt(m1)
+	c2, _ := pubPaillier.Encrypt(m2)
+	k := big.NewInt(42)
+
+	sum, _ := paillier.Decrypt(pubPaillier.Add(c1, c2))
+	assert.Equal(t, new(big.Int).Add(m1, m2), sum, "addition of ciphertexts does not work correctly")
+	product, _ := paillier.Decrypt(pubPaillier.MulScalar(c1, k))
+	assert.Equal(t, new(big.Int).Mul(m1, k), product, "multiplication by scalar does not work correctly")
+	diff, _ := paillier.Decrypt(pubPaillier.Add(c1, pubPaillier.MulScalar(c1, big.NewInt(-1))))
+	assert.Equal(t, 0, diff.Sign(), "subtraction of ciphertexts does not work correctly")
+
+	c, _ := pubPaillier.Rerandomize(c2)
+	assert.NotEqual(t, c2, c, "re-randomized ciphertext should differ")
+	p, _ := paillier.Decrypt(c)
+	assert.Equal(t, m2, p, "re-randomized ciphertext should encrypt the same plaintext")
+}
+
+func TestPaillier_Proofs(t *testing.T) {
+	paillier := encryption.NewPaillier(512)
+	pubPaillier := encryption.NewPubPaillier(paillier.GetPubKey())
+	other := encryption.NewPubPaillier(encryption.NewPaillier(512).GetPubKey())
+
+	m := common.GetRandomInt(big.NewInt(123412341234123))
+	r := pubPaillier.GetRandomness()
+	c, _ := pubPaillier.EncryptWithRandomness(m, r)
+	knowledgeProof := pubPaillier.ProvePlaintextKnowledge(c, m, r)
+	assert.True(t, pubPaillier.VerifyPlaintextKnowledge(c, knowledgeProof), "proof of plaintext knowledge should verify")
+	assert.False(t, other.VerifyPlaintextKnowledge(c, knowledgeProof), "proof should not verify under a different key")
+
+	r0 := pubPaillier.GetRandomness()
+	c0, _ := pubPaillier.EncryptWithRandomness(big.NewInt(0), r0)
+	zeroProof := pubPaillier.ProveZero(c0, r0)
+	assert.True(t, pubPaillier.VerifyZero(c0, zeroProof), "proof of encryption of zero should verify")
+	assert.False(t, pubPaillier.VerifyZero(c, pubPaillier.ProveZero(c, r)), "encryption of non-zero should not verify")
+
+	// cc encrypts a * b, computed from ca and cb by the party knowing a
+	a := common.GetRandomInt(big.NewInt(123412341234123))
+	b := common.GetRandomInt(big.NewInt(123412341234123))
+	ra := pubPaillier.GetRandomness()
+	ca, _ := pubPaillier.EncryptWithRandomness(a, ra)
+	cb, _ := pubPaillier.Encrypt(b)
+	cc, rc := pubPaillier.Rerandomize(pubPaillier.MulScalar(cb, a))
+	mulProof := pubPaillier.ProveMultiplication(ca, a, ra, cb, cc, rc)
+	assert.True(t, pubPaillier.VerifyMultiplication(ca, cb, cc, mulProof), "proof of multiplication should verify")
+	wrong := pubPaillier.Add(cc, c)
+	assert.False(t, pubPaillier.VerifyMultiplication(ca, cb, wrong, mulProof), "wrong product should not verify")
+	product, _ := paillier.Decrypt(cc)
+	assert.Equal(t, new(big.Int).Mul(a, b), product)
+	assert.False(t, pubPaillier.VerifyMultiplication(ca, cb, wrong, nil), "missing proof should not verify")
+}
+
+func TestPaillier_CRT(t *testing.T) {
+	paillier := encryption.NewPaillier(512)
+	secKey := paillier.GetSecretKey()
+	assert.NotNil(t, secKey.P, "secret key should contain p")
+	assert.NotNil(t, secKey.Q, "secret key should contain q")
+
+	m := common.GetRandomInt(paillier.GetPubKey().GetN())
+	c, _ := paillier.Encrypt(m)
+	cCopy := new(big.Int).Set(c)
+	p, _ := paillier.Decrypt(c)
+	assert.Equal(t, m, p, "Paillier CRT decryption does not work correctly")
+	assert.Equal(t, cCopy, c, "decryption should not modify the ciphertext")
+
+	// keys without p and q (generated by older versions) decrypt with lambda
+	oldPaillier := encryption.NewPaillierFromSecretKey(&encryption.PaillierSecretKey{
+		N:      secKey.N,
+		G:      secKey.G,
+		Lambda: secKey.Lambda,
+	})
+	p, _ = oldPaillier.Decrypt(c)
+	assert.Equal(t, m, p, "Paillier decryption without p and q does not work correctly")
+
+	_, err := paillier.Decrypt(new(big.Int).Neg(c))
+	assert.NotNil(t, err, "negative ciphertext should not be decrypted")
+}
+
 func TestCSPaillier(t *testing.T) {
 	secParams := encryption.CSPaillierSecParams{
 		L:        512,
@@ -49,3 +137,379 @@ func TestCSPaillier(t *testing.T) {
 
 	assert.Equal(t, m, p, "Camenisch-Shoup modified Paillier encryption/decryption does not work correctly")
 }
+
+func TestCSPaillier_PublicVerification(t *testing.T) {
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	pubKey := encryption.NewCSPaillier(&secParams).PubKey
+
+	m := common.GetRandomInt(big.NewInt(8685849))
+	label := common.GetRandomInt(big.NewInt(340002223232))
+
+	// interactive proof verified by a third party holding only the public key
+	prover := encryption.NewCSPaillierFromPubKey(pubKey)
+	verifier := encryption.NewCSPaillierFromPubKey(pubKey)
+	u, e, v, _ := prover.Encrypt(m, label)
+	l, delta := prover.GetOpeningMsg(m)
+	verifier.SetVerifierEncData(u, e, v, delta, label, l)
+	u1, e1, v1, delta1, l1, _ := prover.GetProofRandomData(u, e, label)
+	c := verifier.GetChallenge()
+	verifier.SetProofRandomData(u1, e1, v1, delta1, l1, c)
+	rTilde, sTilde, mTilde := prover.GetProofData(c)
+	assert.True(t, verifier.Verify(rTilde, sTilde, mTilde),
+		"interactive proof should be verifiable with the public key only")
+
+	// non-interactive proof
+	u, e, v, delta, proof, err := prover.EncryptWithProof(m, label)
+	assert.Nil(t, err, "encryption with proof failed")
+	ok, err := verifier.VerifyProof(u, e, v, label, delta, proof)
+	assert.Nil(t, err)
+	assert.True(t, ok, "non-interactive proof should be verifiable with the public key only")
+
+	otherLabel := new(big.Int).Add(label, big.NewInt(1))
+	ok, _ = verifier.VerifyProof(u, e, v, otherLabel, delta, proof)
+	assert.False(t, ok, "proof should not verify for a different label")
+	otherDelta := new(big.Int).Mul(delta, pubKey.Gamma.G)
+	otherDelta.Mod(otherDelta, pubKey.Gamma.P)
+	ok, _ = verifier.VerifyProof(u, e, v, label, otherDelta, proof)
+	assert.False(t, ok, "proof should not verify for a different delta")
+
+	otherPubKey := encryption.NewCSPaillier(&secParams).PubKey
+	ok, _ = encryption.NewCSPaillierFromPubKey(otherPubKey).VerifyProof(u, e, v, label, delta, proof)
+	assert.False(t, ok, "proof should not verify under a different key")
+
+	_, err = verifier.VerifyProof(u, e, v, label, delta, &encryption.CSPaillierProof{})
+	assert.NotNil(t, err, "incomplete proof should be rejected")
+	_, err = verifier.VerifyProof(u, nil, v, label, delta, proof)
+	assert.NotNil(t, err, "incomplete ciphertext should be rejected")
+	_, err = verifier.VerifyProof(u, e, v, label, nil, proof)
+	assert.NotNil(t, err, "missing delta should be rejected")
+}
+
+func TestCSPaillier_CRT(t *testing.T) {
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	cspaillier := encryption.NewCSPaillier(&secParams)
+	secKey := cspaillier.SecretKey
+	assert.NotNil(t, secKey.P, "secret key should contain p")
+	assert.NotNil(t, secKey.Q, "secret key should contain q")
+
+	m := common.GetRandomInt(big.NewInt(8685849))
+	label := common.GetRandomInt(big.NewInt(340002223232))
+	u, e, v, _ := cspaillier.Encrypt(m, label)
+	p, err := cspaillier.Decrypt(u, e, v, label)
+	assert.Nil(t, err, "CSPaillier CRT decryption failed")
+	assert.Equal(t, m, p, "CSPaillier CRT decryption does not work correctly")
+
+	// keys without p and q (generated by older versions) still decrypt
+	oldSecKey := *secKey
+	oldSecKey.P, oldSecKey.Q = nil, nil
+	oldCSPaillier := encryption.NewCSPaillierFromSecretKey(&oldSecKey)
+	p, proof, err := oldCSPaillier.DecryptWithProof(u, e, v, label)
+	assert.Nil(t, err, "CSPaillier decryption without p and q failed")
+	assert.Equal(t, m, p, "CSPaillier decryption without p and q does not work correctly")
+	assert.True(t, cspaillier.VerifyDecryption(u, e, v, label, p, proof), "proof of decryption should verify")
+
+	_, err = cspaillier.Decrypt(u, e, v, new(big.Int).Add(label, big.NewInt(1)))
+	assert.NotNil(t, err, "decryption with a wrong label should fail")
+}
+
+func TestDamgardJurik(t *testing.T) {
+	dj, err := encryption.NewDamgardJurik(256, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDJ := encryption.NewPubDamgardJurik(encryption.NewDamgardJurikPubKey(dj.GetPubKey().GetN(), 3))
+
+	// plaintexts are longer than n
+	m1 := common.GetRandomInt(dj.GetPubKey().GetPlaintextSpace())
+	m2 := common.GetRandomInt(dj.GetPubKey().GetPlaintextSpace())
+	r := pubDJ.GetRandomness()
+	c1, _ := pubDJ.EncryptWithRandomness(m1, r)
+	c2, _ := pubDJ.Encrypt(m2)
+	p, err := dj.Decrypt(c1)
+	assert.Nil(t, err, "Damgard-Jurik decryption failed")
+	assert.Equal(t, m1, p, "Damgard-Jurik encryption/decryption does not work correctly")
+
+	ns := dj.GetPubKey().GetPlaintextSpace()
+	sum, _ := dj.Decrypt(pubDJ.Add(c1, c2))
+	assert.Equal(t, new(big.Int).Mod(new(big.Int).Add(m1, m2), ns), sum,
+		"addition of ciphertexts does not work correctly")
+	k := big.NewInt(-42)
+	product, _ := dj.Decrypt(pubDJ.MulScalar(c1, k))
+	assert.Equal(t, new(big.Int).Mod(new(big.Int).Mul(m1, k), ns), product,
+		"multiplication by scalar does not work correctly")
+	c, _ := pubDJ.Rerandomize(c2)
+	assert.NotEqual(t, c2, c, "re-randomized ciphertext should differ")
+	p, _ = dj.Decrypt(c)
+	assert.Equal(t, m2, p, "re-randomized ciphertext should encrypt the same plaintext")
+
+	proof := pubDJ.ProvePlaintextKnowledge(c1, m1, r)
+	assert.True(t, pubDJ.VerifyPlaintextKnowledge(c1, proof), "proof of plaintext knowledge should verify")
+	assert.False(t, pubDJ.VerifyPlaintextKnowledge(c2, proof), "proof should not verify for another ciphertext")
+
+	// with s = 1, Damgard-Jurik is Paillier with g = n + 1
+	dj1, _ := encryption.NewDamgardJurik(256, 1)
+	secKey := dj1.GetSecretKey()
+	paillier := encryption.NewPaillierFromSecretKey(&encryption.PaillierSecretKey{
+		N:      secKey.N,
+		G:      new(big.Int).Add(secKey.N, big.NewInt(1)),
+		Lambda: secKey.Lambda,
+		P:      secKey.P,
+		Q:      secKey.Q,
+	})
+	m := common.GetRandomInt(secKey.N)
+	c, _ = dj1.Encrypt(m)
+	p, _ = paillier.Decrypt(c)
+	assert.Equal(t, m, p, "Damgard-Jurik with s = 1 should be compatible with Paillier")
+}
+
+func TestElGamal(t *testing.T) {
+	group, err := dlog.NewZpSchnorr(160)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elgamal := encryption.NewElGamal(group)
+	pubElGamal := encryption.NewPubElGamal(elgamal.GetPubKey())
+
+	m1, _ := group.ExponentiateBaseG(common.GetRandomInt(group.GetOrderOfSubgroup()))
+	m2, _ := group.ExponentiateBaseG(common.GetRandomInt(group.GetOrderOfSubgroup()))
+	r := pubElGamal.GetRandomness()
+	c1, _ := pubElGamal.EncryptWithRandomness(m1, r)
+	c2, _ := pubElGamal.Encrypt(m2)
+	p, err := elgamal.Decrypt(c1)
+	assert.Nil(t, err, "ElGamal decryption failed")
+	assert.Equal(t, m1, p, "ElGamal encryption/decryption does not work correctly")
+	_, err = pubElGamal.Encrypt(new(big.Int).Sub(group.P, big.NewInt(1)))
+	assert.NotNil(t, err, "elements outside of the subgroup should not be encrypted")
+
+	product, _ := elgamal.Decrypt(pubElGamal.Multiply(c1, c2))
+	m, _ := group.Multiply(m1, m2)
+	assert.Equal(t, m, product, "multiplication of ciphertexts does not work correctly")
+	c, _ := pubElGamal.Rerandomize(c2)
+	assert.NotEqual(t, c2, c, "re-randomized ciphertext should differ")
+	p, _ = elgamal.Decrypt(c)
+	assert.Equal(t, m2, p, "re-randomized ciphertext should encrypt the same plaintext")
+
+	p, proof, err := elgamal.DecryptWithProof(c1)
+	assert.Nil(t, err, "ElGamal decryption with proof failed")
+	assert.True(t, pubElGamal.VerifyDecryption(c1, p, proof), "proof of decryption should verify")
+	assert.False(t, pubElGamal.VerifyDecryption(c1, m2, proof), "proof should not verify for another plaintext")
+
+	knowledgeProof := pubElGamal.ProvePlaintextKnowledge(c1, r)
+	assert.True(t, pubElGamal.VerifyPlaintextKnowledge(c1, knowledgeProof),
+		"proof of plaintext knowledge should verify")
+	assert.False(t, pubElGamal.VerifyPlaintextKnowledge(c2, knowledgeProof),
+		"proof should not verify for another ciphertext")
+
+	// exponential ElGamal is additively homomorphic
+	bound := big.NewInt(10000)
+	e1, _ := pubElGamal.EncryptExponential(big.NewInt(1234))
+	e2, _ := pubElGamal.EncryptExponential(big.NewInt(4321))
+	sum, err := elgamal.DecryptExponential(pubElGamal.Multiply(e1, e2), bound)
+	assert.Nil(t, err, "exponential ElGamal decryption failed")
+	assert.Equal(t, big.NewInt(5555), sum, "addition of exponential ciphertexts does not work correctly")
+	scaled, _ := elgamal.DecryptExponential(pubElGamal.Exponentiate(e1, big.NewInt(3)), bound)
+	assert.Equal(t, big.NewInt(3702), scaled, "multiplication by scalar does not work correctly")
+	_, err = elgamal.DecryptExponential(pubElGamal.Exponentiate(e1, big.NewInt(10)), bound)
+	assert.NotNil(t, err, "plaintexts not smaller than bound should not be decrypted")
+}
+
+func TestElGamalEC(t *testing.T) {
+	group := dlog.NewECDLog()
+	elgamal := encryption.NewElGamalEC(group)
+	pubElGamal := encryption.NewPubElGamalEC(elgamal.GetPubKey())
+
+	toPoint := func(x, y *big.Int) *common.ECGroupElement {
+		return &common.ECGroupElement{X: x, Y: y}
+	}
+	m1 := toPoint(group.ExponentiateBaseG(common.GetRandomInt(group.GetOrderOfSubgroup())))
+	m2 := toPoint(group.ExponentiateBaseG(common.GetRandomInt(group.GetOrderOfSubgroup())))
+	r := pubElGamal.GetRandomness()
+	c1, _ := pubElGamal.EncryptWithRandomness(m1, r)
+	c2, _ := pubElGamal.Encrypt(m2)
+	p, err := elgamal.Decrypt(c1)
+	assert.Nil(t, err, "ElGamal decryption failed")
+	assert.Equal(t, m1, p, "ElGamal encryption/decryption does not work correctly")
+
+	product, _ := elgamal.Decrypt(pubElGamal.Multiply(c1, c2))
+	assert.Equal(t, toPoint(group.Multiply(m1.X, m1.Y, m2.X, m2.Y)), product,
+		"multiplication of ciphertexts does not work correctly")
+	c, _ := pubElGamal.Rerandomize(c2)
+	assert.NotEqual(t, c2, c, "re-randomized ciphertext should differ")
+	p, _ = elgamal.Decrypt(c)
+	assert.Equal(t, m2, p, "re-randomized ciphertext should encrypt the same plaintext")
+
+	p, proof, err := elgamal.DecryptWithProof(c1)
+	assert.Nil(t, err, "ElGamal decryption with proof failed")
+	assert.True(t, pubElGamal.VerifyDecryption(c1, p, proof), "proof of decryption should verify")
+	assert.False(t, pubElGamal.VerifyDecryption(c1, m2, proof), "proof should not verify for another plaintext")
+
+	knowledgeProof := pubElGamal.ProvePlaintextKnowledge(c1, r)
+	assert.True(t, pubElGamal.VerifyPlaintextKnowledge(c1, knowledgeProof),
+		"proof of plaintext knowledge should verify")
+	assert.False(t, pubElGamal.VerifyPlaintextKnowledge(c2, knowledgeProof),
+		"proof should not verify for another ciphertext")
+
+	bound := big.NewInt(10000)
+	e1, _ := pubElGamal.EncryptExponential(big.NewInt(1234))
+	e2, _ := pubElGamal.EncryptExponential(big.NewInt(0))
+	sum, err := elgamal.DecryptExponential(pubElGamal.Multiply(e1, e2), bound)
+	assert.Nil(t, err, "exponential ElGamal decryption failed")
+	assert.Equal(t, big.NewInt(1234), sum, "addition of exponential ciphertexts does not work correctly")
+	scaled, _ := elgamal.DecryptExponential(pubElGamal.Exponentiate(e1, big.NewInt(3)), bound)
+	assert.Equal(t, big.NewInt(3702), scaled, "multiplication by scalar does not work correctly")
+}
+
+func TestThresholdPaillier(t *testing.T) {
+	pubKey, keyShares, err := encryption.NewThresholdPaillierKeys(256, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, keyShares, 3)
+
+	m := common.GetRandomInt(pubKey.N)
+	c, _ := encryption.NewPubPaillier(pubKey.GetPaillierPubKey()).Encrypt(m)
+
+	shares := make([]*encryption.PaillierDecryptionShare, len(keyShares))
+	for i, keyShare := range keyShares {
+		shares[i], err = keyShare.Decrypt(c)
+		assert.Nil(t, err, "computing decryption share failed")
+		assert.True(t, pubKey.VerifyDecryptionShare(c, shares[i]), "decryption share should verify")
+	}
+
+	// any two shares decrypt
+	for _, pair := range [][]int{{0, 1}, {0, 2}, {2, 1}} {
+		p, err := pubKey.Combine(c, []*encryption.PaillierDecryptionShare{shares[pair[0]], shares[pair[1]]})
+		assert.Nil(t, err, "combining decryption shares failed")
+		assert.Equal(t, m, p, "threshold Paillier decryption does not work correctly")
+	}
+
+	// invalid and repeated shares are not counted
+	forged := *shares[1]
+	forged.C = new(big.Int).Add(forged.C, big.NewInt(1))
+	assert.False(t, pubKey.VerifyDecryptionShare(c, &forged), "forged decryption share should not verify")
+	_, err = pubKey.Combine(c, []*encryption.PaillierDecryptionShare{shares[0], &forged, shares[0]})
+	assert.NotNil(t, err, "combining less than threshold valid shares should fail")
+	p, err := pubKey.Combine(c, []*encryption.PaillierDecryptionShare{&forged, shares[0], shares[2]})
+	assert.Nil(t, err, "combining decryption shares failed")
+	assert.Equal(t, m, p, "threshold Paillier decryption with a forged share does not work correctly")
+
+	// key shares can be stored and loaded
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "share.pem")
+	assert.Nil(t, keyShares[2].Store(path, []byte("passphrase")), "storing key share failed")
+	os.Setenv(keystore.PassphraseEnv, "passphrase")
+	defer os.Unsetenv(keystore.PassphraseEnv)
+	loaded, err := encryption.NewThresholdPaillierKeyShareFromFile(path)
+	if assert.Nil(t, err, "loading key share failed") {
+		share, _ := loaded.Decrypt(c)
+		assert.True(t, pubKey.VerifyDecryptionShare(c, share), "share of loaded key should verify")
+	}
+}
+
+func TestThresholdElGamal(t *testing.T) {
+	group, err := dlog.NewZpSchnorr(160)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, keyShares, err := encryption.NewThresholdElGamalKeys(group, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, keyShares, 5)
+
+	// tally of yes votes: the product of encrypted votes encrypts their sum
+	elgamal := encryption.NewPubElGamal(pubKey.GetElGamalPubKey())
+	votes := []int64{1, 0, 1, 1, 0, 1}
+	c, _ := elgamal.EncryptExponential(big.NewInt(votes[0]))
+	for _, vote := range votes[1:] {
+		v, _ := elgamal.EncryptExponential(big.NewInt(vote))
+		c = elgamal.Multiply(c, v)
+	}
+
+	shares := make([]*encryption.ElGamalDecryptionShare, len(keyShares))
+	for i, keyShare := range keyShares {
+		shares[i], err = keyShare.Decrypt(c)
+		assert.Nil(t, err, "computing decryption share failed")
+		assert.True(t, pubKey.VerifyDecryptionShare(c, shares[i]), "decryption share should verify")
+	}
+	bound := big.NewInt(int64(len(votes) + 1))
+	for _, indices := range [][]int{{0, 1, 2}, {4, 0, 3}, {1, 3, 4}} {
+		subset := []*encryption.ElGamalDecryptionShare{shares[indices[0]], shares[indices[1]], shares[indices[2]]}
+		tally, err := pubKey.CombineExponential(c, subset, bound)
+		assert.Nil(t, err, "combining decryption shares failed")
+		assert.Equal(t, big.NewInt(4), tally, "threshold ElGamal decryption does not work correctly")
+	}
+
+	// invalid and repeated shares are not counted
+	forged := *shares[1]
+	forged.D, _ = group.Multiply(forged.D, group.G)
+	assert.False(t, pubKey.VerifyDecryptionShare(c, &forged), "forged decryption share should not verify")
+	_, err = pubKey.Combine(c, []*encryption.ElGamalDecryptionShare{shares[0], &forged, shares[0], shares[2]})
+	assert.NotNil(t, err, "combining less than threshold valid shares should fail")
+
+	// plain ElGamal ciphertexts are decrypted as well
+	m, _ := group.ExponentiateBaseG(common.GetRandomInt(group.GetOrderOfSubgroup()))
+	c, _ = elgamal.Encrypt(m)
+	shares = shares[:0]
+	for _, keyShare := range keyShares[2:] {
+		share, _ := keyShare.Decrypt(c)
+		shares = append(shares, share)
+	}
+	p, err := pubKey.Combine(c, shares)
+	assert.Nil(t, err, "combining decryption shares failed")
+	assert.Equal(t, m, p, "threshold ElGamal decryption does not work correctly")
+
+	// key shares can be stored and loaded
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "share.pem")
+	assert.Nil(t, keyShares[4].Store(path, nil), "storing key share failed")
+	loaded, err := encryption.NewThresholdElGamalKeyShareFromFile(path)
+	if assert.Nil(t, err, "loading key share failed") {
+		share, _ := loaded.Decrypt(c)
+		assert.True(t, pubKey.VerifyDecryptionShare(c, share), "share of loaded key should verify")
+	}
+}
+
+func TestPaillier_StoreAndLoadKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secKeyPath := filepath.Join(dir, "paillierseckey.txt")
+	pubKeyPath := filepath.Join(dir, "paillierpubkey.txt")
+
+	paillier := encryption.NewPaillier(512)
+	assert.Nil(t, paillier.StoreSecKey(secKeyPath), "storing secret key failed")
+	assert.Nil(t, paillier.StorePubKey(pubKeyPath), "storing public key failed")
+
+	pubPaillier, err := encryption.NewPaillierFromPubKeyFile(pubKeyPath)
+	assert.Nil(t, err, "loading public key failed")
+	secPaillier, err := encryption.NewPaillierFromSecKey(secKeyPath)
+	assert.Nil(t, err, "loading secret key failed")
+
+	m := common.GetRandomInt(big.NewInt(123412341234123))
+	c, _ := pubPaillier.Encrypt(m)
+	p, _ := secPaillier.Decrypt(c)
+
+	assert.Equal(t, m, p, "Paillier decryption with loaded secret key does not work correctly")
+	assert.NotNil(t, secPaillier.GetSecretKey().P, "loaded secret key should contain p")
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/encryption"
+	"github.com/xlab-si/emmy/pseudonymsys"
+	"github.com/xlab-si/emmy/signatures"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormat_PaillierKeys(t *testing.T) {
+	paillier := encryption.NewPaillier(512)
+
+	pubData, err := common.MarshalPEM(paillier.GetPubKey())
+	assert.Nil(t, err, "PEM encoding of public key failed")
+	var pubKey encryption.PaillierPubKey
+	assert.Nil(t, common.UnmarshalPEM(pubData, &pubKey), "PEM decoding of public key failed")
+
+	secData, err := json.Marshal(paillier.GetSecretKey())
+	assert.Nil(t, err, "JSON encoding of secret key failed")
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secKeyPath := filepath.Join(dir, "paillierseckey.json")
+	common.Store(secData, secKeyPath)
+	secPaillier, err := encryption.NewPaillierFromSecKey(secKeyPath)
+	assert.Nil(t, err, "loading JSON encoded secret key failed")
+
+	m := common.GetRandomInt(big.NewInt(123412341234123))
+	c, _ := encryption.NewPubPaillier(&pubKey).Encrypt(m)
+	p, _ := secPaillier.Decrypt(c)
+	assert.Equal(t, m, p, "Paillier does not work with decoded keys")
+}
+
+func TestFormat_DamgardJurikKeys(t *testing.T) {
+	dj, _ := encryption.NewDamgardJurik(256, 2)
+
+	pubData, err := common.MarshalPEM(dj.GetPubKey())
+	assert.Nil(t, err, "PEM encoding of public key failed")
+	var pubKey encryption.DamgardJurikPubKey
+	assert.Nil(t, common.UnmarshalPEM(pubData, &pubKey), "PEM decoding of public key failed")
+	assert.Equal(t, 2, pubKey.GetS())
+
+	secData, err := json.Marshal(dj.GetSecretKey())
+	assert.Nil(t, err, "JSON encoding of secret key failed")
+	var secKey encryption.DamgardJurikSecretKey
+	assert.Nil(t, json.Unmarshal(secData, &secKey), "JSON decoding of secret key failed")
+	secDJ, err := encryption.NewDamgardJurikFromSecretKey(&secKey)
+	assert.Nil(t, err, "secret key should be valid")
+
+	m := common.GetRandomInt(pubKey.GetPlaintextSpace())
+	c, _ := encryption.NewPubDamgardJurik(&pubKey).Encrypt(m)
+	p, _ := secDJ.Decrypt(c)
+	assert.Equal(t, m, p, "Damgard-Jurik does not work with decoded keys")
+}
+
+func TestFormat_CLSignature(t *testing.T) {
+	cl := signatures.NewCL(1)
+	m_Ls := []*big.Int{common.GetRandomInt(big.NewInt(1234567))}
+	signature, _ := cl.Sign(m_Ls)
+
+	pubData, _ := json.Marshal(cl.GetPubKey())
+	sigData, _ := common.MarshalPEM(signature)
+
+	var pubKey signatures.CLPubKey
+	assert.Nil(t, json.Unmarshal(pubData, &pubKey), "decoding of public key failed")
+	var decodedSignature signatures.CLSignature
+	assert.Nil(t, common.UnmarshalPEM(sigData, &decodedSignature), "decoding of signature failed")
+
+	ok, _ := signatures.NewPubCL(&pubKey).Verify(m_Ls, &decodedSignature)
+	assert.True(t, ok, "decoded CL signature does not verify")
+}
+
+func TestFormat_Errors(t *testing.T) {
+	group, err := config.LoadDLog("pseudonymsys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(group)
+	var decoded dlog.ZpDLog
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, group, &decoded, "decoded group parameters differ")
+
+	var nym pseudonymsys.Pseudonym
+	assert.NotNil(t, json.Unmarshal(data, &nym), "decoding object of wrong type should fail")
+
+	var keys pseudonymsys.OrgPubKeys
+	err = json.Unmarshal([]byte(`{"version":1,"type":"pseudonymsys-org-public-key","value":{"h1":"12"}}`), &keys)
+	assert.NotNil(t, err, "decoding object with missing fields should fail")
+	err = json.Unmarshal([]byte(`{"version":2,"type":"pseudonymsys-org-public-key","value":{"h1":"12","h2":"3"}}`), &keys)
+	assert.NotNil(t, err, "decoding object of unsupported version should fail")
+}
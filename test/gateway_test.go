@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/commitments"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/gateway"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/server"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// postMessage posts a JSON encoded message to the gateway and decodes the reply.
+func postMessage(t *testing.T, url string, msg *pb.Message) (string, *pb.Message, bool) {
+	body, err := (&jsonpb.Marshaler{}).MarshalToString(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Gateway responded with status %v", resp.StatusCode)
+	}
+
+	var reply struct {
+		SessionId string          `json:"sessionId"`
+		Message   json.RawMessage `json:"message"`
+		Finished  bool            `json:"finished"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		t.Fatal(err)
+	}
+	replyMsg := &pb.Message{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(reply.Message), replyMsg); err != nil {
+		t.Fatal(err)
+	}
+	return reply.SessionId, replyMsg, reply.Finished
+}
+
+func TestGateway_Pedersen(t *testing.T) {
+	gw := httptest.NewServer(gateway.NewGateway(server.NewProtocolServer()))
+	defer gw.Close()
+
+	dlog, err := config.LoadDLog("pedersen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	committer := commitments.NewPedersenCommitter(dlog)
+
+	id, resp, _ := postMessage(t, gw.URL+"/sessions", &pb.Message{
+		Schema:  pb.SchemaType_PEDERSEN,
+		Content: &pb.Message_Empty{&pb.EmptyMsg{}},
+	})
+	committer.SetH(new(big.Int).SetBytes(resp.GetPedersenFirst().H))
+
+	commitment, _ := committer.GetCommitMsg(big.NewInt(121212121))
+	postMessage(t, gw.URL+"/sessions/"+id, &pb.Message{
+		Content: &pb.Message_Bigint{&pb.BigInt{X1: commitment.Bytes()}},
+	})
+
+	val, r := committer.GetDecommitMsg()
+	_, resp, finished := postMessage(t, gw.URL+"/sessions/"+id, &pb.Message{
+		Content: &pb.Message_PedersenDecommitment{
+			&pb.PedersenDecommitment{X: val.Bytes(), R: r.Bytes()},
+		},
+	})
+
+	assert.True(t, finished, "Session should be finished after the status message")
+	assert.True(t, resp.GetStatus().Success, "Pedersen protocol over gateway failed")
+
+	// finished sessions are discarded
+	res, err := http.Post(gw.URL+"/sessions/"+id, "application/json", bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
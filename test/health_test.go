@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/server"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestGRPC_Health(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyDir := config.LoadKeyDirFromConfig()
+	config.Set("key_folder", dir)
+	defer config.Set("key_folder", keyDir)
+
+	// checkHealth starts emmy server with the keys from dir and returns the serving
+	// status reported for the protocol service, and the services listed by reflection
+	checkHealth := func() (healthpb.HealthCheckResponse_ServingStatus, []string) {
+		s := server.NewProtocolServer()
+		lis, err := net.Listen("tcp", ":7013")
+		if err != nil {
+			t.Fatal(err)
+		}
+		grpcServer := grpc.NewServer()
+		pb.RegisterProtocolServer(grpcServer, s)
+		server.RegisterHealthAndReflection(grpcServer, s)
+		go grpcServer.Serve(lis)
+		defer grpcServer.Stop()
+
+		conn, err := grpc.Dial("localhost:7013", grpc.WithInsecure())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+			&healthpb.HealthCheckRequest{Service: server.ProtocolServiceName})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reflectionResp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var services []string
+		for _, service := range reflectionResp.GetListServicesResponse().GetService() {
+			services = append(services, service.Name)
+		}
+		return resp.Status, services
+	}
+
+	// without CSPaillier keys, emmy server is not ready
+	status, services := checkHealth()
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status)
+	assert.Contains(t, services, server.ProtocolServiceName, "protocol service should be listed by reflection")
+	assert.Contains(t, services, "grpc.health.v1.Health", "health service should be listed by reflection")
+
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	assert.Nil(t, server.NewKeyStore(dir).Generate("", &secParams))
+	status, _ = checkHealth()
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status)
+}
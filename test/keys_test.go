@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/encryption"
+	"github.com/xlab-si/emmy/server"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyStore_Rotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	oldKey := encryption.NewCSPaillier(&secParams)
+	newKey := encryption.NewCSPaillier(&secParams)
+	assert.Nil(t, oldKey.StoreSecKey(filepath.Join(dir, "cspaillierseckey.txt")))
+	assert.Nil(t, oldKey.StoreSecKey(filepath.Join(dir, "cspaillierseckey-2017.txt")))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "cspaillierpubkey.txt"), []byte("not a key"), 0644))
+
+	keys := server.NewKeyStore(dir)
+	assert.Nil(t, keys.Reload(), "loading keys failed")
+	assert.Equal(t, []string{"", "2017"}, keys.KeyIds())
+
+	// add a new key, then retire the old one
+	assert.Nil(t, newKey.StoreSecKey(filepath.Join(dir, "cspaillierseckey-2018.txt")))
+	assert.Nil(t, keys.Reload(), "reloading keys failed")
+	assert.Equal(t, []string{"", "2017", "2018"}, keys.KeyIds())
+	key, err := keys.Get("2018")
+	assert.Nil(t, err)
+	assert.Equal(t, newKey.SecretKey.N, key.N, "wrong key for key ID")
+
+	err = os.Rename(filepath.Join(dir, "cspaillierseckey-2017.txt"),
+		filepath.Join(dir, "cspaillierseckey-2017.txt.retired"))
+	assert.Nil(t, err)
+	assert.Nil(t, keys.Reload(), "reloading keys failed")
+	assert.Equal(t, []string{"", "2018"}, keys.KeyIds())
+	_, err = keys.Get("2017")
+	assert.EqualError(t, err, `CSPaillier key "2017" is retired`)
+	_, err = keys.Get("2019")
+	assert.EqualError(t, err, `Unknown CSPaillier key "2019"`)
+
+	keys.Retire("")
+	_, err = keys.Get("")
+	assert.NotNil(t, err, "retired key should not be returned")
+
+	// keys with only the public part can be used for verification, but not for decryption
+	assert.Nil(t, newKey.StorePubKey(filepath.Join(dir, "cspaillierpubkey-2019.txt")))
+	assert.Nil(t, keys.Reload(), "reloading keys failed")
+	assert.Equal(t, []string{"", "2018", "2019"}, keys.KeyIds())
+	pubKey, err := keys.GetPubKey("2019")
+	assert.Nil(t, err)
+	assert.Equal(t, newKey.PubKey.Y1, pubKey.Y1, "wrong public key for key ID")
+	_, err = keys.Get("2019")
+	assert.EqualError(t, err, `No secret key for CSPaillier key "2019"`)
+	pubKey, err = keys.GetPubKey("2018")
+	assert.Nil(t, err)
+	assert.Equal(t, newKey.PubKey.Y3, pubKey.Y3, "public key should be derived from the secret key")
+
+	// keys that fail to load are reported, but don't prevent loading other keys
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "cspaillierseckey-broken.txt"), []byte("?"), 0600))
+	assert.NotNil(t, keys.Reload(), "broken key should be reported")
+	assert.Equal(t, []string{"", "2018", "2019"}, keys.KeyIds())
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/encryption"
+	"github.com/xlab-si/emmy/keystore"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secret")
+
+	secret := []byte("some secret key material")
+	err = keystore.Store(secret, path, []byte("passphrase"))
+	assert.Nil(t, err, "storing encrypted secret failed")
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(keystore.FileMode), info.Mode().Perm(), "file should only be accessible by owner")
+
+	data, _ := ioutil.ReadFile(path)
+	assert.True(t, keystore.IsEncrypted(data), "stored secret should be encrypted")
+	assert.NotContains(t, string(data), string(secret))
+
+	decrypted, err := keystore.Decrypt(data, []byte("passphrase"))
+	assert.Nil(t, err, "decryption failed")
+	assert.Equal(t, secret, decrypted)
+
+	_, err = keystore.Decrypt(data, []byte("wrong passphrase"))
+	assert.Equal(t, keystore.ErrWrongPassphrase, err, "decryption with wrong passphrase should fail")
+
+	// files demanding excessive resources for key derivation are rejected
+	crafted := strings.Replace(string(data), `"n": 32768`, `"n": 1073741824`, 1)
+	_, err = keystore.Decrypt([]byte(crafted), []byte("passphrase"))
+	assert.NotNil(t, err, "decryption with excessive scrypt parameters should fail")
+	assert.NotEqual(t, keystore.ErrWrongPassphrase, err)
+}
+
+func TestCSPaillier_EncryptedSecKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secKeyPath := filepath.Join(dir, "cspaillierseckey.txt")
+	pubKeyPath := filepath.Join(dir, "cspaillierpubkey.txt")
+
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	cspaillier := encryption.NewCSPaillier(&secParams)
+	assert.Nil(t, cspaillier.StoreEncryptedSecKey(secKeyPath, []byte("passphrase")))
+	assert.Nil(t, cspaillier.StorePubKey(pubKeyPath))
+
+	os.Setenv(keystore.PassphraseEnv, "passphrase")
+	defer os.Unsetenv(keystore.PassphraseEnv)
+
+	cspaillierPub, _ := encryption.NewCSPaillierFromPubKeyFile(pubKeyPath)
+	m := common.GetRandomInt(big.NewInt(8685849))
+	label := common.GetRandomInt(big.NewInt(340002223232))
+	u, e, v, _ := cspaillierPub.Encrypt(m, label)
+
+	cspaillierSec, err := encryption.NewCSPaillierFromSecKey(secKeyPath)
+	assert.Nil(t, err, "loading encrypted secret key failed")
+	p, _ := cspaillierSec.Decrypt(u, e, v, label)
+	assert.Equal(t, m, p, "decryption with encrypted secret key does not work correctly")
+}
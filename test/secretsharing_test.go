@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/commitments"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/secretsharing"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestFeldmanVSS(t *testing.T) {
+	group, err := dlog.NewZpSchnorr(160)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dealer, _ := secretsharing.NewDealer()
+	secret := common.GetRandomInt(group.GetOrderOfSubgroup())
+	shares, c, err := dealer.SplitFeldman(secret, group, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, shares, 5)
+	assert.Equal(t, 3, c.Threshold())
+	for _, share := range shares {
+		assert.True(t, c.Verify(share), "share should be consistent with the commitments")
+	}
+
+	points := make(map[*big.Int]*big.Int)
+	for _, share := range shares[1:4] {
+		points[big.NewInt(int64(share.Index))] = share.Value
+	}
+	assert.Equal(t, secret, common.LagrangeInterpolation(big.NewInt(0), points, group.GetOrderOfSubgroup()),
+		"threshold shares should determine the secret")
+
+	// a share that the dealer modified is detected
+	forged := *shares[2]
+	forged.Value = new(big.Int).Add(forged.Value, big.NewInt(1))
+	assert.False(t, c.Verify(&forged), "inconsistent share should not verify")
+	forged = *shares[2]
+	forged.Index = 4
+	assert.False(t, c.Verify(&forged), "share at another index should not verify")
+
+	// commitments can be broadcast as JSON
+	data, err := json.Marshal(c)
+	assert.Nil(t, err, "encoding commitments failed")
+	var decoded secretsharing.FeldmanCommitments
+	assert.Nil(t, json.Unmarshal(data, &decoded), "decoding commitments failed")
+	assert.True(t, decoded.Verify(shares[4]), "share should verify against decoded commitments")
+	assert.NotNil(t, json.Unmarshal([]byte(`{"version":1,"type":"feldman-commitments","value":{}}`),
+		&decoded), "commitments with missing fields should not decode")
+
+	_, _, err = dealer.SplitFeldman(group.GetOrderOfSubgroup(), group, 3, 5)
+	assert.NotNil(t, err, "secret outside of Z_q should not be shared")
+}
+
+func TestPedersenVSS(t *testing.T) {
+	group, err := dlog.NewZpSchnorr(160)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// h is generated by a shareholder, so that the dealer does not know log_g(h)
+	h := commitments.NewPedersenReceiver(group).GetH()
+	dealer, _ := secretsharing.NewDealer()
+	secret := common.GetRandomInt(group.GetOrderOfSubgroup())
+	shares, c, err := dealer.SplitPedersen(secret, group, h, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, shares, 4)
+	for _, share := range shares {
+		assert.True(t, c.Verify(share), "share should be consistent with the commitments")
+	}
+
+	points := map[*big.Int]*big.Int{
+		big.NewInt(int64(shares[3].Index)): shares[3].Value,
+		big.NewInt(int64(shares[0].Index)): shares[0].Value,
+	}
+	assert.Equal(t, secret, common.LagrangeInterpolation(big.NewInt(0), points, group.GetOrderOfSubgroup()),
+		"threshold shares should determine the secret")
+
+	forged := *shares[1]
+	forged.Blinding = new(big.Int).Add(forged.Blinding, big.NewInt(1))
+	assert.False(t, c.Verify(&forged), "inconsistent share should not verify")
+
+	data, err := json.Marshal(c)
+	assert.Nil(t, err, "encoding commitments failed")
+	var decoded secretsharing.PedersenCommitments
+	assert.Nil(t, json.Unmarshal(data, &decoded), "decoding commitments failed")
+	assert.True(t, decoded.Verify(shares[2]), "share should verify against decoded commitments")
+	decoded.H, _ = group.ExponentiateBaseG(big.NewInt(2))
+	assert.False(t, decoded.Verify(shares[2]), "share should not verify with another h")
+}
+
+func TestRecoverInt(t *testing.T) {
+	prime, _ := rand.Prime(rand.Reader, 128)
+	dealer, _ := secretsharing.NewDealer()
+	secret := common.GetRandomInt(prime)
+	shares, err := dealer.SplitInt(secret, prime, 3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, faulty, err := dealer.RecoverInt(shares, prime, 3)
+	assert.Nil(t, err, "recovering the secret failed")
+	assert.Equal(t, secret, s)
+	assert.Empty(t, faulty, "no share should be faulty")
+
+	// up to (7 - 3) / 2 = 2 faulty shares are corrected
+	corrupted := make([]*secretsharing.Share, len(shares))
+	copy(corrupted, shares)
+	corrupted[5] = &secretsharing.Share{Index: 6, Value: common.GetRandomInt(prime)}
+	corrupted[1] = &secretsharing.Share{Index: 2, Value: big.NewInt(0)}
+	s, faulty, err = dealer.RecoverInt(corrupted, prime, 3)
+	assert.Nil(t, err, "recovering the secret failed")
+	assert.Equal(t, secret, s, "faulty shares should be corrected")
+	assert.Equal(t, []int{2, 6}, faulty, "faulty shares should be reported")
+
+	corrupted[3] = &secretsharing.Share{Index: 4, Value: big.NewInt(42)}
+	_, _, err = dealer.RecoverInt(corrupted, prime, 3)
+	assert.NotNil(t, err, "too many faulty shares should be detected")
+
+	// with threshold + 1 shares, faulty shares are detected, but cannot be corrected
+	_, _, err = dealer.RecoverInt(corrupted[:4], prime, 3)
+	assert.NotNil(t, err, "faulty share should be detected")
+}
+
+func TestVSS_Recover(t *testing.T) {
+	group, err := dlog.NewZpSchnorr(160)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dealer, _ := secretsharing.NewDealer()
+	secret := common.GetRandomInt(group.GetOrderOfSubgroup())
+	shares, c, _ := dealer.SplitFeldman(secret, group, 3, 5)
+
+	// only threshold valid shares are needed, as faulty ones are detected
+	forged := &secretsharing.Share{Index: 1, Value: big.NewInt(1)}
+	s, faulty, err := c.Recover([]*secretsharing.Share{forged, shares[4], shares[2], shares[3]})
+	assert.Nil(t, err, "recovering the secret failed")
+	assert.Equal(t, secret, s)
+	assert.Equal(t, []int{1}, faulty, "faulty share should be reported")
+	_, faulty, err = c.Recover([]*secretsharing.Share{forged, shares[4], shares[2]})
+	assert.NotNil(t, err, "less than threshold valid shares should not recover the secret")
+	assert.Equal(t, []int{1}, faulty, "faulty share should be reported")
+
+	// a faulty share does not hide a valid share with the same index
+	s, faulty, err = c.Recover([]*secretsharing.Share{forged, shares[0], shares[4], shares[2]})
+	assert.Nil(t, err, "recovering the secret failed")
+	assert.Equal(t, secret, s)
+	assert.Equal(t, []int{1}, faulty, "faulty share should be reported")
+
+	h := commitments.NewPedersenReceiver(group).GetH()
+	pedersenShares, pc, _ := dealer.SplitPedersen(secret, group, h, 2, 3)
+	pedersenForged := *pedersenShares[1]
+	pedersenForged.Value = new(big.Int).Add(pedersenForged.Value, big.NewInt(1))
+	s, faulty, err = pc.Recover([]*secretsharing.PedersenShare{pedersenShares[0], &pedersenForged,
+		pedersenShares[2]})
+	assert.Nil(t, err, "recovering the secret failed")
+	assert.Equal(t, secret, s)
+	assert.Equal(t, []int{2}, faulty, "faulty share should be reported")
+}
+
+func TestSplitBytes(t *testing.T) {
+	dealer, _ := secretsharing.NewDealer()
+	for _, length := range []int{0, 1, 31, 32, 100} {
+		secret := make([]byte, length)
+		rand.Read(secret)
+		shares, err := dealer.SplitBytes(secret, 3, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recovered, faulty, err := dealer.CombineBytes([]*secretsharing.ByteShare{shares[4], shares[0], shares[2]})
+		assert.Nil(t, err, "combining shares failed")
+		assert.Equal(t, secret, recovered, "secret of length %d should be recovered", length)
+		assert.Empty(t, faulty)
+	}
+
+	// short secrets can be shared among many shareholders
+	shares, err := dealer.SplitBytes([]byte("pw"), 2, 20)
+	assert.Nil(t, err, "splitting a short secret failed")
+	recovered, _, _ := dealer.CombineBytes(shares[18:])
+	assert.Equal(t, []byte("pw"), recovered)
+
+	// shares are encoded with a checksum
+	secret := []byte("root key of emmy deployment")
+	shares, _ = dealer.SplitBytes(secret, 2, 3)
+	data, err := common.MarshalPEM(shares[1])
+	assert.Nil(t, err, "encoding share failed")
+	var decoded secretsharing.ByteShare
+	assert.Nil(t, common.UnmarshalPEM(data, &decoded), "decoding share failed")
+	assert.Equal(t, shares[1], &decoded)
+	data, _ = json.Marshal(shares[1])
+	corrupted := strings.Replace(string(data), `"index":2`, `"index":3`, 1)
+	assert.NotNil(t, json.Unmarshal([]byte(corrupted), &decoded), "corrupted share should not decode")
+
+	// faulty shares are corrected and reported
+	forged := *shares[0]
+	forged.Values = []*big.Int{big.NewInt(1)}
+	_, _, err = dealer.CombineBytes([]*secretsharing.ByteShare{&forged, shares[1]})
+	assert.NotNil(t, err, "shares with different number of chunks should not be combined")
+	more, _ := dealer.SplitBytes(secret, 2, 5)
+	forged = *more[1]
+	forged.Values = []*big.Int{new(big.Int).Add(more[1].Values[0], big.NewInt(1))}
+	more[1] = &forged
+	recovered, faulty, err := dealer.CombineBytes(more)
+	assert.Nil(t, err, "combining shares failed")
+	assert.Equal(t, secret, recovered)
+	assert.Equal(t, []int{2}, faulty, "faulty share should be reported")
+
+	// shares of different secrets are not combined
+	other, _ := dealer.SplitBytes(secret, 2, 3)
+	_, _, err = dealer.CombineBytes([]*secretsharing.ByteShare{shares[0], other[1]})
+	assert.NotNil(t, err, "shares of different secrets should not be combined")
+}
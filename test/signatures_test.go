@@ -1,10 +1,14 @@
 package tests
 
 import (
+	"github.com/stretchr/testify/assert"
 	"github.com/xlab-si/emmy/common"
 	"github.com/xlab-si/emmy/signatures"
+	"io/ioutil"
 	"log"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -30,3 +34,28 @@ func TestCL(t *testing.T) {
 	ok, _ := pubCL.Verify(m_Ls, signature)
 	log.Println(ok)
 }
+
+func TestCL_StoreAndLoadKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	secKeyPath := filepath.Join(dir, "clseckey.txt")
+	pubKeyPath := filepath.Join(dir, "clpubkey.txt")
+
+	cl := signatures.NewCL(1)
+	assert.Nil(t, cl.StoreSecKey(secKeyPath), "storing secret key failed")
+	assert.Nil(t, cl.StorePubKey(pubKeyPath), "storing public key failed")
+
+	secCL, err := signatures.NewCLFromSecKey(secKeyPath)
+	assert.Nil(t, err, "loading secret key failed")
+	pubCL, err := signatures.NewCLFromPubKeyFile(pubKeyPath)
+	assert.Nil(t, err, "loading public key failed")
+
+	m_Ls := []*big.Int{common.GetRandomInt(big.NewInt(1234567))}
+	signature, err := secCL.Sign(m_Ls)
+	assert.Nil(t, err, "signing with loaded secret key failed")
+	ok, _ := pubCL.Verify(m_Ls, signature)
+	assert.True(t, ok, "CL signature does not verify with loaded public key")
+}
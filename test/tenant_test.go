@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/server"
+	"github.com/xlab-si/emmy/transport"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTenants(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	// the tenant's server only verifies encryptions, so it doesn't get the secret key
+	cspaillier := encryption.NewCSPaillier(&secParams)
+	pubKeyPath := filepath.Join(dir, "cspaillierpubkey.txt")
+	assert.Nil(t, cspaillier.StorePubKey(pubKeyPath))
+
+	tokenHash := sha256.Sum256([]byte("unit1 token"))
+	config.Set("tenants", map[string]interface{}{
+		"unit1": map[string]interface{}{
+			"key_folder":   dir,
+			"protocols":    []string{"schnorr", "cspaillier"},
+			"token_sha256": []string{hex.EncodeToString(tokenHash[:])},
+		},
+	})
+	defer config.Set("tenants", map[string]interface{}{})
+
+	s := server.NewProtocolServer()
+	lis, err := net.Listen("tcp", ":7009")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterProtocolServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.GracefulStop()
+	newTransport := func() (transport.Transport, error) {
+		return transport.DialGRPC("localhost:7009")
+	}
+
+	// authentication of HTTP clients
+	req := httptest.NewRequest("POST", "/sessions", nil)
+	tenant, err := s.AuthenticateHTTP(req)
+	assert.Nil(t, err)
+	assert.Equal(t, server.DefaultTenantId, tenant.Id, "client without token should be served by the default tenant")
+	req.Header.Set("Authorization", "Bearer unit1 token")
+	tenant, err = s.AuthenticateHTTP(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "unit1", tenant.Id)
+	req.Header.Set("Authorization", "Bearer wrong token")
+	_, err = s.AuthenticateHTTP(req)
+	assert.Equal(t, server.ErrInvalidToken, err)
+
+	// sessions of a tenant are subject to its policy and use its keys
+	config.Set("tenant_token", "unit1 token")
+	defer config.Set("tenant_token", "")
+	n := big.NewInt(345345345334)
+	m := common.GetRandomInt(big.NewInt(8685849))
+	l := common.GetRandomInt(big.NewInt(340002223232))
+	assert.Nil(t, testSchnorr(newTransport, n, pb.SchemaVariant_SIGMA), "tenant should be allowed to run schnorr")
+	assert.NotNil(t, testPedersen(newTransport, n), "tenant should not be allowed to run pedersen")
+	assert.Nil(t, testCSPaillier(newTransport, m, l, pubKeyPath), "tenant's CSPaillier public key should be used")
+
+	config.Set("tenant_token", "wrong token")
+	assert.NotNil(t, testSchnorr(newTransport, n, pb.SchemaVariant_SIGMA), "client with invalid token should be rejected")
+}
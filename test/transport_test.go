@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/server"
+	"github.com/xlab-si/emmy/transport"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newPipeTransport runs emmy server in the same process, connected to the client
+// with an in-memory pipe.
+func newPipeTransport() (transport.Transport, error) {
+	clientEnd, serverEnd := transport.NewPipe()
+	go func() {
+		defer serverEnd.Close()
+		server.NewProtocolServer().Serve(serverEnd)
+	}()
+	return clientEnd, nil
+}
+
+func TestInMemory_Protocols(t *testing.T) {
+	n := big.NewInt(345345345334)
+	desc := "should finish without errors"
+
+	assert.Nil(t, testPedersen(newPipeTransport, n), desc)
+	assert.Nil(t, testPedersenEC(newPipeTransport, n), desc)
+	assert.Nil(t, testSchnorr(newPipeTransport, n, pb.SchemaVariant_SIGMA), desc)
+	assert.Nil(t, testSchnorr(newPipeTransport, n, pb.SchemaVariant_ZKP), desc)
+	assert.Nil(t, testSchnorr(newPipeTransport, n, pb.SchemaVariant_ZKPOK), desc)
+	assert.Nil(t, testSchnorrEC(newPipeTransport, n, pb.SchemaVariant_SIGMA), desc)
+	assert.Nil(t, testSchnorrEC(newPipeTransport, n, pb.SchemaVariant_ZKP), desc)
+	assert.Nil(t, testSchnorrEC(newPipeTransport, n, pb.SchemaVariant_ZKPOK), desc)
+}
+
+func TestWebSocket_Protocols(t *testing.T) {
+	s := httptest.NewServer(server.NewProtocolServer().WebSocketHandler())
+	defer s.Close()
+
+	newWebSocketTransport := func() (transport.Transport, error) {
+		return transport.DialWebSocket(strings.Replace(s.URL, "http", "ws", 1), s.URL)
+	}
+	n := big.NewInt(345345345334)
+	desc := "should finish without errors"
+
+	assert.Nil(t, testPedersen(newWebSocketTransport, n), desc)
+	assert.Nil(t, testSchnorr(newWebSocketTransport, n, pb.SchemaVariant_ZKPOK), desc)
+	assert.Nil(t, testSchnorrEC(newWebSocketTransport, n, pb.SchemaVariant_SIGMA), desc)
+}
@@ -0,0 +1,316 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/xlab-si/emmy/client"
+	"github.com/xlab-si/emmy/common"
+	"github.com/xlab-si/emmy/config"
+	"github.com/xlab-si/emmy/dlog"
+	"github.com/xlab-si/emmy/encryption"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"github.com/xlab-si/emmy/server"
+	"github.com/xlab-si/emmy/transport"
+	"github.com/xlab-si/emmy/trustee"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrustee(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emmy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secParams := encryption.CSPaillierSecParams{
+		L:        512,
+		RoLength: 160,
+		K:        158,
+		K1:       158,
+	}
+	keys := server.NewKeyStore(dir)
+	assert.Nil(t, keys.Generate("", &secParams))
+	pubKey, _ := keys.GetPubKey("")
+	cspaillier := encryption.NewCSPaillierFromPubKey(pubKey)
+
+	logPath := filepath.Join(dir, "audit.log")
+	auditLog, err := trustee.OpenAuditLog(logPath)
+	assert.Nil(t, err, "opening audit log failed")
+	tr := trustee.New(keys, trustee.NewLabelPolicy(map[string][]string{"case-42": {"investigator"}}), auditLog)
+
+	m := common.GetRandomInt(big.NewInt(8685849))
+	label := trustee.Label("case-42")
+	u, e, v, _ := cspaillier.Encrypt(m, label)
+	req := &trustee.Request{
+		Requester:     "investigator",
+		U:             u,
+		E:             e,
+		V:             v,
+		Label:         label,
+		Justification: "court order 17/2017",
+	}
+	p, proof, err := tr.Decrypt(req)
+	assert.Nil(t, err, "authorized decryption failed")
+	assert.Equal(t, m, p)
+	assert.True(t, cspaillier.VerifyDecryption(u, e, v, label, p, proof), "proof of decryption should verify")
+	assert.False(t, cspaillier.VerifyDecryption(u, e, v, label, new(big.Int).Add(p, big.NewInt(1)), proof),
+		"proof should not verify for a different plaintext")
+
+	// ciphertexts with other labels and requests without justification are refused
+	otherLabel := trustee.Label("case-43")
+	u, e, v, _ = cspaillier.Encrypt(m, otherLabel)
+	_, _, err = tr.Decrypt(&trustee.Request{Requester: "investigator", U: u, E: e, V: v, Label: otherLabel,
+		Justification: "curiosity"})
+	assert.NotNil(t, err, "decryption of an unauthorized label should be denied")
+	req.Justification = ""
+	_, _, err = tr.Decrypt(req)
+	assert.NotNil(t, err, "decryption without justification should be denied")
+
+	// only authenticated requesters listed with the label may request decryption
+	req.Justification = "court order 17/2017"
+	req.Requester = ""
+	_, _, err = tr.Decrypt(req)
+	assert.NotNil(t, err, "decryption for an unauthenticated requester should be denied")
+	req.Requester = "journalist"
+	_, _, err = tr.Decrypt(req)
+	assert.NotNil(t, err, "decryption for an unauthorized requester should be denied")
+
+	// all requests are recorded
+	entries, err := trustee.VerifyAuditLog(logPath)
+	assert.Nil(t, err, "audit log should verify")
+	if assert.Len(t, entries, 5) {
+		assert.Equal(t, trustee.OutcomeDecrypted, entries[0].Outcome)
+		assert.Equal(t, "investigator", entries[0].Requester)
+		for _, entry := range entries[1:] {
+			assert.Equal(t, trustee.OutcomeDenied, entry.Outcome)
+		}
+		assert.Equal(t, entries[4].Hash, auditLog.Head())
+	}
+	assert.Nil(t, auditLog.Close())
+
+	// modified entries are detected
+	data, _ := ioutil.ReadFile(logPath)
+	data = []byte(strings.Replace(string(data), "court order 17/2017", "court order 18/2017", 1))
+	assert.Nil(t, ioutil.WriteFile(logPath, data, 0600))
+	_, err = trustee.VerifyAuditLog(logPath)
+	assert.NotNil(t, err, "modified audit log should not verify")
+	_, err = trustee.OpenAuditLog(logPath)
+	assert.NotNil(t, err, "modified audit log should not be opened")
+}
+
+// authenticateAsTenant configures tenant unit1, which uses the global keys, and makes
+// clients authenticate as unit1. It returns the function restoring the configuration.
+func authenticateAsTenant() func() {
+	tokenHash := sha256.Sum256([]byte("unit1 token"))
+	config.Set("tenants", map[string]interface{}{
+		"unit1": map[string]interface{}{
+			"key_folder":   config.LoadKeyDirFromConfig(),
+			"token_sha256": []string{hex.EncodeToString(tokenHash[:])},
+		},
+	})
+	config.Set("tenant_token", "unit1 token")
+	return func() {
+		config.Set("tenants", map[string]interface{}{})
+		config.Set("tenant_token", "")
+	}
+}
+
+// trusteeLabel configures label as the only label of ciphertexts under the global keys
+// that the trustee may decrypt, on request of the requester, with the hashes of the
+// threshold ciphertexts registered for it.
+func trusteeLabel(label, requester string, ciphertexts ...string) {
+	config.Set("trustee.labels", []interface{}{
+		map[string]interface{}{
+			"label":       label,
+			"requesters":  []string{requester},
+			"ciphertexts": ciphertexts,
+		},
+	})
+}
+
+func TestGRPC_TrusteeDecryption(t *testing.T) {
+	// labels are case sensitive
+	trusteeLabel("Case-42", "unit1")
+	defer config.Set("trustee.labels", []interface{}{})
+	defer authenticateAsTenant()()
+
+	s := server.NewProtocolServer()
+	lis, err := net.Listen("tcp", ":7010")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterProtocolServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.GracefulStop()
+
+	tenant, _ := s.Tenant(server.DefaultTenantId)
+	pubKey, err := tenant.Keys().GetPubKey("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := common.GetRandomInt(big.NewInt(8685849))
+	decrypt := func(label *big.Int) (*big.Int, error) {
+		u, e, v, _ := encryption.NewCSPaillierFromPubKey(pubKey).Encrypt(m, label)
+		tr, err := transport.DialGRPC("localhost:7010")
+		if err != nil {
+			return nil, err
+		}
+		c := client.NewCSPaillierDecryptionClient(tr, pubKey, u, e, v, label, "court order 17/2017")
+		return c.Decrypt(context.Background())
+	}
+
+	p, err := decrypt(trustee.Label("Case-42"))
+	assert.Nil(t, err, "authorized decryption failed")
+	assert.Equal(t, m, p)
+	_, err = decrypt(trustee.Label("case-42"))
+	assert.NotNil(t, err, "decryption of a label differing in case should be denied")
+	_, err = decrypt(trustee.Label("case-43"))
+	assert.NotNil(t, err, "decryption of an unauthorized label should be denied")
+
+	// clients that don't authenticate cannot request decryption
+	config.Set("tenant_token", "")
+	_, err = decrypt(trustee.Label("Case-42"))
+	assert.NotNil(t, err, "decryption for an unauthenticated client should be denied")
+
+	entries, err := trustee.VerifyAuditLog(config.LoadTrusteeAuditLog())
+	assert.Nil(t, err, "audit log should verify")
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "unit1", entries[0].Requester)
+	}
+}
+
+func TestGRPC_TrusteeDecryptionShares(t *testing.T) {
+	pubKey, keyShares, err := encryption.NewThresholdPaillierKeys(256, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the server is the trustee holding the first key share
+	keySharePath := config.LoadTrusteeKeyShare()
+	assert.Nil(t, keyShares[0].Store(keySharePath, nil), "storing key share failed")
+	defer os.Remove(keySharePath)
+	defer authenticateAsTenant()()
+
+	// only the registered ciphertext may be decrypted in the case
+	paillier := encryption.NewPubPaillier(pubKey.GetPaillierPubKey())
+	m := common.GetRandomInt(pubKey.N)
+	c, _ := paillier.Encrypt(m)
+	trusteeLabel("Case-42", "unit1", trustee.CiphertextHash(c))
+	defer config.Set("trustee.labels", []interface{}{})
+
+	s := server.NewProtocolServer()
+	lis, err := net.Listen("tcp", ":7011")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterProtocolServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.GracefulStop()
+
+	getShare := func(caseId string, c *big.Int) (*encryption.PaillierDecryptionShare, error) {
+		tr, err := transport.DialGRPC("localhost:7011")
+		if err != nil {
+			return nil, err
+		}
+		shareClient := client.NewPaillierDecryptionShareClient(tr, pubKey, c, trustee.Label(caseId),
+			"court order 17/2017")
+		return shareClient.GetShare(context.Background())
+	}
+
+	share, err := getShare("Case-42", c)
+	assert.Nil(t, err, "authorized decryption share request failed")
+	otherShare, _ := keyShares[2].Decrypt(c)
+	p, err := pubKey.Combine(c, []*encryption.PaillierDecryptionShare{share, otherShare})
+	assert.Nil(t, err, "combining decryption shares failed")
+	assert.Equal(t, m, p)
+
+	_, err = getShare("case-43", c)
+	assert.NotNil(t, err, "decryption share request for an unauthorized case should be denied")
+	other, _ := paillier.Encrypt(m)
+	_, err = getShare("Case-42", other)
+	assert.NotNil(t, err, "decryption share request for an unregistered ciphertext should be denied")
+
+	entries, err := trustee.VerifyAuditLog(config.LoadTrusteeAuditLog())
+	assert.Nil(t, err, "audit log should verify")
+	if assert.True(t, len(entries) >= 3) {
+		assert.Equal(t, trustee.OutcomeShared, entries[len(entries)-3].Outcome)
+		assert.Equal(t, trustee.OutcomeDenied, entries[len(entries)-2].Outcome)
+		assert.Equal(t, trustee.OutcomeDenied, entries[len(entries)-1].Outcome)
+		assert.Equal(t, trustee.CiphertextHash(other), entries[len(entries)-1].Ciphertext)
+	}
+}
+
+func TestGRPC_TrusteeElGamalDecryptionShares(t *testing.T) {
+	group, err := dlog.NewZpSchnorr(160)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, keyShares, err := encryption.NewThresholdElGamalKeys(group, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the server is the trustee holding the second key share
+	keySharePath := config.LoadTrusteeElGamalKeyShare()
+	assert.Nil(t, keyShares[1].Store(keySharePath, nil), "storing key share failed")
+	defer os.Remove(keySharePath)
+	defer authenticateAsTenant()()
+
+	// only the tally of the ballots may be decrypted, not individual ballots
+	elgamal := encryption.NewPubElGamal(pubKey.GetElGamalPubKey())
+	ballot1, _ := elgamal.EncryptExponential(big.NewInt(1))
+	ballot2, _ := elgamal.EncryptExponential(big.NewInt(41))
+	tally := elgamal.Multiply(ballot1, ballot2)
+	trusteeLabel("Election-2017", "unit1", trustee.CiphertextHash(tally.C1, tally.C2))
+	defer config.Set("trustee.labels", []interface{}{})
+
+	s := server.NewProtocolServer()
+	lis, err := net.Listen("tcp", ":7012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterProtocolServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.GracefulStop()
+
+	getShare := func(caseId string, c *encryption.ElGamalCiphertext) (*encryption.ElGamalDecryptionShare,
+		error) {
+		tr, err := transport.DialGRPC("localhost:7012")
+		if err != nil {
+			return nil, err
+		}
+		shareClient := client.NewElGamalDecryptionShareClient(tr, pubKey, c, trustee.Label(caseId), "tally")
+		return shareClient.GetShare(context.Background())
+	}
+
+	share, err := getShare("Election-2017", tally)
+	assert.Nil(t, err, "authorized decryption share request failed")
+	otherShare, _ := keyShares[0].Decrypt(tally)
+	p, err := pubKey.CombineExponential(tally, []*encryption.ElGamalDecryptionShare{share, otherShare},
+		big.NewInt(100))
+	assert.Nil(t, err, "combining decryption shares failed")
+	assert.Equal(t, big.NewInt(42), p)
+
+	_, err = getShare("election-2018", tally)
+	assert.NotNil(t, err, "decryption share request for an unauthorized case should be denied")
+	_, err = getShare("Election-2017", ballot2)
+	assert.NotNil(t, err, "decryption share request for an individual ballot should be denied")
+
+	entries, err := trustee.VerifyAuditLog(config.LoadTrusteeAuditLog())
+	assert.Nil(t, err, "audit log should verify")
+	if assert.True(t, len(entries) >= 3) {
+		assert.Equal(t, trustee.OutcomeShared, entries[len(entries)-3].Outcome)
+		assert.Equal(t, trustee.OutcomeDenied, entries[len(entries)-2].Outcome)
+		assert.Equal(t, trustee.OutcomeDenied, entries[len(entries)-1].Outcome)
+	}
+}
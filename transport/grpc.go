@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"fmt"
+	"github.com/xlab-si/emmy/config"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"time"
+)
+
+// grpcServerTransport is the server side of a gRPC stream.
+type grpcServerTransport struct {
+	stream pb.Protocol_RunServer
+}
+
+// NewGRPCServerTransport returns a Transport using the given server side of a gRPC stream.
+func NewGRPCServerTransport(stream pb.Protocol_RunServer) Transport {
+	return &grpcServerTransport{stream: stream}
+}
+
+func (t *grpcServerTransport) Send(ctx context.Context, msg *pb.Message) error {
+	return doWithContext(ctx, func() error {
+		return t.stream.Send(msg)
+	}, nil)
+}
+
+func (t *grpcServerTransport) Receive(ctx context.Context) (*pb.Message, error) {
+	var msg *pb.Message
+	err := doWithContext(ctx, func() error {
+		var err error
+		msg, err = t.stream.Recv()
+		return err
+	}, nil)
+	return msg, err
+}
+
+// Close is a no-op, as the server side of the stream is closed when the RPC handler returns.
+func (t *grpcServerTransport) Close() error {
+	return nil
+}
+
+// grpcClientTransport is the client side of a gRPC stream.
+type grpcClientTransport struct {
+	stream pb.Protocol_RunClient
+	cancel context.CancelFunc
+	conn   *grpc.ClientConn // only set when the connection is owned by the transport
+}
+
+// ConnectGRPC establishes a gRPC connection to emmy server at the given endpoint,
+// giving up after the timeout specified in the config.
+func ConnectGRPC(endpoint string) (*grpc.ClientConn, error) {
+	timeoutSec := config.LoadTimeout()
+	dialOptions := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithTimeout(time.Duration(timeoutSec) * time.Second),
+	}
+	conn, err := grpc.Dial(endpoint, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to server %v (%v)", endpoint, err)
+	}
+	return conn, nil
+}
+
+// DialGRPC connects to emmy server at the given endpoint and returns a Transport
+// using a new gRPC stream. The connection is closed together with the transport.
+func DialGRPC(endpoint string) (Transport, error) {
+	conn, err := ConnectGRPC(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := newGRPCClientTransport(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	t.conn = conn
+	return t, nil
+}
+
+// NewGRPCClientTransport returns a Transport using a new gRPC stream on an existing
+// connection to emmy server. Closing the transport does not close the connection.
+func NewGRPCClientTransport(conn *grpc.ClientConn) (Transport, error) {
+	return newGRPCClientTransport(conn)
+}
+
+func newGRPCClientTransport(conn *grpc.ClientConn) (*grpcClientTransport, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if token := config.LoadTenantToken(); token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	stream, err := pb.NewProtocolClient(conn).Run(ctx)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("Error creating the stream: %v", err)
+	}
+
+	return &grpcClientTransport{
+		stream: stream,
+		cancel: cancel,
+	}, nil
+}
+
+// Send sends msg to emmy server. If ctx is done before the message is sent,
+// the stream is aborted.
+func (t *grpcClientTransport) Send(ctx context.Context, msg *pb.Message) error {
+	return doWithContext(ctx, func() error {
+		return t.stream.Send(msg)
+	}, t.cancel)
+}
+
+// Receive waits for the next message from emmy server. If ctx is done before the
+// message arrives, the stream is aborted.
+func (t *grpcClientTransport) Receive(ctx context.Context) (*pb.Message, error) {
+	var msg *pb.Message
+	err := doWithContext(ctx, func() error {
+		var err error
+		msg, err = t.stream.Recv()
+		return err
+	}, t.cancel)
+	return msg, err
+}
+
+// Close closes the stream and, if owned by the transport, the connection to emmy server.
+func (t *grpcClientTransport) Close() error {
+	defer t.cancel()
+	if err := t.stream.CloseSend(); err != nil {
+		return fmt.Errorf("Error closing stream: %v", err)
+	}
+	if t.conn != nil {
+		if err := t.conn.Close(); err != nil {
+			return fmt.Errorf("Error closing connection: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"fmt"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"golang.org/x/net/context"
+	"io"
+	"sync"
+)
+
+// pipeEnd is one end of an in-memory pipe.
+type pipeEnd struct {
+	in         <-chan *pb.Message
+	out        chan<- *pb.Message
+	closed     chan struct{}
+	peerClosed <-chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewPipe returns two connected in-memory transports. Messages sent to one of them
+// are received from the other one. It is useful for running a client and a server
+// within the same process, for example in tests.
+func NewPipe() (Transport, Transport) {
+	ab := make(chan *pb.Message)
+	ba := make(chan *pb.Message)
+	aClosed := make(chan struct{})
+	bClosed := make(chan struct{})
+
+	a := &pipeEnd{in: ba, out: ab, closed: aClosed, peerClosed: bClosed}
+	b := &pipeEnd{in: ab, out: ba, closed: bClosed, peerClosed: aClosed}
+	return a, b
+}
+
+func (p *pipeEnd) Send(ctx context.Context, msg *pb.Message) error {
+	select {
+	case p.out <- msg:
+		return nil
+	case <-p.closed:
+		return fmt.Errorf("Error sending message: transport is closed")
+	case <-p.peerClosed:
+		return io.ErrClosedPipe
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *pipeEnd) Receive(ctx context.Context) (*pb.Message, error) {
+	select {
+	case msg := <-p.in:
+		return msg, nil
+	case <-p.closed:
+		return nil, fmt.Errorf("Error receiving message: transport is closed")
+	case <-p.peerClosed:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *pipeEnd) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	return nil
+}
@@ -0,0 +1,45 @@
+// Package transport provides the means of exchanging protocol messages between emmy
+// clients and emmy server. Protocol implementations only depend on the Transport
+// interface, so that the same protocol can be run over gRPC, WebSockets or in memory.
+package transport
+
+import (
+	pb "github.com/xlab-si/emmy/protobuf"
+	"golang.org/x/net/context"
+)
+
+// Transport is a bidirectional channel for exchanging protocol messages with the
+// other party of the protocol.
+type Transport interface {
+	// Send sends msg to the other party.
+	Send(ctx context.Context, msg *pb.Message) error
+	// Receive waits for the next message from the other party. It returns io.EOF
+	// once the other party closed the transport.
+	Receive(ctx context.Context) (*pb.Message, error)
+	// Close releases the resources held by the transport and signals the other party
+	// that no more messages will be sent.
+	Close() error
+}
+
+// doWithContext calls f and waits for it to finish, unless ctx is done before that.
+// In that case abort (if not nil) is called to unblock f, and ctx.Err() is returned.
+func doWithContext(ctx context.Context, f func() error, abort func()) error {
+	if ctx.Done() == nil {
+		return f()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		if abort != nil {
+			abort()
+		}
+		return ctx.Err()
+	}
+}
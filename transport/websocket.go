@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/xlab-si/emmy/config"
+	pb "github.com/xlab-si/emmy/protobuf"
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+)
+
+// webSocketTransport exchanges protocol messages as binary WebSocket frames, each
+// holding a single protobuf encoded message.
+type webSocketTransport struct {
+	conn *websocket.Conn
+}
+
+// NewWebSocketTransport returns a Transport using the given WebSocket connection.
+func NewWebSocketTransport(conn *websocket.Conn) Transport {
+	return &webSocketTransport{conn: conn}
+}
+
+// DialWebSocket connects to the WebSocket endpoint of emmy server at url
+// (for example ws://localhost:8080/ws) and returns a Transport using the connection.
+func DialWebSocket(url, origin string) (Transport, error) {
+	wsConfig, err := websocket.NewConfig(url, origin)
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to server %v (%v)", url, err)
+	}
+	if token := config.LoadTenantToken(); token != "" {
+		wsConfig.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	conn, err := websocket.DialConfig(wsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to server %v (%v)", url, err)
+	}
+	return NewWebSocketTransport(conn), nil
+}
+
+// Send sends msg to the other party. If ctx is done before the message is sent,
+// the connection is closed.
+func (t *webSocketTransport) Send(ctx context.Context, msg *pb.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("Error encoding message: %v", err)
+	}
+
+	return doWithContext(ctx, func() error {
+		return websocket.Message.Send(t.conn, data)
+	}, t.abort)
+}
+
+// Receive waits for the next message from the other party. If ctx is done before
+// the message arrives, the connection is closed.
+func (t *webSocketTransport) Receive(ctx context.Context) (*pb.Message, error) {
+	var data []byte
+	err := doWithContext(ctx, func() error {
+		return websocket.Message.Receive(t.conn, &data)
+	}, t.abort)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &pb.Message{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("Error decoding message: %v", err)
+	}
+	return msg, nil
+}
+
+func (t *webSocketTransport) Close() error {
+	return t.conn.Close()
+}
+
+func (t *webSocketTransport) abort() {
+	t.conn.Close()
+}
@@ -0,0 +1,168 @@
+package trustee
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outcomes of decryption requests, as recorded in the audit log.
+const (
+	OutcomeDecrypted = "decrypted"
+	OutcomeShared    = "decryption share issued"
+	OutcomeDenied    = "denied"
+	OutcomeFailed    = "failed"
+)
+
+// AuditEntry records a single decryption request. Plaintexts are never recorded.
+type AuditEntry struct {
+	Seq           uint64    `json:"seq"`
+	Time          time.Time `json:"time"`
+	Requester     string    `json:"requester"`
+	KeyId         string    `json:"key_id"`
+	Label         string    `json:"label"`
+	Ciphertext    string    `json:"ciphertext"` // hex encoded SHA-256 hash of the ciphertext
+	Justification string    `json:"justification"`
+	Outcome       string    `json:"outcome"`
+	Reason        string    `json:"reason,omitempty"`
+	// Prev is the hash of the previous entry (empty for the first entry), and Hash is
+	// the hex encoded SHA-256 hash of the entry itself, computed with Hash left empty.
+	Prev string `json:"prev"`
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of the entry, computed with Hash left empty.
+func (e AuditEntry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// AuditLog is an append-only log of decryption requests, stored as a file with one
+// JSON encoded AuditEntry per line. Entries are chained by hashes: each entry holds
+// the hash of the previous one, so modifying, reordering or removing entries is
+// detected by VerifyAuditLog. Removing entries from the end of the log is detected
+// only by comparing Head with a previously recorded value, so the head should be
+// anchored elsewhere from time to time (for example in an external system).
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+	head string
+}
+
+// OpenAuditLog opens the audit log at the given path, creating it if it doesn't exist.
+// The entries already in the log are verified first, and a log that was tampered with
+// is not opened.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	entries, err := VerifyAuditLog(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open audit log: %v", err)
+	}
+
+	log := &AuditLog{file: file}
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		log.seq = last.Seq
+		log.head = last.Hash
+	}
+	return log, nil
+}
+
+// Append completes the entry with its sequence number, the hash of the previous entry
+// and its own hash, and appends it to the log. The entry is synced to disk before
+// Append returns.
+func (l *AuditLog) Append(entry *AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.seq + 1
+	entry.Prev = l.head
+	hash, err := entry.computeHash()
+	if err != nil {
+		return fmt.Errorf("Cannot hash audit log entry: %v", err)
+	}
+	entry.Hash = hash
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("Cannot encode audit log entry: %v", err)
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("Cannot write audit log entry: %v", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("Cannot write audit log entry: %v", err)
+	}
+
+	l.seq = entry.Seq
+	l.head = entry.Hash
+	return nil
+}
+
+// Head returns the hash of the last entry in the log, which commits to the whole log.
+func (l *AuditLog) Head() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.head
+}
+
+// Close closes the log file.
+func (l *AuditLog) Close() error {
+	return l.file.Close()
+}
+
+// VerifyAuditLog reads the audit log at the given path and verifies that its entries
+// are numbered consecutively and correctly chained by their hashes. It returns the
+// entries of the log, or an error describing the first entry that fails verification.
+func VerifyAuditLog(path string) ([]*AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []*AuditEntry
+	prev := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		entry := new(AuditEntry)
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(entry); err != nil {
+			return nil, fmt.Errorf("Audit log %v, line %v: cannot decode entry: %v", path, line, err)
+		}
+		if entry.Seq != uint64(line) {
+			return nil, fmt.Errorf("Audit log %v, line %v: entry has sequence number %v", path, line, entry.Seq)
+		}
+		if entry.Prev != prev {
+			return nil, fmt.Errorf("Audit log %v, line %v: entry is not chained to the previous one", path, line)
+		}
+		hash, err := entry.computeHash()
+		if err != nil || hash != entry.Hash {
+			return nil, fmt.Errorf("Audit log %v, line %v: entry was modified", path, line)
+		}
+		entries = append(entries, entry)
+		prev = entry.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Cannot read audit log %v: %v", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,329 @@
+// Package trustee implements a trustee that opens escrowed CSPaillier ciphertexts
+// under a controlled procedure, for example for revocation of anonymity.
+//
+// Values are escrowed by encrypting them under the trustee's CSPaillier key, with a
+// label that ties the ciphertext to the context in which it may be opened, for example
+// a case ID (see Label). The trustee decrypts a ciphertext only if its Policy
+// authorizes the label, records every decryption request (granted or not) in a
+// tamper-evident AuditLog before answering it, and accompanies every plaintext with
+// a proof of correct decryption, which anybody holding the public key can verify
+// with encryption.CSPaillier.VerifyDecryption.
+//
+// A trustee can also hold a share of a threshold Paillier key, so that no single
+// trustee can decrypt: it then issues decryption shares (see DecryptShare) under the
+// same policy and audit, but only of the ciphertexts registered for the case, and
+// threshold decryption shares are combined into the plaintext with
+// encryption.ThresholdPaillierPubKey.Combine. Likewise, it can hold a
+// share of a threshold ElGamal key (see DecryptElGamalShare), for example as one of the
+// authorities tallying votes.
+package trustee
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/xlab-si/emmy/encryption"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Label returns the CSPaillier label that ties a ciphertext to the given case ID.
+func Label(caseId string) *big.Int {
+	return new(big.Int).SetBytes([]byte(caseId))
+}
+
+// CiphertextHash returns the hex encoded SHA-256 hash of the ciphertext consisting of the
+// given numbers, such as (u, e, v) of a CSPaillier ciphertext, c of a Paillier ciphertext
+// or (c1, c2) of an ElGamal ciphertext, which identifies the ciphertext in the audit log and in the registry of
+// ciphertexts whose decryption shares may be issued (see SetShareCiphertexts).
+func CiphertextHash(numbers ...*big.Int) string {
+	h := sha256.New()
+	for _, n := range numbers {
+		b := n.Bytes()
+		length := make([]byte, 4)
+		binary.BigEndian.PutUint32(length, uint32(len(b)))
+		h.Write(length)
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Request is a request to decrypt a CSPaillier ciphertext (u, e, v) with the given label.
+type Request struct {
+	// Requester is the authenticated identity of the party asking for decryption.
+	Requester string
+	// KeyId is the ID of the trustee's key that the ciphertext was encrypted under.
+	KeyId string
+	U     *big.Int
+	E     *big.Int
+	V     *big.Int
+	Label *big.Int
+	// Justification explains why decryption is requested. It is recorded in the audit log.
+	Justification string
+}
+
+// ShareRequest is a request for a decryption share of threshold Paillier ciphertext C.
+// Unlike CSPaillier labels, Label is not bound to the ciphertext; it states the case
+// (see Label) on behalf of which decryption is requested, which is authorized by the
+// policy and recorded in the audit log. Decryption shares are only issued for the
+// ciphertexts registered for the case (see SetShareCiphertexts).
+type ShareRequest struct {
+	// Requester is the authenticated identity of the party asking for decryption.
+	Requester string
+	C         *big.Int
+	Label     *big.Int
+	// Justification explains why decryption is requested. It is recorded in the audit log.
+	Justification string
+}
+
+// ElGamalShareRequest is a request for a decryption share of threshold ElGamal
+// ciphertext C. As in ShareRequest, Label is not bound to the ciphertext, so decryption
+// shares are only issued for the ciphertexts registered for the case, such as the
+// product of the ballots of an election, but not individual ballots.
+type ElGamalShareRequest struct {
+	// Requester is the authenticated identity of the party asking for decryption.
+	Requester string
+	C         *encryption.ElGamalCiphertext
+	Label     *big.Int
+	// Justification explains why decryption is requested. It is recorded in the audit log.
+	Justification string
+}
+
+// Policy decides whether ciphertexts may be decrypted.
+type Policy interface {
+	// Authorize returns an error if the request must not be granted. For requests for
+	// decryption shares (see ShareRequest and ElGamalShareRequest), only Requester, Label and Justification are set.
+	Authorize(req *Request) error
+}
+
+// LabelPolicy authorizes decryption of ciphertexts with the listed labels, given as
+// case IDs (see Label), for the requesters listed with each label. Requests must be
+// made by an authenticated requester and state a justification.
+type LabelPolicy map[string]map[string]bool
+
+// NewLabelPolicy returns the policy authorizing the requesters listed for each case ID
+// to decrypt ciphertexts with the label of the case ID.
+func NewLabelPolicy(requesters map[string][]string) LabelPolicy {
+	policy := make(LabelPolicy, len(requesters))
+	for id, names := range requesters {
+		policy[id] = make(map[string]bool, len(names))
+		for _, name := range names {
+			policy[id][name] = true
+		}
+	}
+	return policy
+}
+
+func (p LabelPolicy) Authorize(req *Request) error {
+	if req.Requester == "" {
+		return errors.New("decryption request is not authenticated")
+	}
+	if req.Justification == "" {
+		return errors.New("decryption request states no justification")
+	}
+	requesters, ok := p[string(req.Label.Bytes())]
+	if !ok {
+		return fmt.Errorf("label %q is not authorized for decryption", req.Label.Bytes())
+	}
+	if !requesters[req.Requester] {
+		return fmt.Errorf("%q may not request decryption of label %q", req.Requester, req.Label.Bytes())
+	}
+	return nil
+}
+
+// KeySource provides trustee's secret keys by key ID, for example server.KeyStore.
+type KeySource interface {
+	Get(keyId string) (*encryption.CSPaillierSecretKey, error)
+}
+
+// Trustee decrypts CSPaillier ciphertexts authorized by its policy.
+type Trustee struct {
+	keys     KeySource
+	keyShare *encryption.ThresholdPaillierKeyShare // nil if the trustee holds no key share
+	// shareCiphertexts holds, for each case ID, the hashes of ciphertexts whose
+	// decryption shares may be issued for the case
+	shareCiphertexts map[string]map[string]bool
+	// elGamalKeyShare is nil if the trustee holds no threshold ElGamal key share
+	elGamalKeyShare *encryption.ThresholdElGamalKeyShare
+	policy          Policy
+	log             *AuditLog
+}
+
+// New returns a trustee using the given keys, decrypting ciphertexts authorized by
+// policy and recording decryption requests in log.
+func New(keys KeySource, policy Policy, log *AuditLog) *Trustee {
+	return &Trustee{
+		keys:   keys,
+		policy: policy,
+		log:    log,
+	}
+}
+
+// Decrypt decrypts the ciphertext from the request, if the request is authorized by
+// trustee's policy, and returns the plaintext together with a proof of correct
+// decryption. The request is recorded in the audit log in any case; if it cannot be
+// recorded, the plaintext is not returned.
+func (t *Trustee) Decrypt(req *Request) (*big.Int, *encryption.CSPaillierDecryptionProof, error) {
+	entry := &AuditEntry{
+		Time:          time.Now().UTC(),
+		Requester:     req.Requester,
+		KeyId:         req.KeyId,
+		Justification: req.Justification,
+	}
+	if req.U == nil || req.E == nil || req.V == nil || req.Label == nil {
+		return nil, nil, t.record(entry, OutcomeDenied, errors.New("incomplete ciphertext"))
+	}
+	entry.Label = hex.EncodeToString(req.Label.Bytes())
+	entry.Ciphertext = CiphertextHash(req.U, req.E, req.V)
+
+	if err := t.policy.Authorize(req); err != nil {
+		return nil, nil, t.record(entry, OutcomeDenied, err)
+	}
+
+	secKey, err := t.keys.Get(req.KeyId)
+	if err != nil {
+		return nil, nil, t.record(entry, OutcomeFailed, err)
+	}
+	decryptor := encryption.NewCSPaillierFromSecretKey(secKey)
+	m, proof, err := decryptor.DecryptWithProof(req.U, req.E, req.V, req.Label)
+	if err != nil {
+		return nil, nil, t.record(entry, OutcomeFailed, err)
+	}
+
+	if err := t.record(entry, OutcomeDecrypted, nil); err != nil {
+		return nil, nil, err
+	}
+	return m, proof, nil
+}
+
+// SetKeyShare sets the threshold Paillier key share used by DecryptShare.
+func (t *Trustee) SetKeyShare(keyShare *encryption.ThresholdPaillierKeyShare) {
+	t.keyShare = keyShare
+}
+
+// SetShareCiphertexts sets the ciphertexts whose decryption shares may be issued: for
+// each case ID, the hashes (see CiphertextHash) of the ciphertexts that may be decrypted
+// in the case. As labels of threshold ciphertexts are not bound to them, this prevents
+// requesters authorized for a case from obtaining decryption shares of other ciphertexts.
+func (t *Trustee) SetShareCiphertexts(ciphertexts map[string][]string) {
+	t.shareCiphertexts = make(map[string]map[string]bool, len(ciphertexts))
+	for caseId, hashes := range ciphertexts {
+		t.shareCiphertexts[caseId] = make(map[string]bool, len(hashes))
+		for _, hash := range hashes {
+			t.shareCiphertexts[caseId][strings.ToLower(hash)] = true
+		}
+	}
+}
+
+// DecryptShare returns trustee's decryption share of the ciphertext from the request,
+// if the request is authorized by trustee's policy, comes from an authenticated
+// requester and the ciphertext is registered for the case (see SetShareCiphertexts). As with Decrypt, the request is
+// recorded in the audit log in any case, and the decryption share is not returned if
+// it cannot be recorded.
+func (t *Trustee) DecryptShare(req *ShareRequest) (*encryption.PaillierDecryptionShare, error) {
+	entry := &AuditEntry{
+		Time:          time.Now().UTC(),
+		Requester:     req.Requester,
+		Justification: req.Justification,
+	}
+	if req.C == nil || req.Label == nil {
+		return nil, t.record(entry, OutcomeDenied, errors.New("incomplete ciphertext"))
+	}
+	entry.Label = hex.EncodeToString(req.Label.Bytes())
+	entry.Ciphertext = CiphertextHash(req.C)
+
+	err := t.authorizeShare(&Request{
+		Requester:     req.Requester,
+		Label:         req.Label,
+		Justification: req.Justification,
+	}, entry.Ciphertext)
+	if err != nil {
+		return nil, t.record(entry, OutcomeDenied, err)
+	}
+
+	if t.keyShare == nil {
+		return nil, t.record(entry, OutcomeFailed, errors.New("trustee holds no key share"))
+	}
+	share, err := t.keyShare.Decrypt(req.C)
+	if err != nil {
+		return nil, t.record(entry, OutcomeFailed, err)
+	}
+
+	if err := t.record(entry, OutcomeShared, nil); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// SetElGamalKeyShare sets the threshold ElGamal key share used by DecryptElGamalShare.
+func (t *Trustee) SetElGamalKeyShare(keyShare *encryption.ThresholdElGamalKeyShare) {
+	t.elGamalKeyShare = keyShare
+}
+
+// DecryptElGamalShare returns trustee's decryption share of the threshold ElGamal
+// ciphertext from the request, under the same conditions as DecryptShare.
+func (t *Trustee) DecryptElGamalShare(req *ElGamalShareRequest) (*encryption.ElGamalDecryptionShare, error) {
+	entry := &AuditEntry{
+		Time:          time.Now().UTC(),
+		Requester:     req.Requester,
+		Justification: req.Justification,
+	}
+	if req.C == nil || req.C.C1 == nil || req.C.C2 == nil || req.Label == nil {
+		return nil, t.record(entry, OutcomeDenied, errors.New("incomplete ciphertext"))
+	}
+	entry.Label = hex.EncodeToString(req.Label.Bytes())
+	entry.Ciphertext = CiphertextHash(req.C.C1, req.C.C2)
+
+	err := t.authorizeShare(&Request{
+		Requester:     req.Requester,
+		Label:         req.Label,
+		Justification: req.Justification,
+	}, entry.Ciphertext)
+	if err != nil {
+		return nil, t.record(entry, OutcomeDenied, err)
+	}
+
+	if t.elGamalKeyShare == nil {
+		return nil, t.record(entry, OutcomeFailed, errors.New("trustee holds no ElGamal key share"))
+	}
+	share, err := t.elGamalKeyShare.Decrypt(req.C)
+	if err != nil {
+		return nil, t.record(entry, OutcomeFailed, err)
+	}
+
+	if err := t.record(entry, OutcomeShared, nil); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// authorizeShare returns an error if a decryption share of the ciphertext with the given
+// hash must not be issued for req.
+func (t *Trustee) authorizeShare(req *Request, ciphertextHash string) error {
+	if req.Requester == "" {
+		return errors.New("decryption share request is not authenticated")
+	}
+	if err := t.policy.Authorize(req); err != nil {
+		return err
+	}
+	if !t.shareCiphertexts[string(req.Label.Bytes())][ciphertextHash] {
+		return fmt.Errorf("ciphertext is not registered for decryption in case %q", req.Label.Bytes())
+	}
+	return nil
+}
+
+// record appends the entry with the given outcome to the audit log. It returns the
+// reason for the outcome, or the error that prevented recording it.
+func (t *Trustee) record(entry *AuditEntry, outcome string, reason error) error {
+	entry.Outcome = outcome
+	if reason != nil {
+		entry.Reason = reason.Error()
+	}
+	if err := t.log.Append(entry); err != nil {
+		return fmt.Errorf("Cannot record decryption request: %v", err)
+	}
+	return reason
+}